@@ -8,7 +8,10 @@ import (
 	"syscall"
 
 	"gabe565.com/gones/cmd/options"
+	"gabe565.com/gones/internal/archive"
+	"gabe565.com/gones/internal/cartridge"
 	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/nsf"
 	"gabe565.com/gones/internal/util"
 	"github.com/spf13/cobra"
 )
@@ -38,15 +41,31 @@ func runCobra(cmd *cobra.Command, args []string) error {
 	var path string
 	if len(args) > 0 {
 		path = args[0]
+	} else {
+		var err error
+		if path, err = selectROM(); err != nil {
+			return err
+		}
 	}
 
-	cart, err := loadCartridge(path)
+	name, err := archive.Resolve(path, func(name string) bool {
+		return cartridge.IsROMFile(name) || nsf.IsFile(name)
+	})
+	if err != nil {
+		return err
+	}
+
+	if nsf.IsFile(name) {
+		return runNSF(cmd, path)
+	}
+
+	cart, err := loadCartridge(cmd, path)
 	if err != nil {
 		return err
 	}
 
 	conf := config.NewDefault()
-	if err := conf.Load(cmd, cart.Name(), cart.Hash()); err != nil {
+	if err := conf.Load(cmd, cart.Name(), cart.Hash(), gameDefaults(cart)); err != nil {
 		return err
 	}
 
@@ -4,29 +4,50 @@ package gones
 
 import (
 	"log/slog"
+	"path/filepath"
 
+	"gabe565.com/gones/internal/archive"
 	"gabe565.com/gones/internal/cartridge"
 	"gabe565.com/gones/internal/config"
 	"gabe565.com/gones/internal/console"
+	"gabe565.com/gones/internal/patch"
+	"gabe565.com/utils/must"
 	"github.com/ncruces/zenity"
+	"github.com/spf13/cobra"
 )
 
-func loadCartridge(path string) (*cartridge.Cartridge, error) {
-	if path == "" {
-		var err error
-		if path, err = zenity.SelectFile(
-			zenity.Title("Choose a ROM file"),
-			zenity.FileFilter{
-				Name:     "NES ROM",
-				Patterns: []string{"*.nes"},
-				CaseFold: true,
-			},
-		); err != nil {
+func selectROM() (string, error) {
+	return zenity.SelectFile(
+		zenity.Title("Choose a ROM file"),
+		zenity.FileFilter{
+			Name:     "NES ROM",
+			Patterns: []string{"*.nes", "*.unf", "*.unif", "*.fds", "*.qd", "*.nsf", "*.nsfe", "*.zip", "*.gz"},
+			CaseFold: true,
+		},
+	)
+}
+
+func loadCartridge(cmd *cobra.Command, path string) (*cartridge.Cartridge, error) {
+	name, b, err := archive.ReadFile(path, cartridge.IsROMFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Patches are applied before the header is parsed, so they can change it
+	patchPath := must.Must2(cmd.Flags().GetString("patch"))
+	if patchPath == "" {
+		// Patches for a ROM inside an archive are named after the ROM, not the archive
+		archivePath, _ := archive.Split(path)
+		patchPath, _ = patch.Find(filepath.Join(filepath.Dir(archivePath), filepath.Base(name)))
+	}
+	if patchPath != "" {
+		if b, err = patch.ApplyFile(b, patchPath); err != nil {
 			return nil, err
 		}
+		slog.Info("Applied patch", "path", patchPath)
 	}
 
-	cart, err := cartridge.FromINESFile(path)
+	cart, err := cartridge.FromData(name, b)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +56,30 @@ func loadCartridge(path string) (*cartridge.Cartridge, error) {
 	return cart, nil
 }
 
+// gameDefaults returns config values for the cartridge's NES 2.0 default expansion device.
+// Standard controllers are not returned, so that they do not replace devices set in the main config.
+func gameDefaults(cart *cartridge.Cartridge) map[string]any {
+	switch cart.ExpansionDevice {
+	case cartridge.ExpansionFourScore:
+		return map[string]any{"input.multitap": string(config.MultiTapFourScore)}
+	case cartridge.ExpansionFamicomFourPlayer:
+		return map[string]any{"input.multitap": string(config.MultiTapFamicom)}
+	case cartridge.ExpansionZapper, cartridge.ExpansionVsZapper:
+		return map[string]any{"input.port2.type": string(config.DeviceZapper)}
+	case cartridge.ExpansionTwoZappers:
+		return map[string]any{
+			"input.port1.type": string(config.DeviceZapper),
+			"input.port2.type": string(config.DeviceZapper),
+		}
+	case cartridge.ExpansionPowerPadA, cartridge.ExpansionPowerPadB:
+		return map[string]any{"input.port2.type": string(config.DevicePowerPad)}
+	case cartridge.ExpansionArkanoid:
+		return map[string]any{"input.port2.type": string(config.DeviceArkanoid)}
+	default:
+		return nil
+	}
+}
+
 func newConsole(conf *config.Config, cart *cartridge.Cartridge) (*console.Console, error) {
 	return console.New(conf, cart)
 }
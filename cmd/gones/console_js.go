@@ -20,7 +20,13 @@ func loadCartridge() (*cartridge.Cartridge, error) {
 
 	r := bytes.NewReader(goData)
 
-	cart, err := cartridge.FromINES(r)
+	var cart *cartridge.Cartridge
+	var err error
+	if bytes.HasPrefix(goData, []byte("UNIF")) {
+		cart, err = cartridge.FromUNIF(r)
+	} else {
+		cart, err = cartridge.FromINES(r)
+	}
 	if err != nil {
 		return nil, err
 	}
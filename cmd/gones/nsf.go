@@ -0,0 +1,67 @@
+//go:build !js
+
+package gones
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/nsf"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/spf13/cobra"
+)
+
+// runNSF plays an NSF or NSFe file instead of running a game.
+func runNSF(cmd *cobra.Command, path string) error {
+	n, err := nsf.FromFile(path)
+	if err != nil {
+		return err
+	}
+	slog.Info("Loaded NSF", "title", n.Name, "songs", n.Songs)
+
+	conf := config.NewDefault()
+	if err := conf.Load(cmd, n.Name, n.Hash(), nil); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer cancel()
+
+	p, err := nsf.NewPlayer(conf, n)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := p.Close(); err != nil {
+			slog.Error("Failed to close player", "error", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("Exiting...")
+		p.Exit()
+	}()
+
+	scale := conf.UI.Scale
+	ebiten.SetWindowSize(int(consts.Width*scale), int(consts.Height*scale))
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetFullscreen(conf.UI.Fullscreen)
+	ebiten.SetRunnableOnUnfocused(!conf.UI.PauseUnfocused)
+	ebiten.SetTPS(p.Region.TargetFrameRate())
+	setWindowIcons()
+	ebiten.SetWindowTitle(n.Name + " | GoNES")
+
+	if err := ebiten.RunGameWithOptions(p, &ebiten.RunGameOptions{
+		SingleThread: true,
+	}); err != nil && !errors.Is(err, nsf.ErrExit) {
+		return err
+	}
+
+	return nil
+}
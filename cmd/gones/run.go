@@ -45,7 +45,13 @@ func run(ctx context.Context, conf *config.Config, cart *cartridge.Cartridge) er
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetFullscreen(conf.UI.Fullscreen)
 	ebiten.SetScreenClearedEveryFrame(false)
-	ebiten.SetRunnableOnUnfocused(!conf.UI.PauseUnfocused)
+	ebiten.SetTPS(c.Region.TargetFrameRate())
+	if c.Bus.Zapper() != nil {
+		// A crosshair is drawn instead
+		ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	}
+	// A remote debugger needs the game to keep responding while another window is focused
+	ebiten.SetRunnableOnUnfocused(!conf.UI.PauseUnfocused || conf.Debug.GDB != "")
 	setWindowIcons()
 
 	if name := c.Cartridge.Name(); name != "" {
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"gabe565.com/gones/cmd/nesutil/chr/consts"
+	"gabe565.com/gones/internal/archive"
 	"gabe565.com/gones/internal/cartridge"
 	"gabe565.com/gones/internal/util"
 	"github.com/spf13/cobra"
@@ -86,13 +87,13 @@ func run(cmd *cobra.Command, args []string) error {
 var ErrNoCHR = errors.New("ROM file has no CHR data")
 
 func loadCHR(input string) ([]byte, error) {
-	if filepath.Ext(input) == ".nes" {
-		cart, err := cartridge.FromINESFile(input)
+	if strings.EqualFold(filepath.Ext(input), ".nes") || cartridge.IsUNIFFile(input) || archive.IsArchive(input) {
+		cart, err := cartridge.FromFile(input)
 		if err != nil {
 			return nil, err
 		}
 
-		if cart.Header.CHRCount == 0 {
+		if cart.CHRIsRAM() {
 			return nil, fmt.Errorf("%w: %s", ErrNoCHR, input)
 		}
 
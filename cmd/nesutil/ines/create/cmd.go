@@ -9,8 +9,8 @@ import (
 	"strings"
 
 	"gabe565.com/gones/internal/cartridge"
-	"gabe565.com/gones/internal/consts"
 	"gabe565.com/gones/internal/util"
+	"gabe565.com/utils/bytefmt"
 	"gabe565.com/utils/must"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +22,16 @@ const (
 	FlagMapper  = "mapper"
 	FlagMirror  = "mirror"
 	FlagBattery = "battery"
+
+	FlagNESv2     = "nes2"
+	FlagSubmapper = "submapper"
+	FlagPRGRAM    = "prg-ram"
+	FlagPRGNVRAM  = "prg-nvram"
+	FlagCHRRAM    = "chr-ram"
+	FlagCHRNVRAM  = "chr-nvram"
+	FlagTiming    = "timing"
+	FlagConsole   = "console-type"
+	FlagExpansion = "expansion-device"
 )
 
 func New() *cobra.Command {
@@ -38,15 +48,28 @@ func New() *cobra.Command {
 	flag.StringP(FlagHeader, "H", "", "Header file")
 	flag.StringP(FlagPRG, "p", "", "PRG ROM output file path")
 	flag.StringP(FlagCHR, "c", "", "CHR ROM output file path")
-	flag.Uint8P(FlagMapper, "m", 0, "INES mapper number")
+	flag.Uint16P(FlagMapper, "m", 0, "INES mapper number (above 255 requires NES 2.0)")
 	flag.StringP(FlagMirror, "n", "", "Type of nametable mirroring (one of horizontal, vertical, fourscreen)")
 	flag.BoolP(FlagBattery, "b", false, "Enable battery/extra RAM")
+	flag.Bool(FlagNESv2, false, "Write an NES 2.0 header. A loaded header keeps its version unless this is set")
+	flag.Uint8(FlagSubmapper, 0, "NES 2.0 submapper number")
+	flag.String(FlagPRGRAM, "", "NES 2.0 PRG RAM size (for example 8KiB)")
+	flag.String(FlagPRGNVRAM, "", "NES 2.0 battery-backed PRG RAM size")
+	flag.String(FlagCHRRAM, "", "NES 2.0 CHR RAM size")
+	flag.String(FlagCHRNVRAM, "", "NES 2.0 battery-backed CHR RAM size")
+	flag.String(FlagTiming, "", "NES 2.0 CPU/PPU timing (one of ntsc, pal, multi, dendy)")
+	flag.Uint8(FlagConsole, 0, "Console type (0: NES/Famicom, 1: Vs. System, 2: PlayChoice-10, 4+: NES 2.0 extended console type)")
+	flag.Uint8(FlagExpansion, 0, "NES 2.0 default expansion device number")
 	must.Must(cmd.MarkFlagRequired(FlagPRG))
 
 	return cmd
 }
 
-var ErrUnknownMirror = errors.New("unknown mirror")
+var (
+	ErrUnknownMirror = errors.New("unknown mirror")
+	ErrUnknownTiming = errors.New("unknown timing")
+	ErrNotNESv2      = errors.New("flag requires an NES 2.0 header")
+)
 
 func run(cmd *cobra.Command, args []string) error {
 	cart := cartridge.New()
@@ -65,13 +88,23 @@ func run(cmd *cobra.Command, args []string) error {
 		_ = f.Close()
 	}
 
+	if cmd.Flags().Lookup(FlagNESv2).Changed || must.Must2(cmd.Flags().GetString(FlagHeader)) == "" {
+		nes2 := must.Must2(cmd.Flags().GetBool(FlagNESv2))
+		if nes2 != cart.Header.NESv2() {
+			slog.Info("Set NES 2.0", "value", nes2)
+			cart.Header.SetNESv2(nes2)
+		}
+	}
+
 	if prg := must.Must2(cmd.Flags().GetString(FlagPRG)); prg != "" {
 		slog.Info("Loading PRG", "path", prg)
 		var err error
 		if cart.PRG, err = os.ReadFile(prg); err != nil {
 			return err
 		}
-		cart.Header.PRGCount = byte(len(cart.PRG) / consts.PRGChunkSize)
+		if err := cart.Header.SetPRGSize(len(cart.PRG)); err != nil {
+			return fmt.Errorf("%s: %w", FlagPRG, err)
+		}
 	}
 
 	if chr := must.Must2(cmd.Flags().GetString(FlagCHR)); chr != "" {
@@ -80,11 +113,16 @@ func run(cmd *cobra.Command, args []string) error {
 		if cart.CHR, err = os.ReadFile(chr); err != nil {
 			return err
 		}
-		cart.Header.CHRCount = byte(len(cart.CHR) / consts.CHRChunkSize)
+		if err := cart.Header.SetCHRSize(len(cart.CHR)); err != nil {
+			return fmt.Errorf("%s: %w", FlagCHR, err)
+		}
 	}
 
 	if cmd.Flags().Lookup(FlagMapper).Changed {
-		mapper := must.Must2(cmd.Flags().GetUint8(FlagMapper))
+		mapper := must.Must2(cmd.Flags().GetUint16(FlagMapper))
+		if mapper > 0xFF && !cart.Header.NESv2() {
+			return fmt.Errorf("%w: %s", ErrNotNESv2, FlagMapper)
+		}
 		slog.Info("Set mapper", "value", mapper)
 		cart.Header.SetMapper(mapper)
 	}
@@ -111,6 +149,10 @@ func run(cmd *cobra.Command, args []string) error {
 		cart.Header.SetBattery(battery)
 	}
 
+	if err := setNESv2Fields(cmd, &cart.Header); err != nil {
+		return err
+	}
+
 	f, err := os.Create(args[0])
 	if err != nil {
 		return err
@@ -138,3 +180,72 @@ func run(cmd *cobra.Command, args []string) error {
 
 	return f.Close()
 }
+
+// setNESv2Fields sets header fields that only exist in NES 2.0.
+func setNESv2Fields(cmd *cobra.Command, header *cartridge.INESFileHeader) error {
+	flags := cmd.Flags()
+	for _, name := range []string{
+		FlagSubmapper, FlagPRGRAM, FlagPRGNVRAM, FlagCHRRAM, FlagCHRNVRAM, FlagTiming, FlagExpansion,
+	} {
+		if flags.Lookup(name).Changed && !header.NESv2() {
+			return fmt.Errorf("%w: %s", ErrNotNESv2, name)
+		}
+	}
+
+	if flags.Lookup(FlagSubmapper).Changed {
+		submapper := must.Must2(flags.GetUint8(FlagSubmapper))
+		slog.Info("Set submapper", "value", submapper)
+		header.SetSubmapper(submapper)
+	}
+
+	if flags.Lookup(FlagPRGRAM).Changed || flags.Lookup(FlagPRGNVRAM).Changed ||
+		flags.Lookup(FlagCHRRAM).Changed || flags.Lookup(FlagCHRNVRAM).Changed {
+		sizes := []int{header.PRGRAMSize(), header.PRGNVRAMSize(), header.CHRRAMSize(), header.CHRNVRAMSize()}
+		for i, name := range []string{FlagPRGRAM, FlagPRGNVRAM, FlagCHRRAM, FlagCHRNVRAM} {
+			if !flags.Lookup(name).Changed {
+				continue
+			}
+			size, err := bytefmt.Decode(must.Must2(flags.GetString(name)))
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			slog.Info("Set RAM size", "type", name, "value", size)
+			sizes[i] = int(size)
+		}
+		header.SetRAMSizes(sizes[0], sizes[1], sizes[2], sizes[3])
+	}
+
+	if flags.Lookup(FlagTiming).Changed {
+		var timing cartridge.Timing
+		switch v := strings.ToLower(must.Must2(flags.GetString(FlagTiming))); v {
+		case "ntsc":
+			timing = cartridge.TimingNTSC
+		case "pal":
+			timing = cartridge.TimingPAL
+		case "multi":
+			timing = cartridge.TimingMulti
+		case "dendy":
+			timing = cartridge.TimingDendy
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownTiming, v)
+		}
+		slog.Info("Set timing", "value", timing)
+		header.SetTiming(timing)
+	}
+
+	if flags.Lookup(FlagConsole).Changed {
+		consoleType := cartridge.ConsoleType(must.Must2(flags.GetUint8(FlagConsole)))
+		if consoleType > cartridge.ConsoleExtended && !header.NESv2() {
+			return fmt.Errorf("%w: %s", ErrNotNESv2, FlagConsole)
+		}
+		slog.Info("Set console type", "value", consoleType)
+		header.SetConsoleType(consoleType)
+	}
+
+	if flags.Lookup(FlagExpansion).Changed {
+		device := cartridge.ExpansionDevice(must.Must2(flags.GetUint8(FlagExpansion)))
+		slog.Info("Set expansion device", "value", device)
+		header.SetExpansionDevice(device)
+	}
+	return nil
+}
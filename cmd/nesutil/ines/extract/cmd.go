@@ -73,7 +73,7 @@ func run(cmd *cobra.Command, args []string) error {
 		chr = base + "_chr"
 	}
 
-	if cart.Header.CHRCount == 0 {
+	if cart.CHRIsRAM() {
 		slog.Warn("Game does not have CHR. Skipping")
 	} else {
 		slog.Info("Extracting CHR", "path", chr)
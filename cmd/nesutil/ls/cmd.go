@@ -12,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	"gabe565.com/gones/internal/archive"
 	"gabe565.com/gones/internal/cartridge"
 	"gabe565.com/gones/internal/log"
 	"gabe565.com/gones/internal/util"
@@ -136,13 +137,12 @@ func loadPaths(paths []string) ([]*entry, []error) {
 				return err
 			}
 
-			ext := filepath.Ext(path)
-			if !strings.EqualFold(ext, ".nes") {
+			if !isCartridge(path) && !archive.IsArchive(path) {
 				return err
 			}
 
 			wg.Go(func() {
-				cart, err := cartridge.FromINESFile(path)
+				paths, err := archive.List(path, isCartridge)
 				if err != nil {
 					mu.Lock()
 					errs = append(errs, fmt.Errorf("%s: %w", path, err))
@@ -150,10 +150,20 @@ func loadPaths(paths []string) ([]*entry, []error) {
 					return
 				}
 
-				entry := newEntry(path, cart)
-				mu.Lock()
-				carts = append(carts, entry)
-				mu.Unlock()
+				for _, path := range paths {
+					cart, err := cartridge.FromFile(path)
+					if err != nil {
+						mu.Lock()
+						errs = append(errs, fmt.Errorf("%s: %w", path, err))
+						mu.Unlock()
+						continue
+					}
+
+					entry := newEntry(path, cart)
+					mu.Lock()
+					carts = append(carts, entry)
+					mu.Unlock()
+				}
 			})
 			return nil
 		}); err != nil {
@@ -165,6 +175,11 @@ func loadPaths(paths []string) ([]*entry, []error) {
 	return carts, errs
 }
 
+// isCartridge reports whether a path is an iNES or UNIF file.
+func isCartridge(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".nes") || cartridge.IsUNIFFile(path)
+}
+
 var ErrUnknownSortField = errors.New("unknown sort field")
 
 func sortFunc(field string, errCh chan error) func(a, b *entry) int {
@@ -209,13 +224,13 @@ func deleteFunc(filters map[string]string, errCh chan error) func(e *entry) bool
 			case NameField:
 				return !strings.Contains(strings.ToLower(e.Name), strings.ToLower(filter))
 			case MapperField:
-				parsed, err := strconv.ParseUint(filter, 10, 8)
+				parsed, err := strconv.ParseUint(filter, 10, 16)
 				if err != nil {
 					errCh <- fmt.Errorf("invalid mapper filter value: %w", err)
 					return false
 				}
 
-				return byte(parsed) != e.Mapper
+				return uint16(parsed) != e.Mapper
 			case MirrorField:
 				return !strings.Contains(strings.ToLower(e.Mirror), strings.ToLower(filter))
 			case BatteryField:
@@ -0,0 +1,63 @@
+package apply
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gabe565.com/gones/internal/archive"
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/patch"
+	"gabe565.com/gones/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply ROM PATCH [output]",
+		Short: "Apply an IPS, BPS, or UPS patch to a ROM",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  run,
+
+		ValidArgsFunction: validArgs,
+	}
+	return cmd
+}
+
+func validArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0, 2:
+		return util.CompleteROM(cmd, args, toComplete)
+	case 1:
+		return util.CompletePatch(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	name, rom, err := archive.ReadFile(args[0], cartridge.IsROMFile)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Applying patch", "path", args[1])
+	patched, err := patch.ApplyFile(rom, args[1])
+	if err != nil {
+		return err
+	}
+
+	var output string
+	if len(args) > 2 {
+		output = args[2]
+	} else {
+		ext := filepath.Ext(name)
+		output = strings.TrimSuffix(filepath.Base(name), ext) + "_patched" + ext
+	}
+
+	slog.Info("Writing ROM", "path", output)
+	return os.WriteFile(output, patched, 0o644)
+}
@@ -0,0 +1,16 @@
+package patch
+
+import (
+	"gabe565.com/gones/cmd/nesutil/patch/apply"
+	"gabe565.com/gones/cmd/nesutil/patch/create"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "IPS, BPS, and UPS patch utilities",
+	}
+	cmd.AddCommand(apply.New(), create.New())
+	return cmd
+}
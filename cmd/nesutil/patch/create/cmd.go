@@ -0,0 +1,64 @@
+package create
+
+import (
+	"log/slog"
+	"os"
+
+	"gabe565.com/gones/internal/archive"
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/patch"
+	"gabe565.com/gones/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create ORIGINAL MODIFIED PATCH",
+		Short: "Create a patch from the differences between two ROMs",
+		Long:  "Create a patch from the differences between two ROMs.\nThe patch format is chosen by the PATCH file extension (one of .ips, .bps, .ups).",
+		Args:  cobra.ExactArgs(3),
+		RunE:  run,
+
+		ValidArgsFunction: validArgs,
+	}
+	return cmd
+}
+
+func validArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0, 1:
+		return util.CompleteROM(cmd, args, toComplete)
+	case 2:
+		return util.CompletePatch(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	output := args[2]
+	format, err := patch.FormatFromPath(output)
+	if err != nil {
+		return err
+	}
+
+	_, original, err := archive.ReadFile(args[0], cartridge.IsROMFile)
+	if err != nil {
+		return err
+	}
+
+	_, modified, err := archive.ReadFile(args[1], cartridge.IsROMFile)
+	if err != nil {
+		return err
+	}
+
+	b, err := patch.Diff(format, original, modified)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Writing patch", "path", output, "format", format, "size", len(b))
+	return os.WriteFile(output, b, 0o644)
+}
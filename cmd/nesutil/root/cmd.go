@@ -5,6 +5,8 @@ import (
 	"gabe565.com/gones/cmd/nesutil/genie"
 	"gabe565.com/gones/cmd/nesutil/ines"
 	"gabe565.com/gones/cmd/nesutil/ls"
+	"gabe565.com/gones/cmd/nesutil/patch"
+	"gabe565.com/gones/cmd/nesutil/run"
 	"gabe565.com/gones/cmd/options"
 	"github.com/spf13/cobra"
 )
@@ -17,7 +19,7 @@ func New(opts ...options.Option) *cobra.Command {
 		SilenceErrors:     true,
 		DisableAutoGenTag: true,
 	}
-	cmd.AddCommand(ls.New(), ines.New(), chr.New(), genie.New())
+	cmd.AddCommand(ls.New(), ines.New(), chr.New(), genie.New(), run.New(), patch.New())
 
 	for _, opt := range opts {
 		opt(cmd)
@@ -0,0 +1,171 @@
+package run
+
+import (
+	"crypto/md5" //nolint:gosec
+	"errors"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"os"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/console"
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/util"
+	"gabe565.com/utils/must"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagFrames     = "frames"
+	FlagUntil      = "until"
+	FlagMovie      = "movie"
+	FlagScreenshot = "screenshot"
+	FlagHash       = "hash"
+	FlagRAM        = "ram"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run ROM",
+		Short: "Run a ROM without a window or audio",
+		Long: `Run a ROM without a window or audio.
+
+The ROM runs for a fixed number of frames, or until every --until condition is met.
+Conditions are written as ADDR=VALUE or ADDR!=VALUE in hex, for example "6000!=80".
+ADDR may also be PC to compare the program counter, for example "PC=C000".
+Conditions are checked after every instruction, and the run stops as soon as they are all met.`,
+		Args: cobra.ExactArgs(1),
+		RunE: run,
+
+		ValidArgsFunction: util.CompleteROM,
+	}
+
+	flag := cmd.Flags()
+	flag.IntP(FlagFrames, "n", 600, "Maximum number of frames to run")
+	flag.StringArrayP(FlagUntil, "u", nil, "Stop once a CPU memory condition is met (repeatable)")
+	flag.StringP(FlagMovie, "m", "", "FM2 movie to use as controller input")
+	flag.StringP(FlagScreenshot, "s", "", "Write the final frame to a PNG file")
+	flag.Bool(FlagHash, false, "Print an MD5 hash of every frame")
+	flag.String(FlagRAM, "", "Write the final CPU RAM to a file")
+
+	must.Must(cmd.RegisterFlagCompletionFunc(FlagMovie,
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return []string{"fm2"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+	))
+	must.Must(cmd.RegisterFlagCompletionFunc(FlagScreenshot,
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return []string{"png"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+	))
+
+	return cmd
+}
+
+var ErrConditionNotMet = errors.New("conditions were not met")
+
+func run(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	frames := must.Must2(cmd.Flags().GetInt(FlagFrames))
+	var conditions []debugger.Condition
+	for _, s := range must.Must2(cmd.Flags().GetStringArray(FlagUntil)) {
+		cond, err := debugger.ParseCondition(s)
+		if err != nil {
+			return err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	cart, err := cartridge.FromFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	// User config, cheats, and save data are skipped so that runs are reproducible
+	conf := config.NewDefault()
+	conf.Debug.SkipSaveData = true
+	conf.Cheats.Enabled = false
+	conf.Audio.Enabled = false
+	conf.State.Resume = false
+	conf.State.AutosaveInterval = 0
+	conf.State.RewindLength = 0
+	conf.Movie.Play = must.Must2(cmd.Flags().GetString(FlagMovie))
+
+	c, err := console.New(conf, cart)
+	if err != nil {
+		return err
+	}
+
+	printHash := must.Must2(cmd.Flags().GetBool(FlagHash))
+	var frame int
+	var met bool
+	for ; frame < frames; frame++ {
+		var err error
+		if len(conditions) != 0 {
+			met, err = c.StepFrameUntil(func() bool { return allMet(c, conditions) })
+		} else {
+			err = c.StepFrame()
+		}
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", frame, err)
+		}
+		if met {
+			// The frame that met the conditions is counted, even if it was not finished
+			frame++
+			break
+		}
+
+		if printHash {
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%d %x\n", frame, md5.Sum(c.PPU.Image().Pix)); err != nil { //nolint:gosec
+				return err
+			}
+		}
+	}
+	slog.Info("Finished running", "frames", frame)
+
+	if path := must.Must2(cmd.Flags().GetString(FlagScreenshot)); path != "" {
+		slog.Info("Writing screenshot", "path", path)
+		if err := writePNG(c, path); err != nil {
+			return err
+		}
+	}
+
+	if path := must.Must2(cmd.Flags().GetString(FlagRAM)); path != "" {
+		slog.Info("Writing RAM", "path", path)
+		if err := os.WriteFile(path, c.Bus.CPUVRAM[:], 0o644); err != nil {
+			return err
+		}
+	}
+
+	if len(conditions) != 0 && !met {
+		return fmt.Errorf("%w after %d frames", ErrConditionNotMet, frame)
+	}
+	return nil
+}
+
+func allMet(c *console.Console, conditions []debugger.Condition) bool {
+	for _, cond := range conditions {
+		if !cond.Met(c.CPU.ProgramCounter, c.Bus) {
+			return false
+		}
+	}
+	return true
+}
+
+func writePNG(c *console.Console, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err := png.Encode(f, c.PPU.Image()); err != nil {
+		return err
+	}
+	return f.Close()
+}
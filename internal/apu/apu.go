@@ -16,10 +16,12 @@ type CPU interface {
 	interrupt.Stall
 }
 
-const (
-	FrameCounterRate  = float64(consts.CPUFrequency) / 240.0
-	DefaultSampleRate = float64(consts.CPUFrequency) / float64(consts.AudioSampleRate) * consts.FrameRateDiff
-)
+// SampleRate returns the number of CPU cycles per audio sample.
+// Samples are stretched slightly so that the hardware frame rate matches the target frame rate.
+func SampleRate(region consts.Region) float64 {
+	frameRateDiff := float64(region.TargetFrameRate()) / region.FrameRate()
+	return region.CPUFrequency() / float64(consts.AudioSampleRate) * frameRateDiff
+}
 
 //nolint:gochecknoglobals
 var (
@@ -31,6 +33,10 @@ var (
 	tndTable    [203]float32
 )
 
+// squareLevel is the mixed output of one square channel at full volume.
+// Expansion audio levels are set relative to it.
+const squareLevel = 95.52 / (8128.0/15 + 100)
+
 const (
 	StatusPulse1 = 1 << iota
 	StatusPulse2
@@ -53,19 +59,28 @@ func init() { //nolint:gochecknoinits
 
 func New(conf *config.Config) *APU {
 	a := &APU{
-		Enabled:    true,
-		SampleRate: DefaultSampleRate,
-		conf:       &conf.Audio,
-		buf:        newRingBuffer(int(conf.Audio.BufferSize)),
+		Enabled: true,
+		conf:    &conf.Audio,
+		buf:     newRingBuffer(int(conf.Audio.BufferSize)),
 
 		Square: [2]Square{{Channel1: true}, {}},
 		Noise:  Noise{ShiftRegister: 1},
 
 		FramePeriod: 4,
 	}
+	a.SetRegion(consts.RegionNTSC)
 	return a
 }
 
+// SetRegion sets the APU timing and period tables.
+func (a *APU) SetRegion(region consts.Region) {
+	a.region = region
+	a.SampleRate = SampleRate(region)
+	a.frameCounterRate = region.FrameCounterRate()
+	a.Noise.periods = &noisePeriodTables[region]
+	a.DMC.periods = &dmcPeriodTables[region]
+}
+
 type APU struct {
 	Enabled    bool    `msgpack:"-"`
 	SampleRate float64 `msgpack:"-"`
@@ -73,11 +88,16 @@ type APU struct {
 	buf        *ringBuffer
 	sample     float32
 
+	region           consts.Region
+	frameCounterRate float64
+
 	Square   [2]Square
 	Triangle Triangle
 	Noise    Noise
 	DMC      DMC
 
+	expansions []Expansion
+
 	Cycle       uint
 	FramePeriod uint8
 	FrameValue  byte
@@ -158,6 +178,20 @@ func (a *APU) Reset() {
 	a.WriteMem(0x4015, 0)
 }
 
+// Power resets all channels to their power-on state.
+func (a *APU) Power() {
+	a.sample = 0
+	a.Square = [2]Square{{Channel1: true}, {}}
+	a.Triangle = Triangle{}
+	a.Noise = Noise{ShiftRegister: 1, periods: a.Noise.periods}
+	a.DMC = DMC{cpu: a.DMC.cpu, periods: a.DMC.periods}
+	a.Cycle = 0
+	a.FramePeriod = 4
+	a.FrameValue = 0
+	a.IRQEnabled = false
+	a.IRQPending = false
+}
+
 func (a *APU) Step() bool {
 	cycle1 := float64(a.Cycle)
 	a.Cycle++
@@ -165,8 +199,8 @@ func (a *APU) Step() bool {
 
 	a.stepTimer()
 
-	f1 := uint32(cycle1 / FrameCounterRate)
-	f2 := uint32(cycle2 / FrameCounterRate)
+	f1 := uint32(cycle1 / a.frameCounterRate)
+	f2 := uint32(cycle2 / a.frameCounterRate)
 	if f1 != f2 {
 		a.stepFrameCounter()
 	}
@@ -216,6 +250,9 @@ func (a *APU) stepTimer() {
 		a.DMC.stepTimer()
 	}
 	a.Triangle.stepTimer()
+	for _, e := range a.expansions {
+		e.StepAudio()
+	}
 }
 
 func (a *APU) stepEnvelope() {
@@ -257,7 +294,13 @@ func (a *APU) output() float32 {
 		tnd += a.DMC.output()
 	}
 
-	return squareTable[square] + tndTable[tnd]
+	result := squareTable[square] + tndTable[tnd]
+	for _, e := range a.expansions {
+		if a.expansionEnabled(e) {
+			result += e.AudioOutput()
+		}
+	}
+	return result
 }
 
 func (a *APU) sendSample() {
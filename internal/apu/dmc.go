@@ -1,8 +1,12 @@
 package apu
 
+import "gabe565.com/gones/internal/consts"
+
 //nolint:gochecknoglobals
-var dmcPeriodTable = [...]byte{
-	214, 190, 170, 160, 143, 127, 113, 107, 95, 80, 71, 64, 53, 42, 36, 27,
+var dmcPeriodTables = [...][16]byte{
+	consts.RegionNTSC:  {214, 190, 170, 160, 143, 127, 113, 107, 95, 80, 71, 64, 53, 42, 36, 27},
+	consts.RegionPAL:   {199, 177, 158, 149, 138, 118, 105, 99, 88, 74, 66, 59, 49, 39, 33, 25},
+	consts.RegionDendy: {214, 190, 170, 160, 143, 127, 113, 107, 95, 80, 71, 64, 53, 42, 36, 27},
 }
 
 type DMC struct {
@@ -24,6 +28,8 @@ type DMC struct {
 	CurrLen       uint16
 	ShiftRegister byte
 	BitCount      byte
+
+	periods *[16]byte
 }
 
 func (d *DMC) Write(addr uint16, data byte) {
@@ -34,7 +40,7 @@ func (d *DMC) Write(addr uint16, data byte) {
 			d.IRQPending = false
 		}
 		d.Loop = data>>6&1 == 1
-		d.TickPeriod = dmcPeriodTable[data&0xF]
+		d.TickPeriod = d.periods[data&0xF]
 	case 0x4011:
 		d.Value = data & 0x7F
 	case 0x4012:
@@ -0,0 +1,34 @@
+package apu
+
+// Expansion is a cartridge's expansion audio chip. Mappers with expansion audio implement it
+// so that their channels are clocked and mixed along with the APU's.
+type Expansion interface {
+	// StepAudio is called once per CPU cycle.
+	StepAudio()
+	// AudioOutput returns the chip's level, on the same scale as the APU's mixed output.
+	AudioOutput() float32
+}
+
+// SetExpansions sets the expansion audio chips that are mixed with the APU's channels.
+// Calling it with no chips removes them.
+func (a *APU) SetExpansions(e ...Expansion) {
+	a.expansions = e
+}
+
+// expansionEnabled reports whether an expansion chip is turned on in the audio channel config.
+func (a *APU) expansionEnabled(e Expansion) bool {
+	switch e.(type) {
+	case *FDS:
+		return a.conf.Channels.FDS
+	case *MMC5:
+		return a.conf.Channels.MMC5
+	case *VRC6:
+		return a.conf.Channels.VRC6
+	case *VRC7:
+		return a.conf.Channels.VRC7
+	case *N163:
+		return a.conf.Channels.N163
+	default:
+		return true
+	}
+}
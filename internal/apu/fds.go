@@ -0,0 +1,235 @@
+package apu
+
+//nolint:gochecknoglobals
+var (
+	// fdsModTable maps mod table entries to mod counter adjustments. Entry 4 resets the counter instead.
+	fdsModTable = [...]int{0, 1, 2, 4, 0, -4, -2, -1}
+	// fdsMasterVolume is the output multiplier for each master volume setting (2/2, 2/3, 2/4, and 2/5), out of 30.
+	fdsMasterVolume = [...]uint16{30, 20, 15, 12}
+)
+
+const (
+	fdsMaxGain = 32
+	// fdsMaxOutput is the maximum FDS channel level.
+	fdsMaxOutput = 63 * fdsMaxGain
+	// fdsMix is the mixed output of the FDS channel at its maximum level.
+	// At full volume, the FDS channel is about 2.4 times louder than a square channel.
+	fdsMix = 2.4 * squareLevel
+)
+
+// FDS is the Famicom Disk System's wavetable channel.
+//
+// See [FDS audio].
+//
+// [FDS audio]: https://www.nesdev.org/wiki/FDS_audio
+type FDS struct {
+	WaveTable    [64]byte
+	WaveWrite    bool
+	WaveHalt     bool
+	WaveFreq     uint16
+	WaveAccum    uint32
+	WavePos      byte
+	WaveOutput   byte
+	MasterVolume byte
+
+	VolumeEnvelope FDSEnvelope
+	ModEnvelope    FDSEnvelope
+	EnvelopeHalt   bool
+	EnvelopeSpeed  byte
+
+	ModTable   [64]byte
+	ModHalt    bool
+	ModFreq    uint16
+	ModAccum   uint32
+	ModPos     byte
+	ModCounter int
+}
+
+// FDSEnvelope is one of the FDS channel's volume or mod envelopes.
+type FDSEnvelope struct {
+	Disabled bool
+	Increase bool
+	Speed    byte
+	Gain     byte
+	Timer    uint32
+}
+
+func (e *FDSEnvelope) Write(data byte) {
+	e.Disabled = data>>7&1 == 1
+	e.Increase = data>>6&1 == 1
+	e.Speed = data & 0x3F
+	e.Timer = 0
+	if e.Disabled {
+		e.Gain = e.Speed
+	}
+}
+
+func (e *FDSEnvelope) step(masterSpeed byte) {
+	if e.Disabled || masterSpeed == 0 {
+		return
+	}
+
+	e.Timer++
+	if e.Timer < 8*uint32(masterSpeed)*(uint32(e.Speed)+1) {
+		return
+	}
+	e.Timer = 0
+
+	switch {
+	case e.Increase && e.Gain < fdsMaxGain:
+		e.Gain++
+	case !e.Increase && e.Gain > 0:
+		e.Gain--
+	}
+}
+
+func (f *FDS) Write(addr uint16, data byte) {
+	switch {
+	case 0x4040 <= addr && addr <= 0x407F:
+		if f.WaveWrite {
+			f.WaveTable[addr-0x4040] = data & 0x3F
+		}
+	case addr == 0x4080:
+		f.VolumeEnvelope.Write(data)
+	case addr == 0x4082:
+		f.WaveFreq = f.WaveFreq&0xF00 | uint16(data)
+	case addr == 0x4083:
+		f.WaveFreq = uint16(data)&0xF<<8 | f.WaveFreq&0xFF
+		f.WaveHalt = data>>7&1 == 1
+		f.EnvelopeHalt = data>>6&1 == 1
+		if f.WaveHalt {
+			f.WaveAccum = 0
+			f.WavePos = 0
+		}
+	case addr == 0x4084:
+		f.ModEnvelope.Write(data)
+	case addr == 0x4085:
+		// 7-bit signed
+		f.ModCounter = int(int8(data<<1) >> 1)
+	case addr == 0x4086:
+		f.ModFreq = f.ModFreq&0xF00 | uint16(data)
+	case addr == 0x4087:
+		f.ModFreq = uint16(data)&0xF<<8 | f.ModFreq&0xFF
+		f.ModHalt = data>>7&1 == 1
+		if f.ModHalt {
+			f.ModAccum = 0
+		}
+	case addr == 0x4088:
+		// Mod table writes fill two entries, and only work while the mod unit is halted
+		if f.ModHalt {
+			f.ModTable[f.ModPos] = data & 0x7
+			f.ModTable[(f.ModPos+1)&0x3F] = data & 0x7
+			f.ModPos = (f.ModPos + 2) & 0x3F
+		}
+	case addr == 0x4089:
+		f.WaveWrite = data>>7&1 == 1
+		f.MasterVolume = data & 0x3
+	case addr == 0x408A:
+		f.EnvelopeSpeed = data
+	}
+}
+
+// Read returns the low 6 bits of a readable register.
+// It returns false if the address is not readable, in which case the read is open bus.
+func (f *FDS) Read(addr uint16) (byte, bool) {
+	switch {
+	case 0x4040 <= addr && addr <= 0x407F:
+		if !f.WaveWrite {
+			// The wave unit is reading the table
+			return f.WaveTable[f.WavePos], true
+		}
+		return f.WaveTable[addr-0x4040], true
+	case addr == 0x4090:
+		return f.VolumeEnvelope.Gain, true
+	case addr == 0x4092:
+		return f.ModEnvelope.Gain, true
+	default:
+		return 0, false
+	}
+}
+
+func (f *FDS) StepAudio() {
+	if !f.EnvelopeHalt && !f.WaveHalt {
+		f.VolumeEnvelope.step(f.EnvelopeSpeed)
+		f.ModEnvelope.step(f.EnvelopeSpeed)
+	}
+
+	if !f.ModHalt && f.ModFreq != 0 {
+		f.ModAccum += uint32(f.ModFreq)
+		if f.ModAccum >= 0x10000 {
+			f.ModAccum -= 0x10000
+			f.stepMod()
+		}
+	}
+
+	if !f.WaveHalt {
+		if pitch := f.pitch(); pitch > 0 {
+			f.WaveAccum += uint32(pitch)
+			if f.WaveAccum >= 0x10000 {
+				f.WaveAccum -= 0x10000
+				f.WavePos = (f.WavePos + 1) & 0x3F
+			}
+		}
+	}
+
+	if !f.WaveWrite {
+		// Output holds its last value while the wave table is writable
+		f.WaveOutput = f.WaveTable[f.WavePos]
+	}
+}
+
+func (f *FDS) stepMod() {
+	if entry := f.ModTable[f.ModPos]; entry == 4 {
+		f.ModCounter = 0
+	} else {
+		f.ModCounter += fdsModTable[entry]
+		// Wrap to 7-bit signed
+		f.ModCounter = int(int8(f.ModCounter<<1) >> 1)
+	}
+	f.ModPos = (f.ModPos + 1) & 0x3F
+}
+
+// pitch returns the wave frequency adjusted by the mod unit.
+// The rounding matches the hardware.
+func (f *FDS) pitch() int {
+	freq := int(f.WaveFreq)
+	if f.ModHalt || f.ModFreq == 0 {
+		return freq
+	}
+
+	temp := f.ModCounter * int(f.ModEnvelope.Gain)
+	remainder := temp & 0xF
+	temp >>= 4
+	if remainder > 0 && temp&0x80 == 0 {
+		if f.ModCounter < 0 {
+			temp--
+		} else {
+			temp += 2
+		}
+	}
+
+	switch {
+	case temp >= 192:
+		temp -= 256
+	case temp < -64:
+		temp += 256
+	}
+
+	temp *= freq
+	remainder = temp & 0x3F
+	temp >>= 6
+	if remainder >= 32 {
+		temp++
+	}
+	return freq + temp
+}
+
+// output returns the channel level between 0 and [fdsMaxOutput].
+func (f *FDS) output() uint16 {
+	gain := min(f.VolumeEnvelope.Gain, fdsMaxGain)
+	return uint16(f.WaveOutput) * uint16(gain) * fdsMasterVolume[f.MasterVolume] / 30
+}
+
+func (f *FDS) AudioOutput() float32 {
+	return float32(f.output()) / fdsMaxOutput * fdsMix
+}
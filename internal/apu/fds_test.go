@@ -0,0 +1,67 @@
+package apu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFDS_WaveTable(t *testing.T) {
+	t.Parallel()
+
+	var f FDS
+	f.Write(0x4089, 0x80)
+	for i := range uint16(64) {
+		f.Write(0x4040+i, byte(i)|0xC0)
+	}
+	data, ok := f.Read(0x4045)
+	assert.True(t, ok)
+	assert.Equal(t, byte(5), data)
+
+	// Full volume, without an envelope
+	f.Write(0x4089, 0)
+	f.Write(0x4080, 0x80|fdsMaxGain)
+	f.Write(0x4082, 0)
+	f.Write(0x4083, 0x01)
+	for range 0x10000 / 0x100 * 10 {
+		f.StepAudio()
+	}
+	assert.Equal(t, byte(10), f.WavePos)
+	assert.Equal(t, byte(10), f.WaveOutput)
+	assert.Equal(t, uint16(10*fdsMaxGain), f.output())
+
+	// Table writes are ignored unless enabled
+	f.Write(0x4040, 0x3F)
+	data, _ = f.Read(0x4040)
+	assert.Equal(t, f.WaveTable[f.WavePos], data)
+	assert.Zero(t, f.WaveTable[0])
+}
+
+func TestFDS_ModTable(t *testing.T) {
+	t.Parallel()
+
+	var f FDS
+	f.Write(0x4087, 0x80)
+	for i := range byte(32) {
+		f.Write(0x4088, i&7)
+	}
+	assert.Equal(t, byte(1), f.ModTable[2])
+	assert.Equal(t, byte(1), f.ModTable[3])
+	assert.Zero(t, f.ModPos)
+
+	f.Write(0x4085, 0x3F)
+	assert.Equal(t, 63, f.ModCounter)
+	f.ModPos = 2
+	f.stepMod()
+	assert.Equal(t, -64, f.ModCounter, "counter wraps to 7-bit signed")
+	f.ModPos = 8
+	f.stepMod()
+	assert.Zero(t, f.ModCounter, "entry 4 resets the counter")
+
+	// Writes at an odd position wrap around the end of the table
+	f.ModPos = 63
+	f.Write(0x4088, 5)
+	assert.Equal(t, byte(5), f.ModTable[63])
+	assert.Equal(t, byte(5), f.ModTable[0])
+	assert.Equal(t, byte(1), f.ModPos)
+}
@@ -0,0 +1,79 @@
+package apu
+
+// mmc5FramePeriod is the number of CPU cycles between the MMC5's envelope and length counter clocks, which is about 240 Hz.
+const mmc5FramePeriod = 7457
+
+// MMC5 is the MMC5's expansion audio: two pulse channels and a raw PCM channel.
+//
+// See [MMC5 audio].
+//
+// [MMC5 audio]: https://www.nesdev.org/wiki/MMC5_audio
+type MMC5 struct {
+	// Square holds the pulse channels. They work like the APU's, but have no sweep unit.
+	Square [2]Square
+
+	// PCM is the raw 8-bit PCM level.
+	// Read mode and the PCM IRQ are not emulated, since no known game uses them.
+	PCM         byte
+	PCMReadMode bool
+
+	Cycle      uint
+	FrameTimer uint16
+}
+
+func (m *MMC5) Write(addr uint16, data byte) {
+	switch {
+	case 0x5000 <= addr && addr <= 0x5003:
+		if addr != 0x5001 {
+			m.Square[0].Write(addr-0x1000, data)
+		}
+	case 0x5004 <= addr && addr <= 0x5007:
+		if addr != 0x5005 {
+			m.Square[1].Write(addr-0x1000, data)
+		}
+	case addr == 0x5010:
+		m.PCMReadMode = data&1 != 0
+	case addr == 0x5011:
+		// Writing 0 has no effect
+		if !m.PCMReadMode && data != 0 {
+			m.PCM = data
+		}
+	case addr == 0x5015:
+		m.Square[0].SetEnabled(data&StatusPulse1 != 0)
+		m.Square[1].SetEnabled(data&StatusPulse2 != 0)
+	}
+}
+
+func (m *MMC5) Read(addr uint16) byte {
+	var data byte
+	if addr == 0x5015 {
+		if m.Square[0].LengthValue > 0 {
+			data |= StatusPulse1
+		}
+		if m.Square[1].LengthValue > 0 {
+			data |= StatusPulse2
+		}
+	}
+	return data
+}
+
+func (m *MMC5) StepAudio() {
+	m.Cycle++
+	if m.Cycle%2 == 0 {
+		m.Square[0].stepTimer()
+		m.Square[1].stepTimer()
+	}
+
+	m.FrameTimer++
+	if m.FrameTimer == mmc5FramePeriod {
+		m.FrameTimer = 0
+		for i := range m.Square {
+			m.Square[i].stepEnvelope()
+			m.Square[i].stepLength()
+		}
+	}
+}
+
+func (m *MMC5) AudioOutput() float32 {
+	return squareTable[m.Square[0].output()+m.Square[1].output()] + tndTable[m.PCM>>1]
+}
@@ -0,0 +1,111 @@
+package apu
+
+const (
+	// n163Level is the mixed output of one N163 output step, so that a sample of 8 at volume 15 matches a square channel.
+	n163Level = squareLevel / 120
+	// n163Period is the number of CPU cycles that it takes to update one channel.
+	n163Period = 15
+	// N163RAMSize is the size of the N163's internal RAM.
+	N163RAMSize = 0x80
+)
+
+// N163 is the Namco 163's expansion audio: up to 8 wavetable channels.
+// Channel registers and waveforms share 128 bytes of internal RAM. Only one channel is output at a time,
+// so enabling more channels lowers the rate that each is updated.
+//
+// See [Namco 163 audio].
+//
+// [Namco 163 audio]: https://www.nesdev.org/wiki/Namco_163_audio
+type N163 struct {
+	// RAM is the internal RAM. It is owned by the caller so that it can be battery-backed.
+	RAM []byte `msgpack:"-"`
+	// Addr is the $F800 address port. Bit 7 enables auto-increment.
+	Addr byte
+
+	Disabled bool
+	Cycle    byte
+	Channel  byte
+	Sample   int8
+}
+
+// NewN163 returns an N163 that uses ram as its internal RAM.
+func NewN163(ram []byte) N163 {
+	return N163{RAM: ram[:N163RAMSize], Channel: 7}
+}
+
+// WriteAddr writes the $F800 address port.
+func (n *N163) WriteAddr(data byte) {
+	n.Addr = data
+}
+
+// ReadData reads the $4800 data port.
+func (n *N163) ReadData() byte {
+	data := n.RAM[n.Addr&0x7F]
+	n.increment()
+	return data
+}
+
+// WriteData writes the $4800 data port.
+func (n *N163) WriteData(data byte) {
+	n.RAM[n.Addr&0x7F] = data
+	n.increment()
+}
+
+func (n *N163) increment() {
+	if n.Addr&0x80 != 0 {
+		n.Addr = 0x80 | (n.Addr+1)&0x7F
+	}
+}
+
+// SetDisabled sets the sound disable bit.
+func (n *N163) SetDisabled(disabled bool) {
+	n.Disabled = disabled
+	if disabled {
+		n.Sample = 0
+	}
+}
+
+func (n *N163) StepAudio() {
+	if n.Disabled {
+		return
+	}
+	n.Cycle++
+	if n.Cycle < n163Period {
+		return
+	}
+	n.Cycle = 0
+
+	// Channels are updated from channel 8 down
+	first := 7 - n.RAM[0x7F]>>4&7
+	if n.Channel < first || n.Channel > 7 {
+		n.Channel = 7
+	}
+	n.Sample = n.step(n.Channel)
+	if n.Channel == first {
+		n.Channel = 7
+	} else {
+		n.Channel--
+	}
+}
+
+// step advances a channel's phase and returns its output.
+func (n *N163) step(channel byte) int8 {
+	reg := n.RAM[0x40+int(channel)*8:][:8]
+	freq := uint32(reg[4]&3)<<16 | uint32(reg[2])<<8 | uint32(reg[0])
+	phase := uint32(reg[5])<<16 | uint32(reg[3])<<8 | uint32(reg[1])
+	length := 256 - uint32(reg[4]&0xFC)
+
+	phase = (phase + freq) % (length << 16)
+	reg[1] = byte(phase)
+	reg[3] = byte(phase >> 8)
+	reg[5] = byte(phase >> 16)
+
+	// Samples are 4 bits, with the low nibble first
+	addr := (phase>>16 + uint32(reg[6])) & 0xFF
+	sample := n.RAM[addr/2] >> (addr & 1 * 4) & 0xF
+	return (int8(sample) - 8) * int8(reg[7]&0xF)
+}
+
+func (n *N163) AudioOutput() float32 {
+	return float32(n.Sample) * n163Level
+}
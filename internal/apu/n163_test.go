@@ -0,0 +1,42 @@
+package apu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestN163(t *testing.T) {
+	t.Parallel()
+
+	var ram [N163RAMSize]byte
+	n := NewN163(ram[:])
+	// Square wave with 16 samples at address 0
+	for i := range 4 {
+		ram[i] = 0xFF
+	}
+	// Channel 8: full volume, length 16
+	ram[0x78] = 0
+	ram[0x7A] = 0x10
+	ram[0x7C] = 256 - 16
+	ram[0x7F] = 0xF
+
+	var lo, hi float32
+	for range n163Period * 1000 {
+		n.StepAudio()
+		lo = min(lo, n.AudioOutput())
+		hi = max(hi, n.AudioOutput())
+	}
+	assert.InDelta(t, 7*15*n163Level, hi, 0.0001)
+	assert.InDelta(t, -8*15*n163Level, lo, 0.0001)
+
+	// Channels 8 and 7 alternate when 2 channels are enabled
+	ram[0x7F] = 0x1F
+	for range n163Period * 3 {
+		n.StepAudio()
+	}
+	assert.Equal(t, byte(6), n.Channel)
+
+	n.SetDisabled(true)
+	assert.Zero(t, n.AudioOutput())
+}
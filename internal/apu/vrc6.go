@@ -0,0 +1,140 @@
+package apu
+
+// vrc6Level is the mixed output of one VRC6 volume step, so that a pulse at volume 15 matches a square channel.
+const vrc6Level = squareLevel / 15
+
+// VRC6 is the Konami VRC6's expansion audio: two pulse channels and a sawtooth channel.
+// Registers use the VRC6a (mapper 24) addresses.
+//
+// See [VRC6 audio].
+//
+// [VRC6 audio]: https://www.nesdev.org/wiki/VRC6_audio
+type VRC6 struct {
+	Pulse [2]VRC6Pulse
+	Saw   VRC6Saw
+
+	// Halt stops all channels from being clocked.
+	Halt bool
+	// FreqShift shifts every channel's period right by 4 or 8 bits.
+	FreqShift byte
+}
+
+// VRC6Pulse is one of the VRC6's pulse channels. It has 16 duty steps.
+type VRC6Pulse struct {
+	Enabled  bool
+	Constant bool
+	Duty     byte
+	Volume   byte
+
+	Period uint16
+	Timer  uint16
+	Step   byte
+}
+
+// VRC6Saw is the VRC6's sawtooth channel. It adds the rate to an accumulator every other clock, and resets after 14 clocks.
+type VRC6Saw struct {
+	Enabled bool
+	Rate    byte
+
+	Period uint16
+	Timer  uint16
+	Step   byte
+	Accum  byte
+}
+
+func (v *VRC6) Write(addr uint16, data byte) {
+	switch addr {
+	case 0x9000, 0xA000:
+		p := &v.Pulse[addr>>12-9]
+		p.Constant = data&0x80 != 0
+		p.Duty = data >> 4 & 7
+		p.Volume = data & 0xF
+	case 0x9001, 0xA001:
+		p := &v.Pulse[addr>>12-9]
+		p.Period = p.Period&0xF00 | uint16(data)
+	case 0x9002, 0xA002:
+		p := &v.Pulse[addr>>12-9]
+		p.Period = uint16(data&0xF)<<8 | p.Period&0xFF
+		p.Enabled = data&0x80 != 0
+		if !p.Enabled {
+			p.Step = 15
+		}
+	case 0x9003:
+		v.Halt = data&1 != 0
+		switch {
+		case data&4 != 0:
+			v.FreqShift = 8
+		case data&2 != 0:
+			v.FreqShift = 4
+		default:
+			v.FreqShift = 0
+		}
+	case 0xB000:
+		v.Saw.Rate = data & 0x3F
+	case 0xB001:
+		v.Saw.Period = v.Saw.Period&0xF00 | uint16(data)
+	case 0xB002:
+		v.Saw.Period = uint16(data&0xF)<<8 | v.Saw.Period&0xFF
+		v.Saw.Enabled = data&0x80 != 0
+		if !v.Saw.Enabled {
+			v.Saw.Step = 0
+			v.Saw.Accum = 0
+		}
+	}
+}
+
+func (v *VRC6) StepAudio() {
+	if v.Halt {
+		return
+	}
+	v.Pulse[0].stepTimer(v.FreqShift)
+	v.Pulse[1].stepTimer(v.FreqShift)
+	v.Saw.stepTimer(v.FreqShift)
+}
+
+func (v *VRC6) AudioOutput() float32 {
+	level := v.Pulse[0].output() + v.Pulse[1].output() + v.Saw.output()
+	return float32(level) * vrc6Level
+}
+
+func (p *VRC6Pulse) stepTimer(shift byte) {
+	if !p.Enabled {
+		return
+	}
+	if p.Timer == 0 {
+		p.Timer = p.Period >> shift
+		p.Step = (p.Step - 1) & 0xF
+	} else {
+		p.Timer--
+	}
+}
+
+func (p *VRC6Pulse) output() byte {
+	if p.Enabled && (p.Constant || p.Step <= p.Duty) {
+		return p.Volume
+	}
+	return 0
+}
+
+func (s *VRC6Saw) stepTimer(shift byte) {
+	if !s.Enabled {
+		return
+	}
+	if s.Timer == 0 {
+		s.Timer = s.Period >> shift
+		s.Step++
+		switch {
+		case s.Step == 14:
+			s.Step = 0
+			s.Accum = 0
+		case s.Step%2 == 0:
+			s.Accum += s.Rate
+		}
+	} else {
+		s.Timer--
+	}
+}
+
+func (s *VRC6Saw) output() byte {
+	return s.Accum >> 3
+}
@@ -0,0 +1,343 @@
+package apu
+
+import "math"
+
+const (
+	// vrc7Period is the number of CPU cycles between FM samples, which is about 49.7 kHz.
+	vrc7Period = 36
+	// vrc7Level is the mixed output of one FM channel at full volume.
+	vrc7Level = squareLevel
+
+	// vrc7EnvFrac is the number of fraction bits in an envelope's attenuation.
+	vrc7EnvFrac = 16
+	// vrc7EnvMax is the envelope attenuation where an operator is silent, in 0.375 dB steps.
+	vrc7EnvMax = 127
+	// vrc7PhaseBits is the size of an operator's phase counter. The top 10 bits index the sine table.
+	vrc7PhaseBits = 20
+	vrc7SineBits  = 10
+)
+
+const (
+	vrc7EnvOff byte = iota
+	vrc7EnvAttack
+	vrc7EnvDecay
+	vrc7EnvSustain
+	vrc7EnvRelease
+)
+
+//nolint:gochecknoglobals
+var (
+	// vrc7Patches are the built-in instruments. Instrument 0 is the custom instrument, set by registers $00-$07.
+	vrc7Patches = [16][8]byte{
+		{},
+		{0x03, 0x21, 0x05, 0x06, 0xE8, 0x81, 0x42, 0x27}, // Buzzy bell
+		{0x13, 0x41, 0x14, 0x0D, 0xD8, 0xF6, 0x23, 0x12}, // Guitar
+		{0x11, 0x11, 0x08, 0x08, 0xFA, 0xB2, 0x20, 0x12}, // Wurly
+		{0x31, 0x61, 0x0C, 0x07, 0xA8, 0x64, 0x61, 0x27}, // Flute
+		{0x32, 0x21, 0x1E, 0x06, 0xE1, 0x76, 0x01, 0x28}, // Clarinet
+		{0x02, 0x01, 0x06, 0x00, 0xA3, 0xE2, 0xF4, 0xF4}, // Synth
+		{0x21, 0x61, 0x1D, 0x07, 0x82, 0x81, 0x11, 0x07}, // Trumpet
+		{0x23, 0x21, 0x22, 0x17, 0xA2, 0x72, 0x01, 0x17}, // Organ
+		{0x35, 0x11, 0x25, 0x00, 0x40, 0x73, 0x72, 0x01}, // Bells
+		{0xB5, 0x01, 0x0F, 0x0F, 0xA8, 0xA5, 0x51, 0x02}, // Vibes
+		{0x17, 0xC1, 0x24, 0x07, 0xF8, 0xF8, 0x22, 0x12}, // Vibraphone
+		{0x71, 0x23, 0x11, 0x06, 0x65, 0x74, 0x18, 0x16}, // Tutti
+		{0x01, 0x02, 0xD3, 0x05, 0xC9, 0x95, 0x03, 0x02}, // Fretless
+		{0x61, 0x63, 0x0C, 0x00, 0x94, 0xC0, 0x33, 0xF6}, // Synth bass
+		{0x21, 0x72, 0x0D, 0x00, 0xC1, 0xD5, 0x56, 0x06}, // Sweep
+	}
+	// vrc7Multipliers are the frequency multipliers, doubled so that 1/2 is an integer.
+	vrc7Multipliers = [16]uint32{1, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 20, 24, 24, 30, 30}
+	// vrc7KeyScale is the key scale attenuation in dB for the top 4 bits of the frequency, in octave 7.
+	vrc7KeyScale = [16]float64{0, 9, 12, 13.875, 15, 16.125, 16.875, 17.625, 18, 18.75, 19.125, 19.5, 19.875, 20.25, 20.625, 21}
+	// vrc7Vibrato is the vibrato offset pattern, in multiples of the top 3 bits of the frequency.
+	vrc7Vibrato = [8]int{0, 1, 2, 1, 0, -1, -2, -1}
+
+	vrc7Sine        [1 << vrc7SineBits]float32
+	vrc7Attenuation [vrc7EnvMax + 1]float32
+)
+
+func init() { //nolint:gochecknoinits
+	for i := range vrc7Sine {
+		vrc7Sine[i] = float32(math.Sin(2 * math.Pi * float64(i) / float64(len(vrc7Sine))))
+	}
+	for i := range vrc7Attenuation[:vrc7EnvMax] {
+		vrc7Attenuation[i] = float32(math.Pow(10, -float64(i)*0.375/20))
+	}
+}
+
+// VRC7 is the Konami VRC7's expansion audio: a 6 channel FM synthesizer derived from the YM2413 (OPLL).
+// Each channel has a modulator and a carrier operator, and plays one of 15 built-in instruments or the custom instrument.
+//
+// Samples are generated at the chip's rate, then linearly interpolated to the CPU clock.
+//
+// See [VRC7 audio].
+//
+// [VRC7 audio]: https://www.nesdev.org/wiki/VRC7_audio
+type VRC7 struct {
+	// Addr is the register selected by $9010.
+	Addr     byte
+	Custom   [8]byte
+	Channels [6]VRC7Channel
+	// Silenced is set by the mapper's sound reset bit. All channels are cleared and no sound is output.
+	Silenced bool
+
+	Cycle uint
+	// LFO counts samples for vibrato and tremolo.
+	LFO        uint32
+	Sample     float32
+	PrevSample float32
+}
+
+// VRC7Channel is one of the VRC7's FM channels.
+type VRC7Channel struct {
+	FNum       uint16
+	Block      byte
+	Key        bool
+	Sustain    bool
+	Instrument byte
+	Volume     byte
+
+	// Operators are the modulator and the carrier.
+	Operators [2]VRC7Operator
+}
+
+// VRC7Operator is a sine wave generator with an envelope.
+type VRC7Operator struct {
+	Phase    uint32
+	EnvState byte
+	// Env is the envelope's attenuation in 0.375 dB steps, with vrc7EnvFrac fraction bits.
+	Env uint32
+	// Out is the last two outputs, which are used for modulator feedback.
+	Out [2]float32
+}
+
+func (v *VRC7) Write(addr uint16, data byte) {
+	switch addr {
+	case 0x9010:
+		v.Addr = data
+	case 0x9030:
+		if !v.Silenced {
+			v.writeReg(v.Addr, data)
+		}
+	}
+}
+
+func (v *VRC7) writeReg(reg, data byte) {
+	if reg < 8 {
+		v.Custom[reg] = data
+		return
+	}
+
+	i := reg & 0xF
+	if i >= byte(len(v.Channels)) {
+		return
+	}
+	c := &v.Channels[i]
+	switch reg & 0xF0 {
+	case 0x10:
+		c.FNum = c.FNum&0x100 | uint16(data)
+	case 0x20:
+		c.FNum = uint16(data&1)<<8 | c.FNum&0xFF
+		c.Block = data >> 1 & 7
+		c.Sustain = data&0x20 != 0
+		if key := data&0x10 != 0; key != c.Key {
+			c.Key = key
+			for j := range c.Operators {
+				if key {
+					c.Operators[j].keyOn()
+				} else {
+					c.Operators[j].keyOff()
+				}
+			}
+		}
+	case 0x30:
+		c.Instrument = data >> 4
+		c.Volume = data & 0xF
+	}
+}
+
+// SetSilenced sets the sound reset bit. Setting it clears all channels.
+func (v *VRC7) SetSilenced(silenced bool) {
+	v.Silenced = silenced
+	if silenced {
+		v.Channels = [6]VRC7Channel{}
+		v.Sample, v.PrevSample = 0, 0
+	}
+}
+
+func (v *VRC7) StepAudio() {
+	if v.Silenced {
+		return
+	}
+	v.Cycle++
+	if v.Cycle == vrc7Period {
+		v.Cycle = 0
+		v.PrevSample, v.Sample = v.Sample, v.sample()
+	}
+}
+
+func (v *VRC7) AudioOutput() float32 {
+	t := float32(v.Cycle) / vrc7Period
+	return (v.PrevSample + (v.Sample-v.PrevSample)*t) * vrc7Level
+}
+
+// sample generates the next sample from all channels.
+func (v *VRC7) sample() float32 {
+	v.LFO++
+	// Tremolo is a 4.8 dB triangle wave at 3.7 Hz
+	am := v.LFO >> 9 % 26
+	if am > 13 {
+		am = 26 - am
+	}
+	// Vibrato is 6.1 Hz
+	pm := vrc7Vibrato[v.LFO>>10%8]
+
+	var sum float32
+	for i := range v.Channels {
+		c := &v.Channels[i]
+		patch := &vrc7Patches[c.Instrument]
+		if c.Instrument == 0 {
+			patch = &v.Custom
+		}
+		sum += c.output(patch, am, pm)
+	}
+	return sum
+}
+
+func (c *VRC7Channel) output(patch *[8]byte, am uint32, pm int) float32 {
+	mod, car := &c.Operators[0], &c.Operators[1]
+	keyScale := c.Block<<1 | byte(c.FNum>>8)
+	for i := range c.Operators {
+		op := &c.Operators[i]
+		op.stepPhase(patch[i], c.FNum, c.Block, pm)
+		op.stepEnvelope(patch[i], patch[4+i], patch[6+i], keyScale, c.Sustain)
+	}
+
+	var feedback float32
+	if fb := patch[3] & 7; fb != 0 {
+		feedback = (mod.Out[0] + mod.Out[1]) * 4 / float32(int(1)<<(9-fb))
+	}
+	modAtten := uint32(patch[2]&0x3F)*2 + c.keyScaleLevel(patch[2]>>6)
+	modOut := mod.output(patch[0], patch[3]&0x08 != 0, feedback, modAtten, am)
+
+	carAtten := uint32(c.Volume)*8 + c.keyScaleLevel(patch[3]>>6)
+	return car.output(patch[1], patch[3]&0x10 != 0, modOut*4, carAtten, am)
+}
+
+// keyScaleLevel returns the attenuation for higher notes, in 0.375 dB steps.
+// Level 0 is off, and levels 1-3 are 1.5, 3, and 6 dB per octave.
+func (c *VRC7Channel) keyScaleLevel(level byte) uint32 {
+	if level == 0 {
+		return 0
+	}
+	atten := int(vrc7KeyScale[c.FNum>>5]*8/3) - 16*(7-int(c.Block))
+	if atten <= 0 {
+		return 0
+	}
+	return uint32(atten) >> (3 - level)
+}
+
+func (op *VRC7Operator) keyOn() {
+	if op.EnvState == vrc7EnvOff {
+		op.Env = vrc7EnvMax << vrc7EnvFrac
+	}
+	op.EnvState = vrc7EnvAttack
+	op.Phase = 0
+}
+
+func (op *VRC7Operator) keyOff() {
+	if op.EnvState != vrc7EnvOff {
+		op.EnvState = vrc7EnvRelease
+	}
+}
+
+func (op *VRC7Operator) stepPhase(reg byte, fnum uint16, block byte, pm int) {
+	// The frequency is doubled so that vibrato can move it by half steps
+	freq := 2 * int(fnum)
+	if reg&0x40 != 0 {
+		freq += int(fnum>>6) * pm
+	}
+	op.Phase += uint32(freq<<block) * vrc7Multipliers[reg&0xF] >> 1
+	op.Phase &= 1<<vrc7PhaseBits - 1
+}
+
+// envRate returns the envelope change per sample for a 4-bit rate, with vrc7EnvFrac fraction bits.
+// Rate 15 decays 48 dB in about 1 ms, and each lower rate takes twice as long.
+func envRate(rate, keyScale byte) uint32 {
+	if rate == 0 {
+		return 0
+	}
+	r := min(rate*4+keyScale, 63)
+	return uint32(4+r&3) << (r >> 2)
+}
+
+func (op *VRC7Operator) stepEnvelope(reg, attackDecay, sustainRelease, keyScale byte, sustain bool) {
+	if reg&0x10 == 0 {
+		keyScale >>= 2
+	}
+	sustained := reg&0x20 != 0
+
+	switch op.EnvState {
+	case vrc7EnvAttack:
+		// The attack is exponential
+		rate := envRate(attackDecay>>4, keyScale)
+		if rate != 0 {
+			step := max(uint32(uint64(op.Env)*uint64(rate)>>17), rate)
+			if rate >= 4<<15 || step >= op.Env {
+				op.Env = 0
+			} else {
+				op.Env -= step
+			}
+		}
+		if op.Env == 0 {
+			op.EnvState = vrc7EnvDecay
+		}
+	case vrc7EnvDecay:
+		op.Env += envRate(attackDecay&0xF, keyScale)
+		if level := uint32(sustainRelease>>4) * 8 << vrc7EnvFrac; op.Env >= level {
+			op.Env = level
+			op.EnvState = vrc7EnvSustain
+		}
+	case vrc7EnvSustain:
+		// Percussive instruments keep releasing while the key is held
+		if !sustained {
+			op.Env += envRate(sustainRelease&0xF, keyScale)
+		}
+	case vrc7EnvRelease:
+		var rate byte
+		switch {
+		case sustain:
+			rate = 5
+		case sustained:
+			rate = sustainRelease & 0xF
+		default:
+			rate = 7
+		}
+		op.Env += envRate(rate, keyScale)
+	}
+
+	if op.Env >= vrc7EnvMax<<vrc7EnvFrac {
+		op.Env = vrc7EnvMax << vrc7EnvFrac
+		if op.EnvState != vrc7EnvAttack {
+			op.EnvState = vrc7EnvOff
+		}
+	}
+}
+
+// output returns the operator's next output from -1 to 1. The phase is offset by modulation, in cycles.
+func (op *VRC7Operator) output(reg byte, rectified bool, modulation float32, atten, am uint32) float32 {
+	var out float32
+	if op.EnvState != vrc7EnvOff {
+		i := (op.Phase>>(vrc7PhaseBits-vrc7SineBits) + uint32(int32(modulation*(1<<vrc7SineBits)))) % (1 << vrc7SineBits)
+		if !rectified || i < 1<<(vrc7SineBits-1) {
+			atten += op.Env >> vrc7EnvFrac
+			if reg&0x80 != 0 {
+				atten += am
+			}
+			out = vrc7Sine[i] * vrc7Attenuation[min(atten, vrc7EnvMax)]
+		}
+	}
+	op.Out[1], op.Out[0] = op.Out[0], out
+	return out
+}
@@ -0,0 +1,48 @@
+package apu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestVRC7(t *testing.T) {
+	t.Parallel()
+
+	var v VRC7
+	write := func(reg, data byte) {
+		v.Write(0x9010, reg)
+		v.Write(0x9030, data)
+	}
+	// Flute at full volume, A4
+	write(0x30, 0x40)
+	write(0x10, 0x20)
+	write(0x20, 0x19)
+
+	var peak float32
+	for range vrc7Period * 1000 {
+		v.StepAudio()
+		peak = max(peak, v.AudioOutput())
+	}
+	assert.Greater(t, peak, float32(vrc7Level/4))
+
+	b, err := msgpack.Marshal(&v)
+	require.NoError(t, err)
+	var got VRC7
+	require.NoError(t, msgpack.Unmarshal(b, &got))
+	assert.Equal(t, v, got)
+
+	// Key off
+	write(0x20, 0x09)
+	for range vrc7Period * 50000 {
+		v.StepAudio()
+	}
+	assert.Equal(t, vrc7EnvOff, v.Channels[0].Operators[1].EnvState)
+	assert.Zero(t, v.AudioOutput())
+
+	write(0x20, 0x19)
+	v.SetSilenced(true)
+	assert.Equal(t, [6]VRC7Channel{}, v.Channels)
+}
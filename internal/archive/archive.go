@@ -0,0 +1,215 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Separator separates a zip file path from the name of a file inside the zip, like "games.zip#game.nes".
+const Separator = "#"
+
+var (
+	ErrNotFound = errors.New("file not found in archive")
+	ErrMultiple = errors.New("archive contains multiple files")
+)
+
+// Split splits a path into the archive path and the name of a file inside the archive.
+// The name is empty if the path does not select a file.
+func Split(p string) (string, string) {
+	if i := strings.LastIndex(p, Separator); i != -1 && strings.EqualFold(filepath.Ext(p[:i]), ".zip") {
+		return p[:i], p[i+len(Separator):]
+	}
+	return p, ""
+}
+
+// IsArchive reports whether a path is a zip or gzip file, or a file inside a zip.
+func IsArchive(p string) bool {
+	p, _ = Split(p)
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".zip", ".gz":
+		return true
+	default:
+		return false
+	}
+}
+
+// Resolve returns the name of the file that [ReadFile] will read.
+// Paths that are not archives are returned unchanged.
+func Resolve(p string, match func(string) bool) (string, error) {
+	if !IsArchive(p) {
+		return p, nil
+	}
+
+	p, inner := Split(p)
+	if strings.EqualFold(filepath.Ext(p), ".gz") {
+		f, err := os.Open(p)
+		if err != nil {
+			return "", err
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		return gzipName(p, gr), nil
+	}
+
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return "", err
+	}
+	defer func(zr *zip.ReadCloser) {
+		_ = zr.Close()
+	}(zr)
+
+	file, err := findFile(p, &zr.Reader, inner, match)
+	if err != nil {
+		return "", err
+	}
+	return file.Name, nil
+}
+
+// ReadFile reads a file, returning its name and contents.
+// If the path is a gzip file, it is decompressed.
+// If the path is a zip file, the file is found with the name after [Separator],
+// or with the only file that matches. Paths that are not archives are read unchanged.
+func ReadFile(p string, match func(string) bool) (string, []byte, error) {
+	if !IsArchive(p) {
+		b, err := os.ReadFile(p)
+		return p, b, err
+	}
+
+	p, inner := Split(p)
+	if strings.EqualFold(filepath.Ext(p), ".gz") {
+		f, err := os.Open(p)
+		if err != nil {
+			return "", nil, err
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", nil, err
+		}
+		b, err := io.ReadAll(gr)
+		if err != nil {
+			return "", nil, err
+		}
+		return gzipName(p, gr), b, nil
+	}
+
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func(zr *zip.ReadCloser) {
+		_ = zr.Close()
+	}(zr)
+
+	file, err := findFile(p, &zr.Reader, inner, match)
+	if err != nil {
+		return "", nil, err
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return "", nil, err
+	}
+	defer func(f io.ReadCloser) {
+		_ = f.Close()
+	}(f)
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", nil, err
+	}
+	return file.Name, b, nil
+}
+
+// List returns a path for each matching file in a zip.
+// A gzip file is only returned if the file inside it matches.
+// Each path can be passed to [ReadFile]. Other paths are returned unchanged.
+func List(p string, match func(string) bool) ([]string, error) {
+	if strings.EqualFold(filepath.Ext(p), ".gz") {
+		name, err := Resolve(p, match)
+		if err != nil {
+			return nil, err
+		}
+		if !match(name) {
+			return nil, nil
+		}
+		return []string{p}, nil
+	}
+
+	if p, inner := Split(p); inner != "" || !strings.EqualFold(filepath.Ext(p), ".zip") {
+		return []string{p}, nil
+	}
+
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func(zr *zip.ReadCloser) {
+		_ = zr.Close()
+	}(zr)
+
+	var paths []string
+	for _, file := range zr.File {
+		if !file.FileInfo().IsDir() && match(file.Name) {
+			paths = append(paths, p+Separator+file.Name)
+		}
+	}
+	return paths, nil
+}
+
+// gzipName returns the original file name from the gzip header, or the path without its .gz extension.
+func gzipName(p string, gr *gzip.Reader) string {
+	if gr.Name != "" {
+		return filepath.Join(filepath.Dir(p), filepath.Base(gr.Name))
+	}
+	return strings.TrimSuffix(p, filepath.Ext(p))
+}
+
+// findFile finds a file in a zip by name. If name is empty, the only file that matches is returned.
+func findFile(p string, zr *zip.Reader, name string, match func(string) bool) (*zip.File, error) {
+	var found *zip.File
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		if name != "" {
+			if file.Name == name || path.Base(file.Name) == name {
+				return file, nil
+			}
+			continue
+		}
+
+		if match(file.Name) {
+			if found != nil {
+				return nil, fmt.Errorf("%w: choose one with %q", ErrMultiple, p+Separator+found.Name)
+			}
+			found = file
+		}
+	}
+
+	if found == nil {
+		if name == "" {
+			name = "ROM"
+		}
+		return nil, fmt.Errorf("%w: %s: %s", ErrNotFound, p, name)
+	}
+	return found, nil
+}
@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func isNES(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".nes")
+}
+
+func writeZip(t *testing.T, files map[string]string) string {
+	path := filepath.Join(t.TempDir(), "roms.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+	return path
+}
+
+func writeGzip(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gw := gzip.NewWriter(f)
+	_, err = gw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, f.Close())
+	return path
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		path      string
+		wantPath  string
+		wantInner string
+	}{
+		{"game.nes", "game.nes", ""},
+		{"roms.zip", "roms.zip", ""},
+		{"roms.zip#game.nes", "roms.zip", "game.nes"},
+		{"dir/ROMS.ZIP#sub/game.nes", "dir/ROMS.ZIP", "sub/game.nes"},
+		{"#1 game.nes", "#1 game.nes", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			path, inner := Split(tt.path)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantInner, inner)
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zip", func(t *testing.T) {
+		t.Parallel()
+		path := writeZip(t, map[string]string{"readme.txt": "readme", "game.nes": "game"})
+		name, b, err := ReadFile(path, isNES)
+		require.NoError(t, err)
+		assert.Equal(t, "game.nes", name)
+		assert.Equal(t, "game", string(b))
+
+		name, err = Resolve(path, isNES)
+		require.NoError(t, err)
+		assert.Equal(t, "game.nes", name)
+	})
+
+	t.Run("zip multiple", func(t *testing.T) {
+		t.Parallel()
+		path := writeZip(t, map[string]string{"a.nes": "a", "dir/b.nes": "b"})
+		_, _, err := ReadFile(path, isNES)
+		require.ErrorIs(t, err, ErrMultiple)
+
+		name, b, err := ReadFile(path+Separator+"b.nes", isNES)
+		require.NoError(t, err)
+		assert.Equal(t, "dir/b.nes", name)
+		assert.Equal(t, "b", string(b))
+
+		paths, err := List(path, isNES)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{path + "#a.nes", path + "#dir/b.nes"}, paths)
+	})
+
+	t.Run("zip not found", func(t *testing.T) {
+		t.Parallel()
+		path := writeZip(t, map[string]string{"readme.txt": "readme"})
+		_, _, err := ReadFile(path, isNES)
+		require.ErrorIs(t, err, ErrNotFound)
+		_, _, err = ReadFile(path+Separator+"game.nes", isNES)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		t.Parallel()
+		path := writeGzip(t, "game.nes.gz", "game")
+		name, b, err := ReadFile(path, isNES)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSuffix(path, ".gz"), name)
+		assert.Equal(t, "game", string(b))
+
+		paths, err := List(path, isNES)
+		require.NoError(t, err)
+		assert.Equal(t, []string{path}, paths)
+	})
+
+	t.Run("gzip not matching", func(t *testing.T) {
+		t.Parallel()
+		path := writeGzip(t, "music.nsf.gz", "music")
+		paths, err := List(path, isNES)
+		require.NoError(t, err)
+		assert.Empty(t, paths)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "game.nes")
+		require.NoError(t, os.WriteFile(path, []byte("game"), 0o644))
+		name, b, err := ReadFile(path, isNES)
+		require.NoError(t, err)
+		assert.Equal(t, path, name)
+		assert.Equal(t, "game", string(b))
+	})
+}
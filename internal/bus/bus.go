@@ -9,27 +9,107 @@ import (
 )
 
 func New(conf *config.Config, mapper cartridge.Mapper, ppu *ppu.PPU, apu *apu.APU) *Bus {
-	return &Bus{
-		mapper:      mapper,
-		apu:         apu,
-		ppu:         ppu,
-		controller1: controller.NewController(conf, controller.Player1),
-		controller2: controller.NewController(conf, controller.Player2),
+	b := &Bus{
+		mapper:   mapper,
+		apu:      apu,
+		ppu:      ppu,
+		multiTap: conf.Input.MultiTap,
+		controllers: [4]controller.Controller{
+			controller.NewController(conf, controller.Player1),
+			controller.NewController(conf, controller.Player2),
+			controller.NewController(conf, controller.Player3),
+			controller.NewController(conf, controller.Player4),
+		},
+	}
+	b.ports[0] = b.newDevice(conf, 0, conf.Input.Port1)
+	b.ports[1] = b.newDevice(conf, 1, conf.Input.Port2)
+	return b
+}
+
+// newDevice creates the device for a controller port (0 or 1).
+func (b *Bus) newDevice(conf *config.Config, port int, p config.Port) controller.Device {
+	switch p.Type {
+	case config.DeviceZapper:
+		return controller.NewZapper(conf, b.ppu)
+	case config.DeviceArkanoid:
+		return controller.NewArkanoid(conf)
+	case config.DevicePowerPad:
+		return controller.NewPowerPad(p.PowerPad)
+	}
+
+	first, second := &b.controllers[port], &b.controllers[port+2]
+	switch b.multiTap {
+	case config.MultiTapFourScore:
+		return b.fourScore.Port(port, first, second)
+	case config.MultiTapFamicom:
+		return controller.Expansion{First: first, Second: second}
+	default:
+		return first
 	}
 }
 
 type Bus struct {
-	CPUVRAM     [0x800]byte `msgpack:"alias:CpuVram"`
-	mapper      cartridge.Mapper
-	apu         *apu.APU
-	ppu         *ppu.PPU
-	controller1 controller.Controller
-	controller2 controller.Controller
-	OpenBus     byte
+	CPUVRAM [0x800]byte `msgpack:"alias:CpuVram"`
+	mapper  cartridge.Mapper
+	apu     *apu.APU
+	ppu     *ppu.PPU
+	OpenBus byte
+
+	ports       [2]controller.Device
+	controllers [4]controller.Controller
+	multiTap    config.MultiTap
+	fourScore   controller.FourScore
+
+	watcher Watcher
+	patcher Patcher
+}
+
+// Watcher is notified of every CPU memory access.
+type Watcher interface {
+	OnRead(addr uint16, data byte)
+	OnWrite(addr uint16, data byte)
+}
+
+// SetWatcher sets the memory access watcher. Pass nil to disable watching.
+func (b *Bus) SetWatcher(w Watcher) {
+	b.watcher = w
+}
+
+// Patcher can replace bytes read from cartridge ROM, like a Game Genie.
+type Patcher interface {
+	PatchRead(addr uint16, data byte) byte
+}
+
+// SetPatcher sets the ROM read patcher. Pass nil to disable patching.
+func (b *Bus) SetPatcher(p Patcher) {
+	b.patcher = p
+}
+
+// Watchers notifies each Watcher in order.
+type Watchers []Watcher
+
+func (w Watchers) OnRead(addr uint16, data byte) {
+	for _, watcher := range w {
+		watcher.OnRead(addr, data)
+	}
+}
+
+func (w Watchers) OnWrite(addr uint16, data byte) {
+	for _, watcher := range w {
+		watcher.OnWrite(addr, data)
+	}
 }
 
 // ReadMem reads a byte from memory.
 func (b *Bus) ReadMem(addr uint16) byte {
+	data := b.readMem(addr)
+	if b.watcher != nil {
+		b.watcher.OnRead(addr, data)
+	}
+	return data
+}
+
+func (b *Bus) readMem(addr uint16) byte {
 	switch {
 	case addr < 0x2000:
 		addr &= 0x07FF
@@ -42,19 +122,30 @@ func (b *Bus) ReadMem(addr uint16) byte {
 	case addr == 0x4015:
 		return (b.apu.ReadMem(addr) & 0xDF) | (b.OpenBus & 0x20)
 	case addr == 0x4016:
-		b.OpenBus &^= 0xF
-		b.OpenBus |= b.controller1.Read()
+		b.OpenBus &^= 0x1F
+		b.OpenBus |= b.ports[0].Read()
 	case addr == 0x4017:
-		b.OpenBus &^= 0xF
-		b.OpenBus |= b.controller2.Read()
+		b.OpenBus &^= 0x1F
+		b.OpenBus |= b.ports[1].Read()
 	case addr >= 0x4020:
 		if addr < 0x6000 {
-			switch b.mapper.(type) {
+			switch mapper := b.mapper.(type) {
 			case *cartridge.Mapper2, *cartridge.Mapper3, *cartridge.Mapper7:
 				return b.OpenBus
+			case *cartridge.MapperFDS:
+				if 0x4040 <= addr && addr <= 0x4097 {
+					// FDS audio registers only drive the low 6 bits
+					if data, ok := mapper.Audio.Read(addr); ok {
+						b.OpenBus = b.OpenBus&0xC0 | data
+					}
+					return b.OpenBus
+				}
 			}
 		}
 		b.OpenBus = b.mapper.ReadMem(addr)
+		if b.patcher != nil && addr >= 0x8000 {
+			b.OpenBus = b.patcher.PatchRead(addr, b.OpenBus)
+		}
 	}
 	return b.OpenBus
 }
@@ -64,37 +155,65 @@ func (b *Bus) ReadMemSafe(addr uint16) byte {
 	switch {
 	case 0x2001 <= addr && addr < 0x4000,
 		0x4004 <= addr && addr <= 0x4007,
-		0x4015 <= addr && addr <= 0x4017:
+		0x4015 <= addr && addr <= 0x4017,
+		0x4030 <= addr && addr <= 0x4031,
+		0x4800 <= addr && addr < 0x5000,
+		addr == 0x5204:
 		return 0xFF
 	default:
-		return b.ReadMem(addr)
+		return b.readMem(addr)
 	}
 }
 
 // WriteMem writes a byte to memory.
 func (b *Bus) WriteMem(addr uint16, data byte) {
+	if b.watcher != nil {
+		b.watcher.OnWrite(addr, data)
+	}
+
 	switch {
 	case addr < 0x2000:
 		addr &= 0x07FF
 		b.CPUVRAM[addr] = data
 	case addr <= 0x2007, addr == 0x4014:
 		b.ppu.WriteMem(addr, data)
+		if mapper, ok := b.mapper.(cartridge.MapperOnPPUWrite); ok && addr != 0x4014 {
+			mapper.OnPPUWrite(addr, data)
+		}
 		return
 	case addr < 0x4000:
 		addr &= 0x2007
 		b.ppu.WriteMem(addr, data)
+		if mapper, ok := b.mapper.(cartridge.MapperOnPPUWrite); ok {
+			mapper.OnPPUWrite(addr, data)
+		}
 		return
 	case addr <= 0x4013, addr == 0x4015, addr == 0x4017:
 		b.apu.WriteMem(addr, data)
 	case addr == 0x4016:
-		b.controller1.Write(data)
-		b.controller2.Write(data)
+		for _, port := range b.ports {
+			port.Write(data)
+		}
 	case addr >= 0x4020:
 		b.mapper.WriteMem(addr, data)
 	}
 	b.OpenBus = data
 }
 
+// WriteMemSafe writes a byte to RAM or cartridge SRAM. Writes to any other address are ignored,
+// since they would have side effects. It returns false if the write was ignored.
+func (b *Bus) WriteMemSafe(addr uint16, data byte) bool {
+	switch {
+	case addr < 0x2000:
+		b.CPUVRAM[addr&0x07FF] = data
+	case 0x6000 <= addr && addr < 0x8000:
+		b.mapper.WriteMem(addr, data)
+	default:
+		return false
+	}
+	return true
+}
+
 // ReadMem16 reads two bytes from memory.
 func (b *Bus) ReadMem16(addr uint16) uint16 {
 	lo := uint16(b.ReadMem(addr))
@@ -111,8 +230,34 @@ func (b *Bus) WriteMem16(addr uint16, data uint16) {
 }
 
 func (b *Bus) UpdateInput() {
-	b.controller1.UpdateInput()
-	b.controller2.UpdateInput()
+	for _, port := range b.ports {
+		port.UpdateInput()
+	}
+}
+
+// Port returns the device plugged into a controller port (0 or 1).
+func (b *Bus) Port(port int) controller.Device {
+	return b.ports[port]
+}
+
+// Zapper returns the Zapper, or nil if neither port has a Zapper.
+func (b *Bus) Zapper() *controller.Zapper {
+	for _, port := range b.ports {
+		if zapper, ok := port.(*controller.Zapper); ok {
+			return zapper
+		}
+	}
+	return nil
+}
+
+// Players returns the number of connected controllers.
+func (b *Bus) Players() int {
+	return b.multiTap.Players()
+}
+
+// Controller returns the controller for a player (0 to 3).
+func (b *Bus) Controller(player int) *controller.Controller {
+	return &b.controllers[player]
 }
 
 func (b *Bus) SetMapper(m cartridge.Mapper) {
@@ -1,12 +1,14 @@
 package cartridge
 
 import (
+	"bytes"
 	"crypto/md5"
 	"fmt"
 	"log/slog"
 	"path/filepath"
 	"strings"
 
+	"gabe565.com/gones/internal/archive"
 	"gabe565.com/gones/internal/consts"
 	"gabe565.com/gones/internal/database"
 	"gabe565.com/gones/internal/interrupt"
@@ -22,6 +24,20 @@ type Cartridge struct {
 	SRAM    []byte `msgpack:"alias:Sram"`
 	Mirror  Mirror
 	Battery bool `msgpack:"-"`
+
+	// Timing is the region that the game was made for.
+	Timing          Timing          `msgpack:"-"`
+	ConsoleType     ConsoleType     `msgpack:"-"`
+	ExpansionDevice ExpansionDevice `msgpack:"-"`
+	// MiscROM contains NES 2.0 miscellaneous ROM data that follows CHR ROM.
+	MiscROM []byte `msgpack:"-"`
+	// Board is the UNIF board name.
+	Board string `msgpack:"-"`
+	// singleScreen is a UNIF board's single screen mirroring, which can't be stored in the header.
+	singleScreen *Mirror
+
+	// Disk is set when an FDS disk image is loaded instead of a cartridge.
+	Disk *Disk
 }
 
 func New() *Cartridge {
@@ -51,6 +67,56 @@ func FromBytes(b []byte) *Cartridge {
 	return cart
 }
 
+// FromFile loads an iNES, UNIF, or FDS file, based on the file extension.
+// The file can be inside a zip or gzip archive. See [archive.ReadFile].
+func FromFile(path string) (*Cartridge, error) {
+	if !archive.IsArchive(path) {
+		switch {
+		case IsFDSFile(path):
+			return FromFDSFile(path)
+		case IsUNIFFile(path):
+			return FromUNIFFile(path)
+		default:
+			return FromINESFile(path)
+		}
+	}
+
+	name, b, err := archive.ReadFile(path, IsROMFile)
+	if err != nil {
+		return nil, err
+	}
+	return FromData(name, b)
+}
+
+// FromData loads an iNES, UNIF, or FDS file that has already been read, based on the name's extension.
+// If the game is not in the database, the name is used as the title.
+func FromData(name string, b []byte) (*Cartridge, error) {
+	var cartridge *Cartridge
+	var err error
+	switch r := bytes.NewReader(b); {
+	case IsFDSFile(name):
+		cartridge, err = FromFDS(r)
+	case IsUNIFFile(name):
+		cartridge, err = FromUNIF(r)
+	default:
+		cartridge, err = FromINES(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cartridge.name == "" {
+		name = filepath.Base(name)
+		cartridge.name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return cartridge, nil
+}
+
+// IsROMFile reports whether a path has an iNES, UNIF, or FDS extension.
+func IsROMFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".nes") || IsUNIFFile(path) || IsFDSFile(path)
+}
+
 func (c *Cartridge) Name() string {
 	return c.name
 }
@@ -70,7 +136,15 @@ func (c *Cartridge) LogValue() slog.Value {
 }
 
 // CHRIsRAM reports whether the cartridge provides CHR RAM instead of ROM.
-// Per iNES, this is true when the CHR count in the header is zero.
+// Per iNES, this is true when the CHR size in the header is zero.
+// PowerOnMirror returns the mirroring that the cartridge starts with.
+func (c *Cartridge) PowerOnMirror() Mirror {
+	if c.singleScreen != nil {
+		return *c.singleScreen
+	}
+	return c.Header.Mirror()
+}
+
 func (c *Cartridge) CHRIsRAM() bool {
-	return c.Header.CHRCount == 0
+	return c.Header.CHRSize() == 0
 }
@@ -0,0 +1,51 @@
+package cartridge
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCartridge returns a cartridge with 8 KiB PRG banks and 1 KiB CHR banks, where each byte is its bank number.
+func testCartridge(prgBanks, chrBanks int) *Cartridge {
+	cart := New()
+	cart.PRG = make([]byte, prgBanks*0x2000)
+	for i := range cart.PRG {
+		cart.PRG[i] = byte(i / 0x2000)
+	}
+	cart.CHR = make([]byte, chrBanks*0x400)
+	for i := range cart.CHR {
+		cart.CHR[i] = byte(i / 0x400)
+	}
+	return cart
+}
+
+func TestFromFile(t *testing.T) {
+	t.Parallel()
+
+	rom := testUNIF(
+		unifChunk("MAPR", []byte("NES-NROM-256")),
+		unifChunk("PRG0", make([]byte, 0x8000)),
+	)
+	want, err := FromUNIF(bytes.NewReader(rom))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "game.unf.gz")
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(rom)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	cart, err := FromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want.Hash(), cart.Hash(), "hash should be the ROM's hash")
+	assert.Equal(t, "game", cart.Name())
+	assert.Equal(t, want.PRG, cart.PRG)
+}
@@ -0,0 +1,327 @@
+package cartridge
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/database"
+	"gabe565.com/gones/internal/patch"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	// FDSSideSize is the size of a disk side in an .fds image.
+	FDSSideSize = 65500
+	// QDSideSize is the size of a disk side in a .qd image, which also stores each block's CRC.
+	QDSideSize = 0x10000
+	// FDSBIOSSize is the size of the FDS BIOS.
+	FDSBIOSSize = 0x2000
+
+	fdsHeaderSize = 16
+	// fdsLeadIn is the gap before the first block, in bytes.
+	fdsLeadIn = 28300 / 8
+	// fdsBlockGap is the gap after each block, in bytes.
+	fdsBlockGap = 976 / 8
+	// fdsRawSideSize is the minimum size of a side as the drive reads it, including gaps, block start marks, and CRCs.
+	// It leaves room after the last block for games that write new files.
+	fdsRawSideSize = 0x13000
+	// fdsBlockStart is the mark that the drive reads at the end of each gap.
+	fdsBlockStart = 0x80
+)
+
+//nolint:gochecknoglobals
+var (
+	fdsMagic     = []byte{'F', 'D', 'S', 0x1A}
+	fdsDiskMagic = []byte("\x01*NINTENDO-HVC*")
+)
+
+var (
+	ErrInvalidFDS  = errors.New("invalid FDS disk image")
+	ErrInvalidBIOS = errors.New("invalid FDS BIOS")
+)
+
+// Disk is a Famicom Disk System disk.
+//
+// See [FDS disk format].
+//
+// [FDS disk format]: https://www.nesdev.org/wiki/FDS_disk_format
+type Disk struct {
+	// Sides contains each side as the drive reads it, with gaps, block start marks, and CRCs.
+	Sides [][]byte
+
+	original [][]byte
+	// diff caches the result of [Disk.Diff] until the disk changes.
+	diff  []byte
+	dirty bool
+}
+
+// NewDisk parses an .fds or .qd disk image.
+func NewDisk(b []byte) (*Disk, error) {
+	if bytes.HasPrefix(b, fdsMagic) {
+		if len(b) < fdsHeaderSize {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFDS, "truncated header")
+		}
+		b = b[fdsHeaderSize:]
+	}
+
+	sideSize, hasCRC := FDSSideSize, false
+	switch {
+	case len(b) != 0 && len(b)%FDSSideSize == 0:
+	case len(b) != 0 && len(b)%QDSideSize == 0:
+		sideSize, hasCRC = QDSideSize, true
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFDS, "unexpected size")
+	}
+
+	disk := &Disk{dirty: true}
+	for side := range slices.Chunk(b, sideSize) {
+		if !bytes.HasPrefix(side, fdsDiskMagic) {
+			return nil, fmt.Errorf("%w: side %d: %s", ErrInvalidFDS, len(disk.Sides), "missing disk header")
+		}
+		disk.Sides = append(disk.Sides, rawSide(side, hasCRC))
+	}
+
+	disk.original = make([][]byte, len(disk.Sides))
+	for i, side := range disk.Sides {
+		disk.original[i] = slices.Clone(side)
+	}
+	return disk, nil
+}
+
+// rawSide converts a side from a disk image to the format that the drive reads.
+func rawSide(side []byte, hasCRC bool) []byte {
+	raw := make([]byte, fdsLeadIn, fdsRawSideSize)
+	var fileSize int
+blocks:
+	for i := 0; i < len(side); {
+		var size int
+		switch side[i] {
+		case 1:
+			// Disk info
+			size = 56
+		case 2:
+			// File amount
+			size = 2
+		case 3:
+			// File header
+			size = 16
+		case 4:
+			// File data
+			size = 1 + fileSize
+		default:
+			break blocks
+		}
+		if i+size > len(side) {
+			break
+		}
+		block := side[i : i+size]
+		if block[0] == 3 {
+			fileSize = int(binary.LittleEndian.Uint16(block[13:]))
+		}
+
+		raw = append(raw, fdsBlockStart)
+		raw = append(raw, block...)
+		crc := fdsCRC(raw[len(raw)-size-1:])
+		raw = append(raw, byte(crc), byte(crc>>8))
+		raw = append(raw, make([]byte, fdsBlockGap)...)
+
+		i += size
+		if hasCRC {
+			i += 2
+		}
+	}
+
+	if len(raw) < fdsRawSideSize {
+		raw = raw[:fdsRawSideSize]
+	}
+	return raw
+}
+
+// updateFDSCRC adds a byte to an FDS block CRC.
+func updateFDSCRC(crc uint16, data byte) uint16 {
+	for bit := range 8 {
+		carry := crc&1 != 0
+		crc >>= 1
+		if carry {
+			crc ^= 0x8408
+		}
+		if data>>bit&1 != 0 {
+			crc ^= 0x8000
+		}
+	}
+	return crc
+}
+
+// fdsCRC calculates the CRC of a block, including its start mark.
+func fdsCRC(block []byte) uint16 {
+	var crc uint16
+	for _, b := range block {
+		crc = updateFDSCRC(crc, b)
+	}
+	crc = updateFDSCRC(crc, 0)
+	return updateFDSCRC(crc, 0)
+}
+
+// SideName returns a human-readable name for a side index.
+func SideName(side int) string {
+	return fmt.Sprintf("disk %d side %c", side/2+1, 'A'+side%2)
+}
+
+// Write stores a byte on a side.
+func (d *Disk) Write(side, pos int, data byte) {
+	if d.Sides[side][pos] != data {
+		d.Sides[side][pos] = data
+		d.dirty = true
+	}
+}
+
+// Diff returns an IPS patch containing every change written to the disk since it was loaded.
+// The patch applies to the sides as the drive reads them, not to the original image.
+// The patch is cached until the next write, since save states call Diff often.
+func (d *Disk) Diff() ([]byte, error) {
+	if !d.dirty {
+		return d.diff, nil
+	}
+
+	diff, err := patch.DiffIPS(slices.Concat(d.original...), slices.Concat(d.Sides...))
+	if err != nil {
+		return nil, err
+	}
+	d.diff, d.dirty = diff, false
+	return diff, nil
+}
+
+// Modified reports whether the disk has been written to since it was loaded.
+func (d *Disk) Modified() bool {
+	for i, side := range d.Sides {
+		if !bytes.Equal(side, d.original[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset restores the disk to its original contents.
+func (d *Disk) Reset() {
+	for i, side := range d.original {
+		d.Sides[i] = slices.Clone(side)
+	}
+	d.dirty = true
+}
+
+// Apply restores the disk to its original contents, then applies a patch from [Disk.Diff].
+func (d *Disk) Apply(p []byte) error {
+	b, err := patch.ApplyIPS(slices.Concat(d.original...), p)
+	if err != nil {
+		return err
+	}
+
+	for i, side := range d.original {
+		if len(b) < len(side) {
+			return fmt.Errorf("%w: patch does not match disk", ErrInvalidFDS)
+		}
+		d.Sides[i] = b[:len(side):len(side)]
+		b = b[len(side):]
+	}
+	d.dirty = true
+	return nil
+}
+
+// EncodeMsgpack stores disk writes in save states as a patch, since the full disk is large.
+func (d *Disk) EncodeMsgpack(enc *msgpack.Encoder) error {
+	diff, err := d.Diff()
+	if err != nil {
+		return err
+	}
+	return enc.EncodeBytes(diff)
+}
+
+func (d *Disk) DecodeMsgpack(dec *msgpack.Decoder) error {
+	diff, err := dec.DecodeBytes()
+	if err != nil {
+		return err
+	}
+	return d.Apply(diff)
+}
+
+func FromFDSFile(path string) (*Cartridge, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	cartridge, err := FromFDS(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if cartridge.name == "" {
+		cartridge.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return cartridge, nil
+}
+
+// FromFDS loads an .fds or .qd disk image.
+// The BIOS must be loaded with [Cartridge.LoadBIOS] before the disk can run.
+func FromFDS(r io.Reader) (*Cartridge, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := NewDisk(b)
+	if err != nil {
+		return nil, err
+	}
+
+	cartridge := New()
+	cartridge.Header.CHRCount = 0
+	cartridge.CHR = make([]byte, consts.CHRChunkSize)
+	// RAM adapter PRG RAM at $6000-$DFFF
+	cartridge.SRAM = make([]byte, 0x8000)
+	cartridge.Disk = disk
+
+	slog.Debug("Loaded FDS disk", "sides", len(disk.Sides))
+
+	sum := md5.Sum(b)
+	cartridge.hash = hex.EncodeToString(sum[:])
+	cartridge.name, _ = database.FindNameByHash(cartridge.hash)
+	return cartridge, nil
+}
+
+// IsFDSFile reports whether a path has an FDS disk image extension.
+func IsFDSFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".fds", ".qd":
+		return true
+	default:
+		return false
+	}
+}
+
+// LoadBIOS loads the FDS BIOS (usually named disksys.rom) into PRG ROM.
+func (c *Cartridge) LoadBIOS(path string) error {
+	bios, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(bios) != FDSBIOSSize {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidBIOS, FDSBIOSSize, len(bios))
+	}
+	c.PRG = bios
+	return nil
+}
@@ -0,0 +1,152 @@
+package cartridge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFDSSide returns a side with a disk info block, a file amount block, and one file.
+func testFDSSide(crc bool) []byte {
+	var side []byte
+	addBlock := func(block []byte) {
+		side = append(side, block...)
+		if crc {
+			side = append(side, 0, 0)
+		}
+	}
+
+	info := make([]byte, 56)
+	copy(info, fdsDiskMagic)
+	addBlock(info)
+	addBlock([]byte{2, 1})
+	header := make([]byte, 16)
+	header[0] = 3
+	header[13] = 4
+	addBlock(header)
+	addBlock([]byte{4, 0xDE, 0xAD, 0xBE, 0xEF})
+
+	size := FDSSideSize
+	if crc {
+		size = QDSideSize
+	}
+	return append(side, make([]byte, size-len(side))...)
+}
+
+func TestNewDisk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		image []byte
+		sides int
+	}{
+		{"fds", testFDSSide(false), 1},
+		{"fds header", append(append([]byte{'F', 'D', 'S', 0x1A, 2}, make([]byte, 11)...), bytes.Repeat(testFDSSide(false), 2)...), 2},
+		{"qd", testFDSSide(true), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			disk, err := NewDisk(tt.image)
+			require.NoError(t, err)
+			require.Len(t, disk.Sides, tt.sides)
+
+			side := disk.Sides[0]
+			assert.Len(t, side, fdsRawSideSize)
+			assert.Equal(t, make([]byte, fdsLeadIn), side[:fdsLeadIn])
+
+			// Disk info block
+			block := side[fdsLeadIn:]
+			assert.EqualValues(t, fdsBlockStart, block[0])
+			assert.Equal(t, fdsDiskMagic, block[1:1+len(fdsDiskMagic)])
+			crc := fdsCRC(block[:57])
+			assert.Equal(t, []byte{byte(crc), byte(crc >> 8)}, block[57:59])
+
+			// File data block
+			data := []byte{fdsBlockStart, 4, 0xDE, 0xAD, 0xBE, 0xEF}
+			assert.True(t, bytes.Contains(side, data))
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewDisk(make([]byte, FDSSideSize))
+		require.ErrorIs(t, err, ErrInvalidFDS)
+		_, err = NewDisk([]byte("FDS\x1a"))
+		require.ErrorIs(t, err, ErrInvalidFDS)
+	})
+}
+
+func TestDisk_Diff(t *testing.T) {
+	t.Parallel()
+
+	disk, err := NewDisk(bytes.Repeat(testFDSSide(false), 2))
+	require.NoError(t, err)
+	assert.False(t, disk.Modified())
+
+	disk.Write(1, 0x1000, 0xFF)
+	assert.True(t, disk.Modified())
+	diff, err := disk.Diff()
+	require.NoError(t, err)
+	cached, err := disk.Diff()
+	require.NoError(t, err)
+	assert.Same(t, &diff[0], &cached[0], "diff should be cached until the next write")
+	want := [][]byte{bytes.Clone(disk.Sides[0]), bytes.Clone(disk.Sides[1])}
+
+	disk.Reset()
+	assert.False(t, disk.Modified())
+
+	require.NoError(t, disk.Apply(diff))
+	assert.Equal(t, want, disk.Sides)
+}
+
+func TestMapperFDS_Read(t *testing.T) {
+	t.Parallel()
+
+	cart := New()
+	var err error
+	cart.Disk, err = NewDisk(testFDSSide(false))
+	require.NoError(t, err)
+	cart.PRG = make([]byte, FDSBIOSSize)
+	cart.SRAM = make([]byte, 0x8000)
+	cart.CHR = make([]byte, 0x2000)
+	m := NewMapperFDS(cart)
+
+	// Enable disk registers, then start the motor in read mode and wait for the first block
+	m.WriteMem(0x4023, 0x01)
+	m.WriteMem(0x4025, 0xCD)
+
+	var got []byte
+	for range fdsRewindCycles + (fdsLeadIn+20)*(fdsByteCycles+1) {
+		m.OnCPUStep(1)
+		if m.IRQ() {
+			got = append(got, m.ReadMem(0x4031))
+		}
+	}
+	require.NotEmpty(t, got)
+	assert.Equal(t, fdsDiskMagic, got[:len(fdsDiskMagic)])
+	assert.Equal(t, Horizontal, cart.Mirror)
+
+	m.SwitchSide()
+	assert.Equal(t, byte(0x07), m.ReadMem(0x4032)&0x07)
+	m.OnCPUStep(fdsInsertCycles)
+	assert.Equal(t, 0, m.Side)
+}
+
+func TestMapperFDS_Audio(t *testing.T) {
+	t.Parallel()
+
+	cart := New()
+	cart.PRG = make([]byte, FDSBIOSSize)
+	cart.SRAM = make([]byte, 0x8000)
+	cart.CHR = make([]byte, 0x2000)
+	m := NewMapperFDS(cart)
+
+	m.WriteMem(0x4089, 0x80)
+	m.WriteMem(0x4040, 0x3F)
+	assert.Equal(t, byte(0x3F), m.Audio.WaveTable[0])
+	assert.Same(t, &m.Audio, m.ExpansionAudio())
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/bits"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,15 +24,23 @@ type INESFileHeader struct {
 	Control  [10]byte
 }
 
-func (i INESFileHeader) Mapper() uint8 {
-	return i.Control[1]&0xF0 | i.Control[0]>>4
+func (i INESFileHeader) Mapper() uint16 {
+	mapper := uint16(i.Control[1]&0xF0 | i.Control[0]>>4)
+	if i.NESv2() {
+		mapper |= uint16(i.Control[2]&0xF) << 8
+	}
+	return mapper
 }
 
-func (i *INESFileHeader) SetMapper(v uint8) {
+func (i *INESFileHeader) SetMapper(v uint16) {
 	i.Control[0] &^= 0xF0
 	i.Control[1] &^= 0xF0
-	i.Control[0] |= v << 4
-	i.Control[1] |= v & 0xF0
+	i.Control[0] |= byte(v) << 4
+	i.Control[1] |= byte(v) & 0xF0
+	if i.NESv2() {
+		i.Control[2] &^= 0xF
+		i.Control[2] |= byte(v>>8) & 0xF
+	}
 }
 
 func (i INESFileHeader) Mirror() Mirror {
@@ -64,10 +73,24 @@ func (i *INESFileHeader) SetBattery(v bool) {
 	}
 }
 
+// Trainer reports whether 512 bytes of trainer data, loaded at $7000, come before PRG ROM.
+func (i INESFileHeader) Trainer() bool {
+	return i.Control[0]&0x4 != 0
+}
+
 func (i INESFileHeader) NESv2() bool {
 	return i.Control[1]&0xC == 0x8
 }
 
+// SetNESv2 switches between iNES and NES 2.0 headers. Fields that only exist in NES 2.0 are cleared.
+func (i *INESFileHeader) SetNESv2(v bool) {
+	i.Control[1] &^= 0xC
+	clear(i.Control[2:])
+	if v {
+		i.Control[1] |= 0x8
+	}
+}
+
 func (i INESFileHeader) Submapper() uint8 {
 	if i.NESv2() {
 		return i.Control[2] >> 4
@@ -75,7 +98,210 @@ func (i INESFileHeader) Submapper() uint8 {
 	return 0
 }
 
-var ErrInvalidROM = errors.New("invalid ROM file")
+// SetSubmapper sets the NES 2.0 submapper.
+func (i *INESFileHeader) SetSubmapper(v uint8) {
+	i.Control[2] &^= 0xF0
+	i.Control[2] |= v << 4
+}
+
+// PRGSize returns the PRG ROM size in bytes.
+func (i INESFileHeader) PRGSize() int {
+	if !i.NESv2() {
+		return int(i.PRGCount) * consts.PRGChunkSize
+	}
+	return romSize(i.PRGCount, i.Control[3]&0xF, consts.PRGChunkSize)
+}
+
+// SetPRGSize sets the PRG ROM size in bytes.
+// Sizes that are not a multiple of 16 KiB, or that are too large for iNES, require NES 2.0.
+func (i *INESFileHeader) SetPRGSize(size int) error {
+	if !i.NESv2() {
+		count, err := inesROMCount(size, consts.PRGChunkSize)
+		if err != nil {
+			return err
+		}
+		i.PRGCount = count
+		return nil
+	}
+	var msb byte
+	i.PRGCount, msb = encodeROMSize(size, consts.PRGChunkSize)
+	i.Control[3] = i.Control[3]&0xF0 | msb
+	return nil
+}
+
+// CHRSize returns the CHR ROM size in bytes.
+func (i INESFileHeader) CHRSize() int {
+	if !i.NESv2() {
+		return int(i.CHRCount) * consts.CHRChunkSize
+	}
+	return romSize(i.CHRCount, i.Control[3]>>4, consts.CHRChunkSize)
+}
+
+// SetCHRSize sets the CHR ROM size in bytes.
+// Sizes that are not a multiple of 8 KiB, or that are too large for iNES, require NES 2.0.
+func (i *INESFileHeader) SetCHRSize(size int) error {
+	if !i.NESv2() {
+		count, err := inesROMCount(size, consts.CHRChunkSize)
+		if err != nil {
+			return err
+		}
+		i.CHRCount = count
+		return nil
+	}
+	var msb byte
+	i.CHRCount, msb = encodeROMSize(size, consts.CHRChunkSize)
+	i.Control[3] = i.Control[3]&0x0F | msb<<4
+	return nil
+}
+
+// inesROMCount returns the number of chunks in an iNES ROM size.
+func inesROMCount(size, chunkSize int) (byte, error) {
+	if size%chunkSize != 0 || size/chunkSize > 0xFF {
+		return 0, fmt.Errorf("%w: %d", ErrINESROMSize, size)
+	}
+	return byte(size / chunkSize), nil
+}
+
+// romSize decodes a NES 2.0 ROM size.
+// When the MSB nibble is $F, the LSB is an exponent and multiplier instead of a count of chunks.
+// Sizes that are too large for an int are returned as -1.
+func romSize(lsb, msb byte, chunkSize int) int {
+	if msb == 0xF {
+		if int(lsb>>2) > bits.UintSize-4 {
+			return -1
+		}
+		return 1 << (lsb >> 2) * int(lsb&0x3*2+1)
+	}
+	return (int(msb)<<8 | int(lsb)) * chunkSize
+}
+
+// encodeROMSize encodes a NES 2.0 ROM size.
+func encodeROMSize(size, chunkSize int) (byte, byte) {
+	if count := size / chunkSize; size%chunkSize == 0 && count <= 0xEFF {
+		return byte(count), byte(count >> 8)
+	}
+
+	// Find the smallest exponent-multiplier size that fits
+	lsb, best := byte(0xFF), -1
+	for exponent := range 60 {
+		for multiplier := range 4 {
+			if v := 1 << exponent * (multiplier*2 + 1); v >= size && (best == -1 || v < best) {
+				lsb, best = byte(exponent<<2|multiplier), v
+			}
+		}
+	}
+	return lsb, 0xF
+}
+
+// PRGRAMSize returns the volatile PRG RAM size in bytes, or 0 for iNES headers.
+func (i INESFileHeader) PRGRAMSize() int {
+	return i.ramSize(i.Control[4] & 0xF)
+}
+
+// PRGNVRAMSize returns the battery-backed PRG RAM size in bytes, or 0 for iNES headers.
+func (i INESFileHeader) PRGNVRAMSize() int {
+	return i.ramSize(i.Control[4] >> 4)
+}
+
+// CHRRAMSize returns the volatile CHR RAM size in bytes, or 0 for iNES headers.
+func (i INESFileHeader) CHRRAMSize() int {
+	return i.ramSize(i.Control[5] & 0xF)
+}
+
+// CHRNVRAMSize returns the battery-backed CHR RAM size in bytes, or 0 for iNES headers.
+func (i INESFileHeader) CHRNVRAMSize() int {
+	return i.ramSize(i.Control[5] >> 4)
+}
+
+// SetRAMSizes sets the NES 2.0 RAM sizes in bytes. Sizes are rounded up to a power of two, with a minimum of 128 bytes.
+func (i *INESFileHeader) SetRAMSizes(prgRAM, prgNVRAM, chrRAM, chrNVRAM int) {
+	i.Control[4] = encodeRAMSize(prgNVRAM)<<4 | encodeRAMSize(prgRAM)
+	i.Control[5] = encodeRAMSize(chrNVRAM)<<4 | encodeRAMSize(chrRAM)
+}
+
+// ramSize decodes a NES 2.0 RAM shift count.
+func (i INESFileHeader) ramSize(shift byte) int {
+	if !i.NESv2() || shift == 0 {
+		return 0
+	}
+	return 64 << shift
+}
+
+// encodeRAMSize encodes a NES 2.0 RAM shift count.
+func encodeRAMSize(size int) byte {
+	if size <= 0 {
+		return 0
+	}
+	shift := byte(1)
+	for 64<<shift < size && shift < 0xF {
+		shift++
+	}
+	return shift
+}
+
+// ConsoleType returns the console that the game runs on. Extended console types are resolved.
+func (i INESFileHeader) ConsoleType() ConsoleType {
+	t := ConsoleType(i.Control[1] & 0x3)
+	if t == ConsoleExtended && i.NESv2() {
+		t = ConsoleType(i.Control[7] & 0xF)
+	}
+	return t
+}
+
+// SetConsoleType sets the console type. Extended console types require NES 2.0.
+func (i *INESFileHeader) SetConsoleType(v ConsoleType) {
+	i.Control[1] &^= 0x3
+	if v > ConsoleExtended {
+		i.Control[1] |= byte(ConsoleExtended)
+		i.Control[7] = i.Control[7]&0xF0 | byte(v)&0xF
+	} else {
+		i.Control[1] |= byte(v)
+	}
+}
+
+// Timing returns the CPU/PPU timing. iNES headers are always NTSC.
+func (i INESFileHeader) Timing() Timing {
+	if !i.NESv2() {
+		return TimingNTSC
+	}
+	return Timing(i.Control[6] & 0x3)
+}
+
+// SetTiming sets the NES 2.0 CPU/PPU timing.
+func (i *INESFileHeader) SetTiming(v Timing) {
+	i.Control[6] = i.Control[6]&^0x3 | byte(v)&0x3
+}
+
+// MiscROMs returns the number of miscellaneous ROMs after CHR ROM.
+func (i INESFileHeader) MiscROMs() int {
+	if !i.NESv2() {
+		return 0
+	}
+	return int(i.Control[8] & 0x3)
+}
+
+// ExpansionDevice returns the NES 2.0 default expansion device.
+func (i INESFileHeader) ExpansionDevice() ExpansionDevice {
+	if !i.NESv2() {
+		return ExpansionUnspecified
+	}
+	return ExpansionDevice(i.Control[9] & 0x3F)
+}
+
+// SetExpansionDevice sets the NES 2.0 default expansion device.
+func (i *INESFileHeader) SetExpansionDevice(v ExpansionDevice) {
+	i.Control[9] = i.Control[9]&^0x3F | byte(v)&0x3F
+}
+
+const (
+	trainerSize = 0x200
+	trainerAddr = 0x7000
+)
+
+var (
+	ErrInvalidROM  = errors.New("invalid ROM file")
+	ErrINESROMSize = errors.New("ROM size requires an NES 2.0 header")
+)
 
 func FromINESFile(path string) (*Cartridge, error) {
 	f, err := os.Open(path)
@@ -114,35 +340,82 @@ func FromINES(r io.Reader) (*Cartridge, error) {
 	cartridge.Header = header
 	cartridge.Mirror = header.Mirror()
 	cartridge.Battery = header.Battery()
+	cartridge.Timing = header.Timing()
+	cartridge.ConsoleType = header.ConsoleType()
+	cartridge.ExpansionDevice = header.ExpansionDevice()
 
 	slog.Debug("Loaded iNES header",
+		"nes2", header.NESv2(),
 		"battery", cartridge.Battery,
 		"mapper", header.Mapper(),
+		"submapper", header.Submapper(),
 		"mirror", cartridge.Mirror,
-		"prg", header.PRGCount,
-		"chr", header.CHRCount,
+		"prg", header.PRGSize(),
+		"chr", header.CHRSize(),
+		"prgRAM", header.PRGRAMSize(),
+		"prgNVRAM", header.PRGNVRAMSize(),
+		"chrRAM", header.CHRRAMSize(),
+		"chrNVRAM", header.CHRNVRAMSize(),
+		"timing", cartridge.Timing,
+		"console", cartridge.ConsoleType,
+		"expansion", cartridge.ExpansionDevice,
 	)
 
-	cartridge.PRG = make([]byte, int(header.PRGCount)*consts.PRGChunkSize)
-	if _, err := io.ReadFull(tr, cartridge.PRG); err != nil {
+	// Read the rest of the file so that header sizes are checked before anything is allocated
+	data, err := io.ReadAll(tr)
+	if err != nil {
 		return nil, err
 	}
+	next := func(size int) ([]byte, error) {
+		if size < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidROM, "invalid ROM size")
+		}
+		if size > len(data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		b := data[:size:size]
+		data = data[size:]
+		return b, nil
+	}
 
-	if header.CHRCount == 0 {
-		cartridge.CHR = make([]byte, consts.CHRChunkSize)
-	} else {
-		cartridge.CHR = make([]byte, int(header.CHRCount)*consts.CHRChunkSize)
-		if _, err := io.ReadFull(tr, cartridge.CHR); err != nil {
+	// NES 2.0 RAM sizes replace the 8 KiB default, which is still used as a minimum since mappers expect it
+	if ramSize := header.PRGRAMSize() + header.PRGNVRAMSize(); ramSize > len(cartridge.SRAM) {
+		cartridge.SRAM = make([]byte, ramSize)
+	}
+
+	if header.Trainer() {
+		trainer, err := next(trainerSize)
+		if err != nil {
 			return nil, err
 		}
+		copy(cartridge.SRAM[trainerAddr-0x6000:], trainer)
 	}
 
-	// Ensure all bytes are written to hasher
-	if _, err := io.Copy(hasher, r); err != nil {
+	if cartridge.PRG, err = next(header.PRGSize()); err != nil {
 		return nil, err
 	}
 
+	if chrSize := header.CHRSize(); chrSize == 0 {
+		ramSize := header.CHRRAMSize() + header.CHRNVRAMSize()
+		if ramSize == 0 {
+			ramSize = consts.CHRChunkSize
+		}
+		cartridge.CHR = make([]byte, ramSize)
+	} else {
+		if cartridge.CHR, err = next(chrSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if header.MiscROMs() != 0 {
+		cartridge.MiscROM = data
+	}
+
 	cartridge.hash = hex.EncodeToString(hasher.Sum(nil))
 	cartridge.name, _ = database.FindNameByHash(cartridge.hash)
+	if !header.NESv2() && database.IsPAL(cartridge.name) {
+		// iNES headers do not have a region, so it is taken from the database
+		cartridge.Timing = TimingPAL
+	}
 	return cartridge, nil
 }
@@ -1,9 +1,12 @@
 package cartridge
 
 import (
+	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_INESFileHeader_Battery(t *testing.T) {
@@ -68,12 +71,14 @@ func Test_INESFileHeader_Mapper(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   byte
+		want   uint16
 	}{
 		{"0", fields{}, 0},
 		{"1", fields{[10]byte{0x10}}, 1},
 		{"2", fields{[10]byte{0x20}}, 2},
 		{"40", fields{[10]byte{0x80, 0x20}}, 40},
+		{"iNES ignores byte 8", fields{[10]byte{0x80, 0x20, 0x1}}, 40},
+		{"NES 2.0 256", fields{[10]byte{0, 0x8, 0x1}}, 256},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -158,7 +163,7 @@ func TestINESFileHeader_SetMapper(t *testing.T) {
 		Control [10]byte
 	}
 	type args struct {
-		v uint8
+		v uint16
 	}
 	tests := []struct {
 		name   string
@@ -181,3 +186,145 @@ func TestINESFileHeader_SetMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestINESFileHeader_PRGSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		control [10]byte
+		count   byte
+		want    int
+	}{
+		{"iNES", [10]byte{}, 2, 0x8000},
+		{"iNES ignores MSB", [10]byte{3: 0x1}, 2, 0x8000},
+		{"NES 2.0", [10]byte{1: 0x8}, 2, 0x8000},
+		{"NES 2.0 MSB", [10]byte{1: 0x8, 3: 0x1}, 0, 0x100 * 0x4000},
+		{"NES 2.0 exponent", [10]byte{1: 0x8, 3: 0xF}, 10<<2 | 1, 3 << 10},
+		{"NES 2.0 exponent overflow", [10]byte{1: 0x8, 3: 0xF}, 63<<2 | 3, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			i := INESFileHeader{PRGCount: tt.count, Control: tt.control}
+			assert.Equal(t, tt.want, i.PRGSize())
+		})
+	}
+}
+
+func TestINESFileHeader_SetPRGSize(t *testing.T) {
+	t.Parallel()
+	for _, size := range []int{0, 0x4000, 0x80000, 0x100 * 0x4000, 3 << 10, 0x2000} {
+		i := INESFileHeader{Control: [10]byte{1: 0x8, 3: 0x50}}
+		require.NoError(t, i.SetPRGSize(size))
+		assert.Equal(t, size, i.PRGSize())
+		assert.EqualValues(t, 0x5, i.Control[3]>>4, "CHR MSB should be unchanged")
+
+		require.NoError(t, i.SetCHRSize(size))
+		assert.Equal(t, size, i.CHRSize())
+	}
+
+	t.Run("iNES", func(t *testing.T) {
+		t.Parallel()
+		var i INESFileHeader
+		require.NoError(t, i.SetPRGSize(0x8000))
+		assert.Equal(t, 0x8000, i.PRGSize())
+		require.NoError(t, i.SetCHRSize(0x2000))
+		assert.Equal(t, 0x2000, i.CHRSize())
+
+		require.ErrorIs(t, i.SetPRGSize(0x2000), ErrINESROMSize)
+		require.ErrorIs(t, i.SetPRGSize(0x100*0x4000), ErrINESROMSize)
+		require.ErrorIs(t, i.SetCHRSize(0x1000), ErrINESROMSize)
+		assert.Equal(t, 0x8000, i.PRGSize())
+	})
+}
+
+func TestINESFileHeader_RAMSizes(t *testing.T) {
+	t.Parallel()
+
+	i := INESFileHeader{Control: [10]byte{1: 0x8}}
+	i.SetRAMSizes(0x2000, 0x8000, 0, 0x2000)
+	assert.EqualValues(t, 0x97, i.Control[4])
+	assert.EqualValues(t, 0x70, i.Control[5])
+	assert.Equal(t, 0x2000, i.PRGRAMSize())
+	assert.Equal(t, 0x8000, i.PRGNVRAMSize())
+	assert.Equal(t, 0, i.CHRRAMSize())
+	assert.Equal(t, 0x2000, i.CHRNVRAMSize())
+
+	i.SetNESv2(false)
+	assert.Equal(t, 0, i.PRGRAMSize(), "iNES headers do not have RAM sizes")
+}
+
+func TestINESFileHeader_NESv2Fields(t *testing.T) {
+	t.Parallel()
+
+	i := INESFileHeader{Control: [10]byte{1: 0x8}}
+	i.SetSubmapper(3)
+	i.SetTiming(TimingDendy)
+	i.SetConsoleType(ConsoleVT02)
+	i.SetExpansionDevice(ExpansionZapper)
+	i.SetMapper(0x1AB)
+	assert.Equal(t, [10]byte{0xB0, 0xAB, 0x31, 0, 0, 0, 0x3, 0x7, 0, 0x8}, i.Control)
+	assert.EqualValues(t, 3, i.Submapper())
+	assert.Equal(t, TimingDendy, i.Timing())
+	assert.Equal(t, ConsoleVT02, i.ConsoleType())
+	assert.Equal(t, ExpansionZapper, i.ExpansionDevice())
+	assert.EqualValues(t, 0x1AB, i.Mapper())
+
+	i.SetNESv2(false)
+	assert.Equal(t, TimingNTSC, i.Timing())
+	assert.Equal(t, ConsoleExtended, i.ConsoleType())
+	assert.Equal(t, ExpansionUnspecified, i.ExpansionDevice())
+	assert.EqualValues(t, 0xAB, i.Mapper())
+}
+
+func TestFromINES(t *testing.T) {
+	t.Parallel()
+
+	t.Run("iNES", func(t *testing.T) {
+		t.Parallel()
+		b := []byte{'N', 'E', 'S', 0x1A, 1, 0, 0x4, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+		b = append(b, bytes.Repeat([]byte{0x12}, trainerSize)...)
+		b = append(b, bytes.Repeat([]byte{0x34}, 0x4000)...)
+
+		cart, err := FromINES(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Len(t, cart.PRG, 0x4000)
+		assert.Len(t, cart.CHR, 0x2000)
+		assert.True(t, cart.CHRIsRAM())
+		assert.Len(t, cart.SRAM, 0x2000)
+		assert.EqualValues(t, 0x12, cart.SRAM[trainerAddr-0x6000])
+		assert.Equal(t, TimingNTSC, cart.Timing)
+	})
+
+	t.Run("NES 2.0", func(t *testing.T) {
+		t.Parallel()
+		b := []byte{'N', 'E', 'S', 0x1A, 1, 0, 0, 0x8, 0, 0, 0x09, 0x09, 0x1, 0, 0x1, 0x0F}
+		b = append(b, bytes.Repeat([]byte{0x34}, 0x4000)...)
+		b = append(b, 0x56)
+
+		cart, err := FromINES(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Len(t, cart.SRAM, 0x8000)
+		assert.Len(t, cart.CHR, 0x8000)
+		assert.Equal(t, TimingPAL, cart.Timing)
+		assert.Equal(t, ExpansionArkanoid, cart.ExpansionDevice)
+		assert.Equal(t, []byte{0x56}, cart.MiscROM)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+		b := []byte{'N', 'E', 'S', 0x1A, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+		b = append(b, make([]byte, 0x4000)...)
+
+		_, err := FromINES(bytes.NewReader(b))
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		t.Parallel()
+		b := []byte{'N', 'E', 'S', 0x1A, 0xFC, 0, 0, 0x8, 0, 0x0F, 0, 0, 0, 0, 0, 0}
+
+		_, err := FromINES(bytes.NewReader(b))
+		require.ErrorIs(t, err, ErrInvalidROM)
+	})
+}
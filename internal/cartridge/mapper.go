@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"gabe565.com/gones/internal/apu"
 	"gabe565.com/gones/internal/memory"
 	"gabe565.com/gones/internal/ppu/registers"
 )
@@ -30,9 +31,56 @@ type MapperIRQ interface {
 	IRQ() bool
 }
 
+// MapperAudio is implemented by mappers with expansion audio.
+type MapperAudio interface {
+	ExpansionAudio() apu.Expansion
+}
+
+// MapperNametable is implemented by mappers that map nametables themselves instead of using [Cartridge.Mirror].
+// ciram is the console's 2 KiB of nametable RAM.
+type MapperNametable interface {
+	ReadNametable(ciram *[0x800]byte, addr uint16) byte
+	WriteNametable(ciram *[0x800]byte, addr uint16, data byte)
+}
+
+// FetchKind is the kind of memory fetch that the PPU makes while rendering.
+type FetchKind uint8
+
+const (
+	FetchNametable FetchKind = iota
+	FetchAttribute
+	FetchBackground
+	FetchSprite
+)
+
+// MapperRenderFetch is implemented by mappers that watch the PPU's rendering fetches, like MMC5.
+// It is called instead of the PPU's normal read for every nametable, attribute, and pattern fetch.
+type MapperRenderFetch interface {
+	RenderFetch(ciram *[0x800]byte, kind FetchKind, addr uint16) byte
+}
+
+// MapperOnPPUWrite is implemented by mappers that watch CPU writes to the PPU registers.
+type MapperOnPPUWrite interface {
+	OnPPUWrite(addr uint16, data byte)
+}
+
+// MapperPRGOffset is implemented by mappers that can report the PRG ROM offset mapped to a CPU address.
+type MapperPRGOffset interface {
+	PRGOffset(addr uint16) (int, bool)
+}
+
+// MapperCHROffset is implemented by mappers that can report the CHR offset mapped to a PPU address.
+type MapperCHROffset interface {
+	CHROffset(addr uint16) (int, bool)
+}
+
 var ErrUnsupportedMapper = errors.New("unsupported mapper")
 
 func NewMapper(cartridge *Cartridge) (Mapper, error) { //nolint:ireturn,nolintlint
+	if cartridge.Disk != nil {
+		return NewMapperFDS(cartridge), nil
+	}
+
 	switch cartridge.Header.Mapper() {
 	case 0:
 		return NewMapper2(cartridge, false), nil
@@ -44,12 +92,22 @@ func NewMapper(cartridge *Cartridge) (Mapper, error) { //nolint:ireturn,nolintli
 		return NewMapper3(cartridge), nil
 	case 4:
 		return NewMapper4(cartridge), nil
+	case 5:
+		return NewMapper5(cartridge), nil
 	case 7:
 		return NewMapper7(cartridge), nil
+	case 19:
+		return NewMapper19(cartridge), nil
+	case 24:
+		return NewMapper24(cartridge, false), nil
+	case 26:
+		return NewMapper24(cartridge, true), nil
 	case 69:
 		return NewMapper69(cartridge), nil
 	case 71:
 		return NewMapper71(cartridge), nil
+	case 85:
+		return NewMapper85(cartridge), nil
 	default:
 		return nil, fmt.Errorf("%w: %d", ErrUnsupportedMapper, cartridge.Header.Mapper())
 	}
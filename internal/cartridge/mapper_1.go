@@ -49,6 +49,25 @@ func (m *Mapper1) ReadMem(addr uint16) byte {
 	}
 }
 
+func (m *Mapper1) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	addr -= 0x8000
+	bank := addr / consts.PRGChunkSize
+	offset := int(addr % consts.PRGChunkSize)
+	return m.PRGOffsets[bank] + offset, true
+}
+
+func (m *Mapper1) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	bank := addr / 0x1000
+	offset := int(addr % 0x1000)
+	return m.CHROffsets[bank] + offset, true
+}
+
 func (m *Mapper1) WriteMem(addr uint16, data byte) {
 	switch {
 	case addr < 0x2000:
@@ -0,0 +1,173 @@
+package cartridge
+
+import "gabe565.com/gones/internal/apu"
+
+// n163RAMOffset is the offset of the N163's internal RAM within SRAM.
+// It is stored after PRG RAM so that it is battery-backed along with it.
+const n163RAMOffset = 0x2000
+
+func NewMapper19(cartridge *Cartridge) *Mapper19 {
+	return &Mapper19{
+		cartridge: cartridge,
+		Audio:     apu.NewN163(n163RAM(cartridge)),
+	}
+}
+
+// n163RAM returns the N163's internal RAM within SRAM, growing SRAM if it is too small.
+func n163RAM(cartridge *Cartridge) []byte {
+	if len(cartridge.SRAM) < n163RAMOffset+apu.N163RAMSize {
+		sram := make([]byte, n163RAMOffset+apu.N163RAMSize)
+		copy(sram, cartridge.SRAM)
+		cartridge.SRAM = sram
+	}
+	return cartridge.SRAM[n163RAMOffset : n163RAMOffset+apu.N163RAMSize]
+}
+
+// Mapper19 is the Namco 163.
+// Nametables can be mapped to CIRAM or to CHR ROM. CIRAM is not available as pattern table memory.
+//
+// See [Namco 163].
+//
+// [Namco 163]: https://www.nesdev.org/wiki/INES_Mapper_019
+type Mapper19 struct {
+	cartridge *Cartridge
+
+	PRGBanks [3]byte
+	// CHRBanks are the 1 KiB pattern table banks, followed by the 4 nametable banks.
+	// Nametable banks $E0 and above select a CIRAM page.
+	CHRBanks [12]byte
+
+	IRQCounter uint16
+	IRQEnabled bool
+	IRQPending bool
+
+	Audio apu.N163
+}
+
+func (m *Mapper19) Cartridge() *Cartridge { return m.cartridge }
+
+// SetCartridge sets the cartridge and points the N163 at its RAM.
+// The N163's RAM is not saved in save states, so this is also called after loading one.
+func (m *Mapper19) SetCartridge(c *Cartridge) {
+	m.cartridge = c
+	m.Audio.RAM = n163RAM(c)
+}
+
+func (m *Mapper19) ExpansionAudio() apu.Expansion { return &m.Audio }
+
+func (m *Mapper19) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		offset, _ := m.CHROffset(addr)
+		return m.cartridge.CHR[offset]
+	case 0x4800 <= addr && addr < 0x5000:
+		return m.Audio.ReadData()
+	case 0x5000 <= addr && addr < 0x5800:
+		return byte(m.IRQCounter)
+	case 0x5800 <= addr && addr < 0x6000:
+		data := byte(m.IRQCounter >> 8)
+		if m.IRQEnabled {
+			data |= 0x80
+		}
+		return data
+	case 0x6000 <= addr && addr < 0x8000:
+		return m.cartridge.SRAM[addr-0x6000]
+	case 0x8000 <= addr:
+		offset, _ := m.PRGOffset(addr)
+		return m.cartridge.PRG[offset]
+	default:
+		return 0
+	}
+}
+
+func (m *Mapper19) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	bank := len(m.cartridge.PRG)/0x2000 - 1
+	if slot := (addr - 0x8000) / 0x2000; slot < 3 {
+		bank = int(m.PRGBanks[slot])
+	}
+	return (bank*0x2000 + int(addr%0x2000)) % len(m.cartridge.PRG), true
+}
+
+func (m *Mapper19) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	bank := int(m.CHRBanks[addr/0x400])
+	return (bank*0x400 + int(addr%0x400)) % len(m.cartridge.CHR), true
+}
+
+func (m *Mapper19) WriteMem(addr uint16, data byte) {
+	switch {
+	case addr < 0x2000:
+		if m.cartridge.CHRIsRAM() {
+			offset, _ := m.CHROffset(addr)
+			m.cartridge.CHR[offset] = data
+		}
+	case 0x4800 <= addr && addr < 0x5000:
+		m.Audio.WriteData(data)
+	case 0x5000 <= addr && addr < 0x5800:
+		m.IRQCounter = m.IRQCounter&0x7F00 | uint16(data)
+		m.IRQPending = false
+	case 0x5800 <= addr && addr < 0x6000:
+		m.IRQCounter = uint16(data&0x7F)<<8 | m.IRQCounter&0xFF
+		m.IRQEnabled = data&0x80 != 0
+		m.IRQPending = false
+	case 0x6000 <= addr && addr < 0x8000:
+		if m.ramWritable(addr) {
+			m.cartridge.SRAM[addr-0x6000] = data
+		}
+	case 0x8000 <= addr && addr < 0xE000:
+		m.CHRBanks[(addr-0x8000)/0x800] = data
+	case 0xE000 <= addr && addr < 0xE800:
+		m.PRGBanks[0] = data & 0x3F
+		m.Audio.SetDisabled(data&0x40 != 0)
+	case 0xE800 <= addr && addr < 0xF000:
+		m.PRGBanks[1] = data & 0x3F
+	case 0xF000 <= addr && addr < 0xF800:
+		m.PRGBanks[2] = data & 0x3F
+	case 0xF800 <= addr:
+		m.Audio.WriteAddr(data)
+	}
+}
+
+// ramWritable reports whether PRG RAM can be written at addr.
+// $F800 enables writes when its upper nibble is $4, and each of its lower bits protects a 2 KiB region.
+func (m *Mapper19) ramWritable(addr uint16) bool {
+	protect := m.Audio.Addr
+	return protect&0xF0 == 0x40 && protect>>((addr-0x6000)/0x800)&1 == 0
+}
+
+func (m *Mapper19) ReadNametable(ciram *[0x800]byte, addr uint16) byte {
+	bank := m.CHRBanks[8+addr/0x400%4]
+	if bank >= 0xE0 {
+		return ciram[int(bank&1)*0x400+int(addr%0x400)]
+	}
+	return m.cartridge.CHR[(int(bank)*0x400+int(addr%0x400))%len(m.cartridge.CHR)]
+}
+
+func (m *Mapper19) WriteNametable(ciram *[0x800]byte, addr uint16, data byte) {
+	bank := m.CHRBanks[8+addr/0x400%4]
+	switch {
+	case bank >= 0xE0:
+		ciram[int(bank&1)*0x400+int(addr%0x400)] = data
+	case m.cartridge.CHRIsRAM():
+		m.cartridge.CHR[(int(bank)*0x400+int(addr%0x400))%len(m.cartridge.CHR)] = data
+	}
+}
+
+func (m *Mapper19) OnCPUStep(cycles uint) {
+	for range cycles {
+		if !m.IRQEnabled || m.IRQCounter == 0x7FFF {
+			return
+		}
+		m.IRQCounter++
+		if m.IRQCounter == 0x7FFF {
+			m.IRQPending = true
+		}
+	}
+}
+
+func (m *Mapper19) IRQ() bool { return m.IRQPending }
@@ -0,0 +1,86 @@
+package cartridge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper19(t *testing.T) {
+	t.Parallel()
+
+	cart := testCartridge(8, 16)
+	m := NewMapper19(cart)
+
+	t.Run("prg", func(t *testing.T) {
+		m.WriteMem(0xE000, 2)
+		m.WriteMem(0xE800, 3)
+		m.WriteMem(0xF000, 4)
+		assert.Equal(t, byte(2), m.ReadMem(0x8000))
+		assert.Equal(t, byte(3), m.ReadMem(0xA000))
+		assert.Equal(t, byte(4), m.ReadMem(0xC000))
+		assert.Equal(t, byte(7), m.ReadMem(0xE000))
+	})
+
+	t.Run("chr", func(t *testing.T) {
+		m.WriteMem(0x8000, 5)
+		m.WriteMem(0xB800, 9)
+		assert.Equal(t, byte(5), m.ReadMem(0x0000))
+		assert.Equal(t, byte(9), m.ReadMem(0x1C00))
+	})
+
+	t.Run("nametables", func(t *testing.T) {
+		var ciram [0x800]byte
+		m.WriteMem(0xC000, 0xE1)
+		m.WriteMem(0xC800, 6)
+		m.WriteNametable(&ciram, 0x2001, 0x12)
+		assert.Equal(t, byte(0x12), ciram[0x401])
+		assert.Equal(t, byte(0x12), m.ReadNametable(&ciram, 0x2001))
+		assert.Equal(t, byte(6), m.ReadNametable(&ciram, 0x2400))
+	})
+
+	t.Run("prg ram protect", func(t *testing.T) {
+		m.WriteMem(0xF800, 0)
+		m.WriteMem(0x6000, 1)
+		assert.Zero(t, m.ReadMem(0x6000))
+		m.WriteMem(0xF800, 0x41)
+		m.WriteMem(0x6000, 1)
+		m.WriteMem(0x6800, 2)
+		assert.Zero(t, m.ReadMem(0x6000))
+		assert.Equal(t, byte(2), m.ReadMem(0x6800))
+	})
+
+	t.Run("internal ram", func(t *testing.T) {
+		m.WriteMem(0xF800, 0x80|0x7F)
+		m.WriteMem(0x4800, 0x11)
+		m.WriteMem(0x4800, 0x22)
+		assert.Equal(t, byte(0x11), cart.SRAM[n163RAMOffset+0x7F])
+		assert.Equal(t, byte(0x22), cart.SRAM[n163RAMOffset])
+		m.WriteMem(0xF800, 0x7F)
+		assert.Equal(t, byte(0x11), m.ReadMem(0x4800))
+		assert.Equal(t, byte(0x11), m.ReadMem(0x4800))
+	})
+
+	t.Run("set cartridge", func(t *testing.T) {
+		sram := bytes.Clone(cart.SRAM)
+		sram[n163RAMOffset] = 0x33
+		cart.SRAM = sram
+		m.SetCartridge(cart)
+		m.WriteMem(0xF800, 0)
+		assert.Equal(t, byte(0x33), m.ReadMem(0x4800))
+	})
+
+	t.Run("irq", func(t *testing.T) {
+		m.WriteMem(0x5000, 0xFD)
+		m.WriteMem(0x5800, 0xFF)
+		m.OnCPUStep(1)
+		assert.False(t, m.IRQ())
+		m.OnCPUStep(5)
+		assert.True(t, m.IRQ())
+		assert.Equal(t, uint16(0x7FFF), m.IRQCounter)
+		assert.Equal(t, byte(0xFF), m.ReadMem(0x5800))
+		m.WriteMem(0x5000, 0)
+		assert.False(t, m.IRQ())
+	})
+}
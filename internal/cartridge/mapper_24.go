@@ -0,0 +1,151 @@
+package cartridge
+
+import "gabe565.com/gones/internal/apu"
+
+// vrcMirrors are the VRC6 and VRC7 mirroring modes.
+//
+//nolint:gochecknoglobals
+var vrcMirrors = [...]Mirror{Vertical, Horizontal, SingleLower, SingleUpper}
+
+// NewMapper24 returns a VRC6 mapper. VRC6b boards (mapper 26) swap the A0 and A1 address lines.
+func NewMapper24(cartridge *Cartridge, swapped bool) *Mapper24 {
+	return &Mapper24{
+		cartridge: cartridge,
+		swapped:   swapped,
+	}
+}
+
+// Mapper24 is the Konami VRC6.
+//
+// See [VRC6].
+//
+// [VRC6]: https://www.nesdev.org/wiki/VRC6
+type Mapper24 struct {
+	cartridge *Cartridge
+	swapped   bool
+
+	PRGBank16 byte
+	PRGBank8  byte
+	CHRBanks  [8]byte
+	// Control is the $B003 PPU banking style register.
+	Control byte
+
+	IRQCounter VRCIRQ
+	Audio      apu.VRC6
+}
+
+func (m *Mapper24) Cartridge() *Cartridge { return m.cartridge }
+
+func (m *Mapper24) SetCartridge(c *Cartridge) { m.cartridge = c }
+
+func (m *Mapper24) ExpansionAudio() apu.Expansion { return &m.Audio }
+
+func (m *Mapper24) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		offset, _ := m.CHROffset(addr)
+		return m.cartridge.CHR[offset]
+	case 0x6000 <= addr && addr < 0x8000:
+		if m.ramEnabled() {
+			return m.cartridge.SRAM[int(addr-0x6000)%len(m.cartridge.SRAM)]
+		}
+		return 0
+	case 0x8000 <= addr:
+		offset, _ := m.PRGOffset(addr)
+		return m.cartridge.PRG[offset]
+	default:
+		return 0
+	}
+}
+
+func (m *Mapper24) PRGOffset(addr uint16) (int, bool) {
+	var bank int
+	switch {
+	case addr < 0x8000:
+		return 0, false
+	case addr < 0xC000:
+		bank = int(m.PRGBank16)*2 + int(addr-0x8000)/0x2000
+	case addr < 0xE000:
+		bank = int(m.PRGBank8)
+	default:
+		bank = len(m.cartridge.PRG)/0x2000 - 1
+	}
+	return (bank*0x2000 + int(addr%0x2000)) % len(m.cartridge.PRG), true
+}
+
+func (m *Mapper24) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+
+	// In modes 1-3, some registers select 2 KiB banks, with the low bit replaced by PPU A10
+	slot := addr / 0x400
+	var bank int
+	switch m.Control & 3 {
+	case 0:
+		bank = int(m.CHRBanks[slot])
+	case 1:
+		bank = int(m.CHRBanks[slot/2])&^1 | int(slot&1)
+	default:
+		if slot < 4 {
+			bank = int(m.CHRBanks[slot])
+		} else {
+			bank = int(m.CHRBanks[4+(slot-4)/2])&^1 | int(slot&1)
+		}
+	}
+	return (bank*0x400 + int(addr%0x400)) % len(m.cartridge.CHR), true
+}
+
+func (m *Mapper24) WriteMem(addr uint16, data byte) {
+	switch {
+	case addr < 0x2000:
+		if m.cartridge.CHRIsRAM() {
+			offset, _ := m.CHROffset(addr)
+			m.cartridge.CHR[offset] = data
+		}
+		return
+	case 0x6000 <= addr && addr < 0x8000:
+		if m.ramEnabled() {
+			m.cartridge.SRAM[int(addr-0x6000)%len(m.cartridge.SRAM)] = data
+		}
+		return
+	case addr < 0x8000:
+		return
+	}
+
+	if m.swapped {
+		addr = addr&0xFFFC | addr&1<<1 | addr>>1&1
+	}
+
+	switch addr & 0xF003 {
+	case 0x8000, 0x8001, 0x8002, 0x8003:
+		m.PRGBank16 = data & 0xF
+	case 0x9000, 0x9001, 0x9002, 0x9003, 0xA000, 0xA001, 0xA002, 0xB000, 0xB001, 0xB002:
+		m.Audio.Write(addr&0xF003, data)
+	case 0xB003:
+		m.Control = data
+		m.cartridge.Mirror = vrcMirrors[data>>2&3]
+	case 0xC000, 0xC001, 0xC002, 0xC003:
+		m.PRGBank8 = data & 0x1F
+	case 0xD000, 0xD001, 0xD002, 0xD003:
+		m.CHRBanks[addr&3] = data
+	case 0xE000, 0xE001, 0xE002, 0xE003:
+		m.CHRBanks[4+addr&3] = data
+	case 0xF000:
+		m.IRQCounter.WriteLatch(data)
+	case 0xF001:
+		m.IRQCounter.WriteControl(data)
+	case 0xF002:
+		m.IRQCounter.Ack()
+	}
+}
+
+func (m *Mapper24) ramEnabled() bool {
+	return m.Control&0x80 != 0 && len(m.cartridge.SRAM) != 0
+}
+
+func (m *Mapper24) OnCPUStep(cycles uint) {
+	m.IRQCounter.step(cycles)
+}
+
+func (m *Mapper24) IRQ() bool { return m.IRQCounter.Pending }
@@ -0,0 +1,69 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper24_Swapped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		swapped bool
+		addr    uint16
+	}{
+		{"vrc6a", false, 0xD001},
+		{"vrc6b", true, 0xD002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cart := testCartridge(4, 16)
+			m := NewMapper24(cart, tt.swapped)
+
+			m.WriteMem(tt.addr, 9)
+			assert.Equal(t, byte(9), m.ReadMem(0x0400))
+
+			m.WriteMem(0xB003, 0x84)
+			assert.Equal(t, Horizontal, cart.Mirror)
+			m.WriteMem(0x6000, 0xAB)
+			assert.Equal(t, byte(0xAB), m.ReadMem(0x6000))
+		})
+	}
+}
+
+func TestVRCIRQ(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+		var irq VRCIRQ
+		irq.WriteLatch(0xFD)
+		irq.WriteControl(0x07)
+		irq.step(2)
+		assert.False(t, irq.Pending)
+		irq.step(1)
+		assert.True(t, irq.Pending)
+		assert.Equal(t, byte(0xFD), irq.Counter)
+
+		irq.Ack()
+		assert.False(t, irq.Pending)
+		assert.True(t, irq.Enabled)
+	})
+
+	t.Run("scanline", func(t *testing.T) {
+		t.Parallel()
+		var irq VRCIRQ
+		irq.WriteLatch(0xFF)
+		irq.WriteControl(0x02)
+		irq.step(113)
+		assert.False(t, irq.Pending)
+		irq.step(1)
+		assert.True(t, irq.Pending)
+
+		irq.Ack()
+		assert.False(t, irq.Enabled)
+	})
+}
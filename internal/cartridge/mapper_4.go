@@ -79,6 +79,25 @@ func (m *Mapper4) ReadMem(addr uint16) byte {
 	}
 }
 
+func (m *Mapper4) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	addr -= 0x8000
+	bank := addr / 0x2000
+	offset := int(addr % 0x2000)
+	return m.PRGOffsets[bank] + offset, true
+}
+
+func (m *Mapper4) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	bank := addr / 0x400
+	offset := int(addr % 0x400)
+	return m.CHROffsets[bank] + offset, true
+}
+
 func (m *Mapper4) WriteMem(addr uint16, data byte) {
 	switch {
 	case addr < 0x2000:
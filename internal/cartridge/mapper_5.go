@@ -0,0 +1,401 @@
+package cartridge
+
+import "gabe565.com/gones/internal/apu"
+
+// mmc5IdleCycles is the number of CPU cycles without a new scanline before the MMC5 decides
+// that the PPU has stopped rendering. It is a little longer than one scanline.
+const mmc5IdleCycles = 120
+
+func NewMapper5(cartridge *Cartridge) *Mapper5 {
+	if !cartridge.Header.NESv2() && len(cartridge.SRAM) < 0x10000 {
+		// iNES headers can't describe MMC5 RAM sizes, so the largest is used
+		sram := make([]byte, 0x10000)
+		copy(sram, cartridge.SRAM)
+		cartridge.SRAM = sram
+	}
+
+	return &Mapper5{
+		cartridge: cartridge,
+		PRGMode:   3,
+		PRGBanks:  [4]byte{0xFF, 0xFF, 0xFF, 0xFF},
+	}
+}
+
+// Mapper5 is the MMC5 (ExROM).
+//
+// See [MMC5].
+//
+// [MMC5]: https://www.nesdev.org/wiki/MMC5
+type Mapper5 struct {
+	cartridge *Cartridge
+
+	PRGMode    byte
+	PRGRAMBank byte
+	PRGBanks   [4]byte
+	RAMProtect [2]byte
+
+	CHRMode  byte
+	CHRUpper byte
+	// CHRBanks holds $5120-$5127 (sprites, or everything with 8x8 sprites), then $5128-$512B (8x16 background).
+	CHRBanks [12]uint16
+	// LastCHRB is set when $5128-$512B was written last. CPU reads with 8x16 sprites use the last written set.
+	LastCHRB bool
+
+	ExRAM      [0x400]byte
+	ExRAMMode  byte
+	Nametables byte
+	FillTile   byte
+	FillAttr   byte
+
+	SplitCtrl   byte
+	SplitScroll byte
+	SplitBank   byte
+
+	IRQCompare      byte
+	IRQEnabled      bool
+	IRQPending      bool
+	InFrame         bool
+	ScanlineCounter byte
+	IdleCycles      uint
+
+	Multiplicand byte
+	Multiplier   byte
+
+	TallSprites bool
+	// Tile is the number of nametable fetches since the scanline started.
+	Tile byte
+	// InSplit is set when the tile being fetched is in the vertical split region.
+	InSplit bool
+	// SplitColumn is the column of the split tile being fetched.
+	SplitColumn byte
+	// ExAttr is the ExRAM byte for the tile being fetched in extended attribute mode.
+	ExAttr byte
+
+	Audio apu.MMC5
+}
+
+func (m *Mapper5) Cartridge() *Cartridge { return m.cartridge }
+
+func (m *Mapper5) SetCartridge(c *Cartridge) { m.cartridge = c }
+
+func (m *Mapper5) ExpansionAudio() apu.Expansion { return &m.Audio }
+
+func (m *Mapper5) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		if m.TallSprites && m.LastCHRB {
+			return m.cartridge.CHR[m.chrOffsetB(addr)]
+		}
+		return m.cartridge.CHR[m.chrOffsetA(addr)]
+	case addr == 0x5010, addr == 0x5015:
+		return m.Audio.Read(addr)
+	case addr == 0x5204:
+		var data byte
+		if m.IRQPending {
+			data |= 0x80
+		}
+		if m.InFrame {
+			data |= 0x40
+		}
+		m.IRQPending = false
+		return data
+	case addr == 0x5205:
+		return byte(m.product())
+	case addr == 0x5206:
+		return byte(m.product() >> 8)
+	case 0x5C00 <= addr && addr < 0x6000:
+		if m.ExRAMMode >= 2 {
+			return m.ExRAM[addr-0x5C00]
+		}
+		return 0
+	case 0x6000 <= addr:
+		offset, rom := m.prgOffset(addr)
+		if rom {
+			return m.cartridge.PRG[offset]
+		}
+		return m.cartridge.SRAM[offset]
+	default:
+		return 0
+	}
+}
+
+func (m *Mapper5) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x6000 {
+		return 0, false
+	}
+	offset, rom := m.prgOffset(addr)
+	return offset, rom
+}
+
+func (m *Mapper5) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	return m.chrOffsetA(addr), true
+}
+
+func (m *Mapper5) WriteMem(addr uint16, data byte) {
+	switch {
+	case addr < 0x2000:
+		if m.TallSprites && m.LastCHRB {
+			m.cartridge.CHR[m.chrOffsetB(addr)] = data
+		} else {
+			m.cartridge.CHR[m.chrOffsetA(addr)] = data
+		}
+	case 0x5000 <= addr && addr <= 0x5015:
+		m.Audio.Write(addr, data)
+	case addr == 0x5100:
+		m.PRGMode = data & 3
+	case addr == 0x5101:
+		m.CHRMode = data & 3
+	case addr == 0x5102, addr == 0x5103:
+		m.RAMProtect[addr-0x5102] = data & 3
+	case addr == 0x5104:
+		m.ExRAMMode = data & 3
+	case addr == 0x5105:
+		m.Nametables = data
+	case addr == 0x5106:
+		m.FillTile = data
+	case addr == 0x5107:
+		m.FillAttr = data & 3
+	case addr == 0x5113:
+		m.PRGRAMBank = data
+	case 0x5114 <= addr && addr <= 0x5117:
+		m.PRGBanks[addr-0x5114] = data
+	case 0x5120 <= addr && addr <= 0x512B:
+		m.CHRBanks[addr-0x5120] = uint16(m.CHRUpper)<<8 | uint16(data)
+		m.LastCHRB = addr >= 0x5128
+	case addr == 0x5130:
+		m.CHRUpper = data & 3
+	case addr == 0x5200:
+		m.SplitCtrl = data
+	case addr == 0x5201:
+		m.SplitScroll = data
+	case addr == 0x5202:
+		m.SplitBank = data
+	case addr == 0x5203:
+		m.IRQCompare = data
+	case addr == 0x5204:
+		m.IRQEnabled = data&0x80 != 0
+	case addr == 0x5205:
+		m.Multiplicand = data
+	case addr == 0x5206:
+		m.Multiplier = data
+	case 0x5C00 <= addr && addr < 0x6000:
+		switch m.ExRAMMode {
+		case 0, 1:
+			// Writes outside rendering store 0
+			if !m.InFrame {
+				data = 0
+			}
+			m.ExRAM[addr-0x5C00] = data
+		case 2:
+			m.ExRAM[addr-0x5C00] = data
+		}
+	case 0x6000 <= addr:
+		if offset, rom := m.prgOffset(addr); !rom && m.RAMProtect == [2]byte{2, 1} {
+			m.cartridge.SRAM[offset] = data
+		}
+	}
+}
+
+func (m *Mapper5) product() uint16 {
+	return uint16(m.Multiplicand) * uint16(m.Multiplier)
+}
+
+// prgOffset returns the PRG ROM or RAM offset for a CPU address from $6000-$FFFF, and whether it is ROM.
+func (m *Mapper5) prgOffset(addr uint16) (int, bool) {
+	offset := int(addr % 0x2000)
+	if addr < 0x8000 {
+		bank := int(m.PRGRAMBank & 7)
+		return (bank*0x2000 + offset) % len(m.cartridge.SRAM), false
+	}
+
+	slot := int(addr-0x8000) / 0x2000
+	// reg is the register that controls the slot, and size is the number of 8 KiB banks that it switches
+	var reg, size int
+	switch m.PRGMode {
+	case 0:
+		reg, size = 3, 4
+	case 1:
+		reg, size = slot&2|1, 2
+	case 2:
+		if slot < 2 {
+			reg, size = 1, 2
+		} else {
+			reg, size = slot, 1
+		}
+	default:
+		reg, size = slot, 1
+	}
+
+	data := m.PRGBanks[reg]
+	bank := int(data&0x7F)&^(size-1) | slot&(size-1)
+	if reg != 3 && data&0x80 == 0 {
+		bank &= 7
+		return (bank*0x2000 + offset) % len(m.cartridge.SRAM), false
+	}
+	return (bank*0x2000 + offset) % len(m.cartridge.PRG), true
+}
+
+// chrOffsetA returns the CHR offset using $5120-$5127.
+func (m *Mapper5) chrOffsetA(addr uint16) int {
+	switch m.CHRMode {
+	case 0:
+		return m.chrOffset(m.CHRBanks[7], 0x2000, addr)
+	case 1:
+		return m.chrOffset(m.CHRBanks[addr/0x1000*4+3], 0x1000, addr)
+	case 2:
+		return m.chrOffset(m.CHRBanks[addr/0x800*2+1], 0x800, addr)
+	default:
+		return m.chrOffset(m.CHRBanks[addr/0x400], 0x400, addr)
+	}
+}
+
+// chrOffsetB returns the CHR offset using $5128-$512B, which are used for both pattern tables.
+func (m *Mapper5) chrOffsetB(addr uint16) int {
+	switch m.CHRMode {
+	case 0:
+		return m.chrOffset(m.CHRBanks[11], 0x2000, addr)
+	case 1:
+		return m.chrOffset(m.CHRBanks[11], 0x1000, addr)
+	case 2:
+		return m.chrOffset(m.CHRBanks[8+addr%0x1000/0x800*2+1], 0x800, addr)
+	default:
+		return m.chrOffset(m.CHRBanks[8+addr%0x1000/0x400], 0x400, addr)
+	}
+}
+
+func (m *Mapper5) chrOffset(bank uint16, size int, addr uint16) int {
+	return (int(bank)*size + int(addr)%size) % len(m.cartridge.CHR)
+}
+
+func (m *Mapper5) ReadNametable(ciram *[0x800]byte, addr uint16) byte {
+	offset := addr % 0x400
+	switch m.Nametables >> (addr / 0x400 % 4 * 2) & 3 {
+	case 0:
+		return ciram[offset]
+	case 1:
+		return ciram[0x400+offset]
+	case 2:
+		if m.ExRAMMode < 2 {
+			return m.ExRAM[offset]
+		}
+		return 0
+	default:
+		// Fill mode
+		if offset >= 0x3C0 {
+			return m.FillAttr * 0x55
+		}
+		return m.FillTile
+	}
+}
+
+func (m *Mapper5) WriteNametable(ciram *[0x800]byte, addr uint16, data byte) {
+	offset := addr % 0x400
+	switch m.Nametables >> (addr / 0x400 % 4 * 2) & 3 {
+	case 0:
+		ciram[offset] = data
+	case 1:
+		ciram[0x400+offset] = data
+	case 2:
+		if m.ExRAMMode < 2 {
+			m.ExRAM[offset] = data
+		}
+	}
+}
+
+func (m *Mapper5) RenderFetch(ciram *[0x800]byte, kind FetchKind, addr uint16) byte {
+	switch kind {
+	case FetchNametable:
+		tile := m.Tile
+		m.Tile++
+		m.InSplit = m.inSplit(tile)
+		if m.InSplit {
+			m.SplitColumn = tile % 32
+			return m.ExRAM[int(m.splitY())/8*32+int(m.SplitColumn)]
+		}
+		if m.ExRAMMode == 1 {
+			m.ExAttr = m.ExRAM[addr%0x400]
+		}
+		return m.ReadNametable(ciram, addr)
+	case FetchAttribute:
+		if m.InSplit {
+			y, x := int(m.splitY()), int(m.SplitColumn)
+			attr := m.ExRAM[0x3C0+y/32*8+x/4]
+			shift := y/16%2*4 + x/2%2*2
+			return attr >> shift & 3 * 0x55
+		}
+		if m.ExRAMMode == 1 {
+			return m.ExAttr >> 6 * 0x55
+		}
+		return m.ReadNametable(ciram, addr)
+	case FetchBackground:
+		switch {
+		case m.InSplit:
+			addr = addr&0xFF8 | uint16(m.splitY()%8)
+			return m.cartridge.CHR[m.chrOffset(uint16(m.SplitBank), 0x1000, addr)]
+		case m.ExRAMMode == 1:
+			bank := uint16(m.CHRUpper)<<6 | uint16(m.ExAttr&0x3F)
+			return m.cartridge.CHR[m.chrOffset(bank, 0x1000, addr)]
+		case m.TallSprites:
+			return m.cartridge.CHR[m.chrOffsetB(addr)]
+		}
+	}
+	return m.cartridge.CHR[m.chrOffsetA(addr)]
+}
+
+// inSplit reports whether a background tile is in the vertical split region.
+// Tiles 0 and 1 are fetched at the end of the previous scanline.
+func (m *Mapper5) inSplit(tile byte) bool {
+	if m.SplitCtrl&0x80 == 0 || m.ExRAMMode >= 2 {
+		return false
+	}
+	count := m.SplitCtrl & 0x1F
+	if m.SplitCtrl&0x40 != 0 {
+		return tile >= count
+	}
+	return tile < count
+}
+
+// splitY returns the vertical scroll of the split region for the current scanline.
+func (m *Mapper5) splitY() byte {
+	return byte((int(m.SplitScroll) + int(m.ScanlineCounter)) % 240)
+}
+
+func (m *Mapper5) OnPPUWrite(addr uint16, data byte) {
+	switch addr {
+	case 0x2000:
+		m.TallSprites = data&0x20 != 0
+	case 0x2001:
+		if data&0x18 == 0 {
+			m.InFrame = false
+		}
+	}
+}
+
+func (m *Mapper5) OnScanline() {
+	if m.InFrame {
+		m.ScanlineCounter++
+		if m.ScanlineCounter == m.IRQCompare {
+			m.IRQPending = true
+		}
+	} else {
+		m.InFrame = true
+		m.ScanlineCounter = 0
+		m.IRQPending = false
+	}
+	m.Tile = 0
+	m.IdleCycles = 0
+}
+
+func (m *Mapper5) OnCPUStep(cycles uint) {
+	if m.InFrame {
+		m.IdleCycles += cycles
+		if m.IdleCycles > mmc5IdleCycles {
+			m.InFrame = false
+		}
+	}
+}
+
+func (m *Mapper5) IRQ() bool { return m.IRQEnabled && m.IRQPending }
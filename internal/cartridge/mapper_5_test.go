@@ -0,0 +1,185 @@
+package cartridge
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMapper5 returns an MMC5 with 16 PRG banks and 16 CHR banks.
+func testMapper5() *Mapper5 {
+	cart := testCartridge(16, 16)
+	cart.SRAM = make([]byte, 0x10000)
+	return NewMapper5(cart)
+}
+
+func TestMapper5_PRG(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode byte
+		want [4]byte
+	}{
+		{0, [4]byte{12, 13, 14, 15}},
+		{1, [4]byte{4, 5, 14, 15}},
+		{2, [4]byte{4, 5, 6, 15}},
+		{3, [4]byte{3, 5, 6, 15}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.mode)), func(t *testing.T) {
+			t.Parallel()
+			m := testMapper5()
+			m.WriteMem(0x5100, tt.mode)
+			m.WriteMem(0x5114, 0x83)
+			m.WriteMem(0x5115, 0x85)
+			m.WriteMem(0x5116, 0x86)
+			m.WriteMem(0x5117, 0x0F)
+
+			var got [4]byte
+			for i := range got {
+				got[i] = m.ReadMem(0x8000 + uint16(i)*0x2000)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("ram", func(t *testing.T) {
+		t.Parallel()
+		m := testMapper5()
+		m.WriteMem(0x5114, 0x02)
+		m.WriteMem(0x8000, 0xAB)
+		assert.Equal(t, byte(0), m.ReadMem(0x8000), "write protected")
+
+		m.WriteMem(0x5102, 2)
+		m.WriteMem(0x5103, 1)
+		m.WriteMem(0x8000, 0xAB)
+		assert.Equal(t, byte(0xAB), m.ReadMem(0x8000))
+		m.WriteMem(0x5113, 0x02)
+		assert.Equal(t, byte(0xAB), m.ReadMem(0x6000))
+	})
+}
+
+func TestMapper5_CHR(t *testing.T) {
+	t.Parallel()
+
+	m := testMapper5()
+	m.WriteMem(0x5101, 3)
+	for i := range uint16(12) {
+		m.WriteMem(0x5120+i, byte(i))
+	}
+	assert.Equal(t, byte(5), m.ReadMem(0x1400))
+	assert.Equal(t, byte(5), m.RenderFetch(nil, FetchBackground, 0x1400))
+
+	// 8x16 sprites use the B set for the background
+	m.OnPPUWrite(0x2000, 0x20)
+	assert.Equal(t, byte(5), m.RenderFetch(nil, FetchSprite, 0x1400))
+	assert.Equal(t, byte(9), m.RenderFetch(nil, FetchBackground, 0x1400))
+	assert.Equal(t, byte(9), m.ReadMem(0x1400), "last written set")
+}
+
+func TestMapper5_Nametables(t *testing.T) {
+	t.Parallel()
+
+	var ciram [0x800]byte
+	m := testMapper5()
+	// Vertical, then ExRAM, then fill mode
+	m.WriteMem(0x5105, 0b11_10_01_00)
+	m.WriteMem(0x5106, 0x42)
+	m.WriteMem(0x5107, 2)
+	m.OnScanline()
+
+	m.WriteNametable(&ciram, 0x2400, 1)
+	m.WriteNametable(&ciram, 0x2800, 2)
+	m.WriteNametable(&ciram, 0x2C00, 3)
+	assert.Equal(t, byte(1), ciram[0x400])
+	assert.Equal(t, byte(2), m.ExRAM[0])
+	assert.Equal(t, byte(0x42), m.ReadNametable(&ciram, 0x2C00))
+	assert.Equal(t, byte(0xAA), m.ReadNametable(&ciram, 0x2FC0))
+}
+
+func TestMapper5_ExRAM(t *testing.T) {
+	t.Parallel()
+
+	m := testMapper5()
+	m.WriteMem(0x5C00, 1)
+	assert.Equal(t, byte(0), m.ExRAM[0], "writes outside rendering store 0")
+	m.OnScanline()
+	m.WriteMem(0x5C00, 1)
+	assert.Equal(t, byte(1), m.ExRAM[0])
+	assert.Equal(t, byte(0), m.ReadMem(0x5C00), "not readable in mode 0")
+
+	m.WriteMem(0x5104, 2)
+	m.WriteMem(0x5C00, 2)
+	assert.Equal(t, byte(2), m.ReadMem(0x5C00))
+
+	m.WriteMem(0x5104, 3)
+	m.WriteMem(0x5C00, 3)
+	assert.Equal(t, byte(2), m.ReadMem(0x5C00), "read-only in mode 3")
+}
+
+func TestMapper5_ExtendedAttributes(t *testing.T) {
+	t.Parallel()
+
+	var ciram [0x800]byte
+	m := testMapper5()
+	m.WriteMem(0x5104, 1)
+	m.OnScanline()
+	m.WriteMem(0x5C05, 0xC1)
+
+	m.RenderFetch(&ciram, FetchNametable, 0x2005)
+	assert.Equal(t, byte(0xFF), m.RenderFetch(&ciram, FetchAttribute, 0x23C1))
+	// 4 KiB bank 1 is 1 KiB banks 4-7
+	assert.Equal(t, byte(6), m.RenderFetch(&ciram, FetchBackground, 0x0810))
+}
+
+func TestMapper5_Split(t *testing.T) {
+	t.Parallel()
+
+	var ciram [0x800]byte
+	m := testMapper5()
+	m.OnScanline()
+	// Left 2 tiles, scrolled down 8 lines
+	m.WriteMem(0x5200, 0x82)
+	m.WriteMem(0x5201, 8)
+	m.WriteMem(0x5202, 2)
+	m.WriteMem(0x5C20, 0x11)
+	m.WriteMem(0x5FC0, 0b11_10_01_00)
+
+	assert.Equal(t, byte(0x11), m.RenderFetch(&ciram, FetchNametable, 0x2000))
+	assert.Equal(t, byte(0x00), m.RenderFetch(&ciram, FetchAttribute, 0x23C0))
+	assert.Equal(t, byte(8), m.RenderFetch(&ciram, FetchBackground, 0x0110))
+	m.RenderFetch(&ciram, FetchNametable, 0x2001)
+	m.RenderFetch(&ciram, FetchNametable, 0x2002)
+	assert.False(t, m.InSplit)
+}
+
+func TestMapper5_IRQ(t *testing.T) {
+	t.Parallel()
+
+	m := testMapper5()
+	m.WriteMem(0x5203, 2)
+	m.WriteMem(0x5204, 0x80)
+
+	m.OnScanline()
+	assert.Equal(t, byte(0x40), m.ReadMem(0x5204))
+	m.OnScanline()
+	assert.False(t, m.IRQ())
+	m.OnScanline()
+	assert.True(t, m.IRQ())
+	assert.Equal(t, byte(0xC0), m.ReadMem(0x5204))
+	assert.False(t, m.IRQ(), "reading status acknowledges the IRQ")
+
+	m.OnCPUStep(mmc5IdleCycles + 1)
+	assert.Equal(t, byte(0), m.ReadMem(0x5204))
+}
+
+func TestMapper5_Multiplier(t *testing.T) {
+	t.Parallel()
+
+	m := testMapper5()
+	m.WriteMem(0x5205, 0xFF)
+	m.WriteMem(0x5206, 0x12)
+	assert.Equal(t, byte(0xEE), m.ReadMem(0x5205))
+	assert.Equal(t, byte(0x11), m.ReadMem(0x5206))
+}
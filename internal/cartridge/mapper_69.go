@@ -72,6 +72,25 @@ func (m *Mapper69) ReadMem(addr uint16) byte {
 	}
 }
 
+func (m *Mapper69) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x6000 || (addr < 0x8000 && m.RAMSelect) {
+		return 0, false
+	}
+	addr -= 0x6000
+	bank := addr / 0x2000
+	offset := int(addr % 0x2000)
+	return (m.PRGBanks[bank]*0x2000 + offset) % len(m.cartridge.PRG), true
+}
+
+func (m *Mapper69) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	bank := addr / 0x400
+	offset := int(addr % 0x400)
+	return (m.CHRBanks[bank]*0x400 + offset) % len(m.cartridge.CHR), true
+}
+
 func (m *Mapper69) WriteMem(addr uint16, data byte) {
 	switch {
 	case 0x6000 <= addr && addr < 0x8000:
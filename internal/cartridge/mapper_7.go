@@ -37,6 +37,18 @@ func (m *Mapper7) ReadMem(addr uint16) byte {
 	}
 }
 
+func (m *Mapper7) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	offset := uint(addr-0x8000) + m.PRGBank*2*consts.PRGChunkSize
+	return int(offset % uint(len(m.cartridge.PRG))), true
+}
+
+func (m *Mapper7) CHROffset(addr uint16) (int, bool) {
+	return int(addr & 0x1FFF), addr < 0x2000
+}
+
 func (m *Mapper7) WriteMem(addr uint16, data byte) {
 	switch {
 	case addr < 0x2000:
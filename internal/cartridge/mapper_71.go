@@ -44,6 +44,21 @@ func (m *Mapper71) ReadMem(addr uint16) byte {
 	}
 }
 
+func (m *Mapper71) PRGOffset(addr uint16) (int, bool) {
+	switch {
+	case 0x8000 <= addr && addr < 0xC000:
+		return int(uint(addr-0x8000) + m.PRGActive*consts.PRGChunkSize), true
+	case 0xC000 <= addr:
+		return int(uint(addr-0xC000) + m.PRGLast*consts.PRGChunkSize), true
+	default:
+		return 0, false
+	}
+}
+
+func (m *Mapper71) CHROffset(addr uint16) (int, bool) {
+	return int(addr), addr < 0x2000
+}
+
 func (m *Mapper71) WriteMem(addr uint16, data byte) {
 	switch {
 	case addr < 0x2000:
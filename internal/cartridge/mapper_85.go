@@ -0,0 +1,129 @@
+package cartridge
+
+import "gabe565.com/gones/internal/apu"
+
+func NewMapper85(cartridge *Cartridge) *Mapper85 {
+	return &Mapper85{cartridge: cartridge}
+}
+
+// Mapper85 is the Konami VRC7.
+// VRC7a boards use A4 to select a register's second address, and VRC7b boards use A3. Both are accepted.
+//
+// See [VRC7].
+//
+// [VRC7]: https://www.nesdev.org/wiki/VRC7
+type Mapper85 struct {
+	cartridge *Cartridge
+
+	PRGBanks [3]byte
+	CHRBanks [8]byte
+	// Control is the $E000 mirroring, sound reset, and PRG RAM enable register.
+	Control byte
+
+	IRQCounter VRCIRQ
+	Audio      apu.VRC7
+}
+
+func (m *Mapper85) Cartridge() *Cartridge { return m.cartridge }
+
+func (m *Mapper85) SetCartridge(c *Cartridge) { m.cartridge = c }
+
+func (m *Mapper85) ExpansionAudio() apu.Expansion { return &m.Audio }
+
+func (m *Mapper85) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		offset, _ := m.CHROffset(addr)
+		return m.cartridge.CHR[offset]
+	case 0x6000 <= addr && addr < 0x8000:
+		if m.ramEnabled() {
+			return m.cartridge.SRAM[int(addr-0x6000)%len(m.cartridge.SRAM)]
+		}
+		return 0
+	case 0x8000 <= addr:
+		offset, _ := m.PRGOffset(addr)
+		return m.cartridge.PRG[offset]
+	default:
+		return 0
+	}
+}
+
+func (m *Mapper85) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	bank := len(m.cartridge.PRG)/0x2000 - 1
+	if slot := (addr - 0x8000) / 0x2000; slot < 3 {
+		bank = int(m.PRGBanks[slot])
+	}
+	return (bank*0x2000 + int(addr%0x2000)) % len(m.cartridge.PRG), true
+}
+
+func (m *Mapper85) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	bank := int(m.CHRBanks[addr/0x400])
+	return (bank*0x400 + int(addr%0x400)) % len(m.cartridge.CHR), true
+}
+
+func (m *Mapper85) WriteMem(addr uint16, data byte) {
+	switch {
+	case addr < 0x2000:
+		if m.cartridge.CHRIsRAM() {
+			offset, _ := m.CHROffset(addr)
+			m.cartridge.CHR[offset] = data
+		}
+		return
+	case 0x6000 <= addr && addr < 0x8000:
+		if m.ramEnabled() {
+			m.cartridge.SRAM[int(addr-0x6000)%len(m.cartridge.SRAM)] = data
+		}
+		return
+	case addr < 0x8000:
+		return
+	case addr&0xF030 == 0x9010, addr&0xF030 == 0x9030:
+		m.Audio.Write(addr&0xF030, data)
+		return
+	}
+
+	var second uint16
+	if addr&0x18 != 0 {
+		second = 1
+	}
+
+	switch reg := addr & 0xF000; reg {
+	case 0x8000:
+		m.PRGBanks[second] = data & 0x3F
+	case 0x9000:
+		if second == 0 {
+			m.PRGBanks[2] = data & 0x3F
+		}
+	case 0xA000, 0xB000, 0xC000, 0xD000:
+		m.CHRBanks[(reg-0xA000)/0x800+second] = data
+	case 0xE000:
+		if second == 0 {
+			m.Control = data
+			m.cartridge.Mirror = vrcMirrors[data&3]
+			m.Audio.SetSilenced(data&0x40 != 0)
+		} else {
+			m.IRQCounter.WriteLatch(data)
+		}
+	case 0xF000:
+		if second == 0 {
+			m.IRQCounter.WriteControl(data)
+		} else {
+			m.IRQCounter.Ack()
+		}
+	}
+}
+
+func (m *Mapper85) ramEnabled() bool {
+	return m.Control&0x80 != 0 && len(m.cartridge.SRAM) != 0
+}
+
+func (m *Mapper85) OnCPUStep(cycles uint) {
+	m.IRQCounter.step(cycles)
+}
+
+func (m *Mapper85) IRQ() bool { return m.IRQCounter.Pending }
@@ -0,0 +1,44 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper85(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		addr uint16
+	}{
+		{"vrc7a", 0x8010},
+		{"vrc7b", 0x8008},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cart := testCartridge(8, 8)
+			m := NewMapper85(cart)
+
+			m.WriteMem(0x8000, 2)
+			m.WriteMem(tt.addr, 3)
+			m.WriteMem(0x9000, 4)
+			assert.Equal(t, byte(2), m.ReadMem(0x8000))
+			assert.Equal(t, byte(3), m.ReadMem(0xA000))
+			assert.Equal(t, byte(4), m.ReadMem(0xC000))
+			assert.Equal(t, byte(7), m.ReadMem(0xE000))
+
+			// $9010 selects an audio register instead of a PRG bank
+			m.WriteMem(0x9010, 0x30)
+			m.WriteMem(0x9030, 0x4F)
+			assert.Equal(t, byte(4), m.ReadMem(0xC000))
+			assert.Equal(t, byte(4), m.Audio.Channels[0].Instrument)
+
+			m.WriteMem(0xE000, 0x41)
+			assert.Equal(t, Horizontal, cart.Mirror)
+			assert.True(t, m.Audio.Silenced)
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package cartridge
+
+import (
+	"log/slog"
+
+	"gabe565.com/gones/internal/apu"
+	"gabe565.com/gones/internal/consts"
+)
+
+const (
+	// fdsByteCycles is the number of CPU cycles that the drive takes to transfer a byte.
+	fdsByteCycles = 150
+	// fdsRewindCycles is the number of CPU cycles that the drive takes to return to the start of the disk.
+	fdsRewindCycles = 50000
+	// fdsInsertCycles is the number of CPU cycles that a disk stays ejected while switching sides.
+	// The BIOS needs to see the disk removed before it will read the new side.
+	fdsInsertCycles = consts.CPUFrequency
+)
+
+func NewMapperFDS(cartridge *Cartridge) *MapperFDS {
+	clear(cartridge.SRAM)
+	clear(cartridge.CHR)
+	return &MapperFDS{
+		cartridge:  cartridge,
+		EndOfHead:  true,
+		InsertSide: -1,
+	}
+}
+
+// MapperFDS is the Famicom Disk System RAM adapter and disk drive.
+//
+// See [FDS].
+//
+// [FDS]: https://www.nesdev.org/wiki/Family_Computer_Disk_System
+type MapperFDS struct {
+	cartridge *Cartridge
+
+	// Side is the inserted disk side, or -1 when the disk is ejected.
+	Side int
+	// InsertSide is the side that will be inserted once InsertDelay expires, or -1.
+	InsertSide  int
+	InsertDelay int
+
+	IRQReload   uint16
+	IRQCounter  uint16
+	IRQRepeat   bool
+	IRQEnabled  bool
+	TimerIRQ    bool
+	DiskEnabled bool
+
+	MotorOn        bool
+	ResetTransfer  bool
+	ReadMode       bool
+	CRCControl     bool
+	DiskReady      bool
+	DiskIRQEnabled bool
+	DiskIRQ        bool
+
+	Scanning         bool
+	EndOfHead        bool
+	GapEnded         bool
+	TransferComplete bool
+	Position         int
+	Delay            int
+	ReadData         byte
+	WriteData        byte
+	CRC              uint16
+	PrevCRCControl   bool
+	ExtOutput        byte
+
+	Audio apu.FDS
+}
+
+func (m *MapperFDS) Cartridge() *Cartridge { return m.cartridge }
+
+func (m *MapperFDS) SetCartridge(c *Cartridge) { m.cartridge = c }
+
+func (m *MapperFDS) ExpansionAudio() apu.Expansion { return &m.Audio }
+
+func (m *MapperFDS) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		return m.cartridge.CHR[addr]
+	case addr == 0x4030:
+		// Disk status
+		var data byte
+		if m.TimerIRQ {
+			data |= 0x01
+		}
+		if m.TransferComplete {
+			data |= 0x02
+		}
+		if m.EndOfHead {
+			data |= 0x40
+		}
+		m.TransferComplete = false
+		m.TimerIRQ = false
+		m.DiskIRQ = false
+		return data
+	case addr == 0x4031:
+		// Read data
+		m.TransferComplete = false
+		m.DiskIRQ = false
+		return m.ReadData
+	case addr == 0x4032:
+		// Drive status
+		var data byte
+		if m.Side < 0 {
+			// Not inserted, not ready, and write protected
+			data |= 0x07
+		} else if !m.Scanning {
+			data |= 0x02
+		}
+		return data | 0x40
+	case addr == 0x4033:
+		// External connector, with the battery good bit set
+		return 0x80 | m.ExtOutput&0x7F
+	case 0x6000 <= addr && addr < 0xE000:
+		return m.cartridge.SRAM[addr-0x6000]
+	case 0xE000 <= addr:
+		return m.cartridge.PRG[int(addr-0xE000)%len(m.cartridge.PRG)]
+	default:
+		return 0
+	}
+}
+
+func (m *MapperFDS) PRGOffset(addr uint16) (int, bool) {
+	if addr < 0xE000 {
+		return 0, false
+	}
+	return int(addr-0xE000) % len(m.cartridge.PRG), true
+}
+
+func (m *MapperFDS) CHROffset(addr uint16) (int, bool) {
+	if addr >= 0x2000 {
+		return 0, false
+	}
+	return int(addr), true
+}
+
+func (m *MapperFDS) WriteMem(addr uint16, data byte) {
+	switch {
+	case addr < 0x2000:
+		m.cartridge.CHR[addr] = data
+	case addr == 0x4020:
+		m.IRQReload = m.IRQReload&0xFF00 | uint16(data)
+	case addr == 0x4021:
+		m.IRQReload = uint16(data)<<8 | m.IRQReload&0xFF
+	case addr == 0x4022:
+		m.IRQRepeat = data&1 == 1
+		m.IRQEnabled = data>>1&1 == 1 && m.DiskEnabled
+		if m.IRQEnabled {
+			m.IRQCounter = m.IRQReload
+		} else {
+			m.TimerIRQ = false
+		}
+	case addr == 0x4023:
+		m.DiskEnabled = data&1 == 1
+		if !m.DiskEnabled {
+			m.IRQEnabled = false
+			m.TimerIRQ = false
+			m.DiskIRQ = false
+		}
+	case addr == 0x4024 && m.DiskEnabled:
+		m.WriteData = data
+		m.TransferComplete = false
+		m.DiskIRQ = false
+	case addr == 0x4025 && m.DiskEnabled:
+		m.DiskIRQ = false
+		m.MotorOn = data&1 == 1
+		m.ResetTransfer = data>>1&1 == 1
+		m.ReadMode = data>>2&1 == 1
+		if data>>3&1 == 1 {
+			m.cartridge.Mirror = Horizontal
+		} else {
+			m.cartridge.Mirror = Vertical
+		}
+		m.CRCControl = data>>4&1 == 1
+		m.DiskReady = data>>6&1 == 1
+		m.DiskIRQEnabled = data>>7&1 == 1
+	case addr == 0x4026 && m.DiskEnabled:
+		m.ExtOutput = data
+	case 0x4040 <= addr && addr <= 0x408A:
+		m.Audio.Write(addr, data)
+	case 0x6000 <= addr && addr < 0xE000:
+		m.cartridge.SRAM[addr-0x6000] = data
+	}
+}
+
+func (m *MapperFDS) OnCPUStep(cycles uint) {
+	for range cycles {
+		m.stepTimer()
+		m.stepDrive()
+	}
+
+	if m.InsertSide >= 0 {
+		m.InsertDelay -= int(cycles)
+		if m.InsertDelay <= 0 {
+			m.Insert(m.InsertSide)
+		}
+	}
+}
+
+func (m *MapperFDS) IRQ() bool { return m.TimerIRQ || m.DiskIRQ }
+
+func (m *MapperFDS) stepTimer() {
+	if !m.IRQEnabled || !m.DiskEnabled {
+		return
+	}
+
+	if m.IRQCounter == 0 {
+		m.TimerIRQ = true
+		m.IRQCounter = m.IRQReload
+		if !m.IRQRepeat {
+			m.IRQEnabled = false
+		}
+	} else {
+		m.IRQCounter--
+	}
+}
+
+func (m *MapperFDS) stepDrive() {
+	if m.Side < 0 || !m.MotorOn {
+		m.EndOfHead = true
+		m.Scanning = false
+		return
+	}
+
+	if m.ResetTransfer && !m.Scanning {
+		return
+	}
+
+	if m.EndOfHead {
+		// Rewind to the start of the disk
+		m.Delay = fdsRewindCycles
+		m.EndOfHead = false
+		m.Position = 0
+		m.GapEnded = false
+		return
+	}
+
+	if m.Delay > 0 {
+		m.Delay--
+		return
+	}
+
+	m.Scanning = true
+	side := m.cartridge.Disk.Sides[m.Side]
+	irq := m.DiskIRQEnabled
+
+	if m.ReadMode {
+		data := side[m.Position]
+		switch {
+		case !m.DiskReady:
+			m.GapEnded = false
+		case data != 0 && !m.GapEnded:
+			// Start mark
+			m.GapEnded = true
+			irq = false
+		}
+		if m.GapEnded {
+			m.TransferComplete = true
+			m.ReadData = data
+			if irq {
+				m.DiskIRQ = true
+			}
+		}
+	} else {
+		var data byte
+		if m.CRCControl {
+			if !m.PrevCRCControl {
+				m.CRC = updateFDSCRC(m.CRC, 0)
+				m.CRC = updateFDSCRC(m.CRC, 0)
+			}
+			data = byte(m.CRC)
+			m.CRC >>= 8
+		} else {
+			m.TransferComplete = true
+			if irq {
+				m.DiskIRQ = true
+			}
+			data = m.WriteData
+			m.CRC = updateFDSCRC(m.CRC, data)
+		}
+
+		if !m.DiskReady {
+			data = 0
+			m.CRC = 0
+		}
+		m.cartridge.Disk.Write(m.Side, m.Position, data)
+		m.GapEnded = false
+	}
+
+	m.PrevCRCControl = m.CRCControl
+	m.Position++
+	if m.Position >= len(side) {
+		m.MotorOn = false
+	} else {
+		m.Delay = fdsByteCycles
+	}
+}
+
+// Insert inserts a disk side.
+func (m *MapperFDS) Insert(side int) {
+	m.InsertSide = -1
+	m.InsertDelay = 0
+	if side < 0 || side >= len(m.cartridge.Disk.Sides) {
+		return
+	}
+	m.Side = side
+	slog.Info("Inserted FDS disk", "side", SideName(side))
+}
+
+// Eject removes the disk from the drive.
+func (m *MapperFDS) Eject() {
+	if m.Side < 0 {
+		return
+	}
+	slog.Info("Ejected FDS disk", "side", SideName(m.Side))
+	m.Side = -1
+}
+
+// SwitchSide ejects the disk, then inserts the next side once the BIOS has seen it removed.
+// If the next side is already waiting to be inserted, the side after it is chosen instead.
+func (m *MapperFDS) SwitchSide() {
+	next := m.Side
+	if m.InsertSide >= 0 {
+		next = m.InsertSide
+	}
+	next = (next + 1) % len(m.cartridge.Disk.Sides)
+
+	m.Eject()
+	m.InsertSide = next
+	m.InsertDelay = fdsInsertCycles
+	slog.Info("Switching FDS disk", "side", SideName(next))
+}
@@ -0,0 +1,59 @@
+package cartridge
+
+//go:generate go tool stringer -type Timing -trimprefix Timing
+
+// Timing is the CPU/PPU timing that a game was made for.
+type Timing byte
+
+const (
+	TimingNTSC Timing = iota
+	TimingPAL
+	// TimingMulti games run on both NTSC and PAL consoles.
+	TimingMulti
+	TimingDendy
+)
+
+//go:generate go tool stringer -type ConsoleType -trimprefix Console
+
+// ConsoleType is the console that a game runs on.
+// Values above ConsoleExtended are NES 2.0 extended console types.
+type ConsoleType byte
+
+const (
+	ConsoleNES ConsoleType = iota
+	ConsoleVsSystem
+	ConsolePlaychoice10
+	ConsoleExtended
+	ConsoleFamicloneDecimal
+	ConsoleEPSM
+	ConsoleVT01
+	ConsoleVT02
+	ConsoleVT03
+	ConsoleVT09
+	ConsoleVT32
+	ConsoleVT369
+	ConsoleUM6578
+	ConsoleFamicomNetworkSystem
+)
+
+// ExpansionDevice is the NES 2.0 default expansion device.
+// Only the devices that the emulator can use are named.
+//
+// See [Default Expansion Device].
+//
+// [Default Expansion Device]: https://www.nesdev.org/wiki/NES_2.0#Default_Expansion_Device
+type ExpansionDevice byte
+
+const (
+	ExpansionUnspecified ExpansionDevice = 0x00
+	ExpansionStandard    ExpansionDevice = 0x01
+	ExpansionFourScore   ExpansionDevice = 0x02
+	// ExpansionFamicomFourPlayer is four players through Famicom expansion port controllers.
+	ExpansionFamicomFourPlayer ExpansionDevice = 0x03
+	ExpansionVsZapper          ExpansionDevice = 0x07
+	ExpansionZapper            ExpansionDevice = 0x08
+	ExpansionTwoZappers        ExpansionDevice = 0x09
+	ExpansionPowerPadA         ExpansionDevice = 0x0B
+	ExpansionPowerPadB         ExpansionDevice = 0x0C
+	ExpansionArkanoid          ExpansionDevice = 0x0F
+)
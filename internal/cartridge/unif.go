@@ -0,0 +1,217 @@
+package cartridge
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/database"
+	"gabe565.com/gones/internal/util"
+)
+
+const unifHeaderSize = 32
+
+var (
+	ErrInvalidUNIF      = errors.New("invalid UNIF file")
+	ErrUnsupportedBoard = errors.New("unsupported UNIF board")
+)
+
+// unifBoards maps UNIF board names to iNES mapper numbers.
+// Board names are matched without their "NES-", "HVC-", "UNL-", "BTL-", or "BMC-" prefix.
+//
+//nolint:gochecknoglobals
+var unifBoards = map[string]uint16{
+	"NROM": 0, "NROM-128": 0, "NROM-256": 0, "RROM": 0, "RROM-128": 0,
+
+	"SAROM": 1, "SBROM": 1, "SCROM": 1, "SEROM": 1, "SFROM": 1, "SGROM": 1, "SHROM": 1,
+	"SJROM": 1, "SKROM": 1, "SLROM": 1, "SL1ROM": 1, "SNROM": 1, "SOROM": 1, "SUROM": 1, "SXROM": 1,
+
+	"UNROM": 2, "UOROM": 2,
+
+	"CNROM": 3,
+
+	"TBROM": 4, "TEROM": 4, "TFROM": 4, "TGROM": 4, "TKROM": 4, "TLROM": 4,
+	"TL1ROM": 4, "TR1ROM": 4, "TSROM": 4, "TVROM": 4, "HKROM": 4,
+
+	"EKROM": 5, "ELROM": 5, "ETROM": 5, "EWROM": 5,
+
+	"AMROM": 7, "ANROM": 7, "AN1ROM": 7, "AOROM": 7,
+
+	"BTR": 69, "JLROM": 69, "JSROM": 69,
+}
+
+// unifBoardMapper returns the iNES mapper for a UNIF board name.
+func unifBoardMapper(board string) (uint16, bool) {
+	board = strings.ToUpper(board)
+	for _, prefix := range []string{"NES-", "HVC-", "UNL-", "BTL-", "BMC-"} {
+		board = strings.TrimPrefix(board, prefix)
+	}
+	mapper, ok := unifBoards[board]
+	return mapper, ok
+}
+
+func FromUNIFFile(path string) (*Cartridge, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	cartridge, err := FromUNIF(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if cartridge.name == "" {
+		cartridge.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return cartridge, nil
+}
+
+// FromUNIF loads a UNIF file. An iNES header is generated from the board name so that the cartridge
+// can use the same mappers as iNES files.
+//
+// See [UNIF].
+//
+// [UNIF]: https://www.nesdev.org/wiki/UNIF
+func FromUNIF(r io.Reader) (*Cartridge, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < unifHeaderSize || !bytes.HasPrefix(b, []byte("UNIF")) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUNIF, "missing UNIF header")
+	}
+
+	var board, name string
+	var prg, chr [16][]byte
+	cartridge := New()
+	for data := b[unifHeaderSize:]; len(data) != 0; {
+		if len(data) < 8 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidUNIF, "truncated chunk")
+		}
+		id := string(data[:4])
+		size := int(binary.LittleEndian.Uint32(data[4:]))
+		data = data[8:]
+		if size > len(data) {
+			return nil, fmt.Errorf("%w: truncated %s chunk", ErrInvalidUNIF, id)
+		}
+		chunk := data[:size:size]
+		data = data[size:]
+
+		switch {
+		case id == "MAPR":
+			board = util.CString(chunk)
+		case id == "NAME":
+			name = util.CString(chunk)
+		case id == "MIRR" && len(chunk) != 0:
+			switch chunk[0] {
+			case 0:
+				cartridge.Mirror = Horizontal
+			case 1:
+				cartridge.Mirror = Vertical
+			case 2:
+				cartridge.Mirror = SingleLower
+			case 3:
+				cartridge.Mirror = SingleUpper
+			case 4:
+				cartridge.Mirror = FourScreen
+			}
+		case id == "BATR":
+			cartridge.Battery = true
+		case id == "TVCI" && len(chunk) != 0:
+			if chunk[0] == 1 {
+				cartridge.Timing = TimingPAL
+			}
+		case strings.HasPrefix(id, "PRG") && isHexDigit(id[3]):
+			prg[hexDigit(id[3])] = chunk
+		case strings.HasPrefix(id, "CHR") && isHexDigit(id[3]):
+			chr[hexDigit(id[3])] = chunk
+		}
+	}
+
+	if board == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUNIF, "missing MAPR chunk")
+	}
+	mapper, ok := unifBoardMapper(board)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBoard, board)
+	}
+
+	cartridge.Board = board
+	cartridge.PRG = bytes.Join(prg[:], nil)
+	if len(cartridge.PRG) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUNIF, "missing PRG chunk")
+	}
+	cartridge.CHR = bytes.Join(chr[:], nil)
+
+	// Sizes that aren't a multiple of the iNES chunk sizes need NES 2.0
+	cartridge.Header.SetNESv2(true)
+	cartridge.Header.SetMapper(mapper)
+	if err := cartridge.Header.SetPRGSize(len(cartridge.PRG)); err != nil {
+		return nil, err
+	}
+	if err := cartridge.Header.SetCHRSize(len(cartridge.CHR)); err != nil {
+		return nil, err
+	}
+	cartridge.Header.SetBattery(cartridge.Battery)
+	cartridge.Header.SetTiming(cartridge.Timing)
+	if mirror := cartridge.Mirror; mirror == SingleLower || mirror == SingleUpper {
+		cartridge.singleScreen = &mirror
+	} else {
+		cartridge.Header.SetMirror(mirror)
+	}
+	if len(cartridge.CHR) == 0 {
+		cartridge.CHR = make([]byte, consts.CHRChunkSize)
+	}
+
+	slog.Debug("Loaded UNIF file",
+		"board", board,
+		"mapper", mapper,
+		"battery", cartridge.Battery,
+		"mirror", cartridge.Mirror,
+		"prg", len(cartridge.PRG),
+		"chr", cartridge.Header.CHRSize(),
+	)
+
+	sum := md5.Sum(b)
+	cartridge.hash = hex.EncodeToString(sum[:])
+	cartridge.name, _ = database.FindNameByHash(cartridge.hash)
+	if cartridge.name == "" {
+		cartridge.name = name
+	}
+	return cartridge, nil
+}
+
+// IsUNIFFile reports whether a path has a UNIF extension.
+func IsUNIFFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".unf", ".unif":
+		return true
+	default:
+		return false
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'A' <= c && c <= 'F'
+}
+
+func hexDigit(c byte) int {
+	if c <= '9' {
+		return int(c - '0')
+	}
+	return int(c-'A') + 10
+}
@@ -0,0 +1,151 @@
+package cartridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unifChunk(id string, data []byte) []byte {
+	b := append([]byte(id), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+	return append(b, data...)
+}
+
+func testUNIF(chunks ...[]byte) []byte {
+	b := make([]byte, unifHeaderSize)
+	copy(b, "UNIF")
+	binary.LittleEndian.PutUint32(b[4:], 7)
+	for _, chunk := range chunks {
+		b = append(b, chunk...)
+	}
+	return b
+}
+
+func TestFromUNIF(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mmc1", func(t *testing.T) {
+		t.Parallel()
+		prg0 := bytes.Repeat([]byte{1}, 0x4000)
+		prg1 := bytes.Repeat([]byte{2}, 0x4000)
+		chr0 := bytes.Repeat([]byte{3}, 0x2000)
+		cart, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("MAPR", []byte("NES-SNROM\x00")),
+			unifChunk("NAME", []byte("Test\x00")),
+			unifChunk("PRG1", prg1),
+			unifChunk("PRG0", prg0),
+			unifChunk("CHR0", chr0),
+			unifChunk("MIRR", []byte{1}),
+			unifChunk("BATR", []byte{1}),
+		)))
+		require.NoError(t, err)
+		assert.Equal(t, "NES-SNROM", cart.Board)
+		assert.EqualValues(t, 1, cart.Header.Mapper())
+		assert.Equal(t, "Test", cart.Name())
+		assert.Equal(t, append(prg0, prg1...), cart.PRG)
+		assert.Equal(t, chr0, cart.CHR)
+		assert.False(t, cart.CHRIsRAM())
+		assert.Equal(t, Vertical, cart.Mirror)
+		assert.Equal(t, Vertical, cart.Header.Mirror())
+		assert.True(t, cart.Battery)
+		assert.True(t, cart.Header.Battery())
+		assert.Equal(t, 0x8000, cart.Header.PRGSize())
+	})
+
+	t.Run("chr ram", func(t *testing.T) {
+		t.Parallel()
+		cart, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("MAPR", []byte("UNL-UNROM")),
+			unifChunk("PRG0", make([]byte, 0x8000)),
+			unifChunk("TVCI", []byte{1}),
+		)))
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, cart.Header.Mapper())
+		assert.True(t, cart.CHRIsRAM())
+		assert.Len(t, cart.CHR, 0x2000)
+		assert.Equal(t, TimingPAL, cart.Timing)
+	})
+
+	t.Run("single screen", func(t *testing.T) {
+		t.Parallel()
+		cart, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("MAPR", []byte("NES-AOROM")),
+			unifChunk("PRG0", make([]byte, 0x8000)),
+			unifChunk("MIRR", []byte{3}),
+		)))
+		require.NoError(t, err)
+		assert.Equal(t, SingleUpper, cart.Mirror)
+		assert.Equal(t, SingleUpper, cart.PowerOnMirror())
+	})
+
+	t.Run("odd sizes", func(t *testing.T) {
+		t.Parallel()
+		cart, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("MAPR", []byte("UNL-UNROM")),
+			unifChunk("PRG0", make([]byte, 0x6000)),
+			unifChunk("CHR0", make([]byte, 0x1000)),
+		)))
+		require.NoError(t, err)
+		assert.True(t, cart.Header.NESv2())
+		assert.Equal(t, 0x6000, cart.Header.PRGSize())
+		assert.Equal(t, 0x1000, cart.Header.CHRSize())
+		assert.False(t, cart.CHRIsRAM())
+	})
+
+	t.Run("unsupported board", func(t *testing.T) {
+		t.Parallel()
+		_, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("MAPR", []byte("UNL-UNKNOWN")),
+			unifChunk("PRG0", make([]byte, 0x8000)),
+		)))
+		require.ErrorIs(t, err, ErrUnsupportedBoard)
+	})
+
+	t.Run("missing board", func(t *testing.T) {
+		t.Parallel()
+		_, err := FromUNIF(bytes.NewReader(testUNIF(
+			unifChunk("PRG0", make([]byte, 0x8000)),
+		)))
+		require.ErrorIs(t, err, ErrInvalidUNIF)
+	})
+
+	t.Run("truncated chunk", func(t *testing.T) {
+		t.Parallel()
+		b := testUNIF(unifChunk("PRG0", make([]byte, 0x8000)))
+		_, err := FromUNIF(bytes.NewReader(b[:len(b)-1]))
+		require.ErrorIs(t, err, ErrInvalidUNIF)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := FromUNIF(bytes.NewReader([]byte("NES\x1A")))
+		require.ErrorIs(t, err, ErrInvalidUNIF)
+	})
+}
+
+func Test_unifBoardMapper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		board  string
+		want   uint16
+		wantOK bool
+	}{
+		{"NES-SXROM", 1, true},
+		{"nes-sxrom", 1, true},
+		{"Hvc-UnRom", 2, true},
+		{"AOROM", 7, true},
+		{"unl-unknown", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.board, func(t *testing.T) {
+			t.Parallel()
+			got, ok := unifBoardMapper(tt.board)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cartridge
+
+const (
+	// vrcPrescalerPeriod is the VRC IRQ prescaler period in thirds of a CPU cycle, which is one scanline.
+	vrcPrescalerPeriod = 341
+	// vrcPrescalerStep is the amount that the prescaler counts down each CPU cycle.
+	vrcPrescalerStep = 3
+)
+
+// VRCIRQ is the IRQ counter shared by Konami's VRC4, VRC6, and VRC7.
+// It counts scanlines using a CPU cycle prescaler, or counts CPU cycles directly.
+//
+// See [VRC IRQ].
+//
+// [VRC IRQ]: https://www.nesdev.org/wiki/VRC_IRQ
+type VRCIRQ struct {
+	Latch     byte
+	Counter   byte
+	Prescaler int
+
+	Enabled        bool
+	EnableAfterAck bool
+	CycleMode      bool
+	Pending        bool
+}
+
+func (v *VRCIRQ) WriteLatch(data byte) {
+	v.Latch = data
+}
+
+func (v *VRCIRQ) WriteControl(data byte) {
+	v.EnableAfterAck = data&1 != 0
+	v.Enabled = data&2 != 0
+	v.CycleMode = data&4 != 0
+	v.Pending = false
+	if v.Enabled {
+		v.Counter = v.Latch
+		v.Prescaler = vrcPrescalerPeriod
+	}
+}
+
+func (v *VRCIRQ) Ack() {
+	v.Pending = false
+	v.Enabled = v.EnableAfterAck
+}
+
+func (v *VRCIRQ) step(cycles uint) {
+	if !v.Enabled {
+		return
+	}
+	for range cycles {
+		if v.CycleMode {
+			v.clock()
+			continue
+		}
+		v.Prescaler -= vrcPrescalerStep
+		if v.Prescaler <= 0 {
+			v.Prescaler += vrcPrescalerPeriod
+			v.clock()
+		}
+	}
+}
+
+func (v *VRCIRQ) clock() {
+	if v.Counter == 0xFF {
+		v.Counter = v.Latch
+		v.Pending = true
+	} else {
+		v.Counter++
+	}
+}
@@ -0,0 +1,175 @@
+package cdl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/cpu"
+)
+
+// PRG flags use the FCEUX format, which Mesen can also read.
+//
+// See [FCEUX Code/Data Logger].
+//
+// [FCEUX Code/Data Logger]: https://fceux.com/web/help/CodeDataLogger.html
+const (
+	Code         = 0x01
+	Data         = 0x02
+	BankMask     = 0x0C
+	IndirectCode = 0x10
+	IndirectData = 0x20
+	PCM          = 0x40
+)
+
+// CHR flags
+const (
+	Drawn = 0x01
+	Read  = 0x02
+)
+
+var ErrSize = errors.New("CDL size does not match the cartridge")
+
+// Logger marks PRG and CHR bytes as they are used.
+// It implements the bus and PPU watcher interfaces.
+type Logger struct {
+	PRG []byte
+	CHR []byte
+
+	prg cartridge.MapperPRGOffset
+	chr cartridge.MapperCHROffset
+
+	pc       uint16
+	length   uint16
+	indirect bool
+	jump     bool
+	inCPU    bool
+}
+
+// New creates a logger for a cartridge.
+// CHR bytes are only logged when the cartridge has CHR ROM.
+func New(cart *cartridge.Cartridge, mapper cartridge.Mapper) *Logger {
+	l := &Logger{PRG: make([]byte, len(cart.PRG))}
+	if !cart.CHRIsRAM() {
+		l.CHR = make([]byte, len(cart.CHR))
+	}
+	l.SetMapper(mapper)
+	return l
+}
+
+// SetMapper changes the mapper used to translate addresses to ROM offsets.
+// Mappers which cannot report offsets are ignored.
+func (l *Logger) SetMapper(mapper cartridge.Mapper) {
+	l.prg, _ = mapper.(cartridge.MapperPRGOffset)
+	l.chr, _ = mapper.(cartridge.MapperCHROffset)
+}
+
+// BeforeStep must be called before the CPU runs the instruction at pc.
+func (l *Logger) BeforeStep(pc uint16, code byte) {
+	l.pc = pc
+	l.inCPU = true
+	if op := cpu.Lookup(code); op != nil {
+		l.length = uint16(op.Len)
+		l.indirect = op.Mode == cpu.IndirectX || op.Mode == cpu.IndirectY
+		l.jump = op.Mode == cpu.Indirect
+	} else {
+		l.length = 1
+		l.indirect = false
+		l.jump = false
+	}
+}
+
+// AfterStep must be called once the CPU has run an instruction.
+// Any reads before the next call to [Logger.BeforeStep] are treated as DMC sample fetches.
+func (l *Logger) AfterStep(pc uint16) {
+	if l.jump {
+		l.mark(pc, IndirectCode)
+	}
+	l.inCPU = false
+}
+
+func (l *Logger) OnRead(addr uint16, _ byte) {
+	switch {
+	case !l.inCPU:
+		l.mark(addr, PCM)
+	case addr-l.pc < l.length:
+		l.mark(addr, Code)
+	case l.indirect:
+		l.mark(addr, IndirectData|Data)
+	default:
+		l.mark(addr, Data)
+	}
+}
+
+func (l *Logger) OnWrite(uint16, byte) {}
+
+func (l *Logger) mark(addr uint16, flags byte) {
+	if l.prg == nil {
+		return
+	}
+	if offset, ok := l.prg.PRGOffset(addr); ok && offset < len(l.PRG) {
+		l.PRG[offset] |= flags | byte(addr>>13&3)<<2
+	}
+}
+
+// OnCHRDraw marks a pattern table byte that was fetched while rendering.
+func (l *Logger) OnCHRDraw(addr uint16) {
+	l.markCHR(addr, Drawn)
+}
+
+// OnCHRRead marks a pattern table byte that was read through PPUDATA.
+func (l *Logger) OnCHRRead(addr uint16) {
+	l.markCHR(addr, Read)
+}
+
+func (l *Logger) markCHR(addr uint16, flags byte) {
+	if l.chr == nil || l.CHR == nil {
+		return
+	}
+	if offset, ok := l.chr.CHROffset(addr); ok && offset < len(l.CHR) {
+		l.CHR[offset] |= flags
+	}
+}
+
+// WriteTo writes the log as PRG flags followed by CHR flags.
+func (l *Logger) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(l.PRG)
+	if err != nil {
+		return int64(n), err
+	}
+	n2, err := w.Write(l.CHR)
+	return int64(n + n2), err
+}
+
+// Merge adds the flags from a previously saved log, so that coverage accumulates across sessions.
+func (l *Logger) Merge(b []byte) error {
+	if len(b) != len(l.PRG)+len(l.CHR) {
+		return fmt.Errorf("%w: got %d bytes, expected %d", ErrSize, len(b), len(l.PRG)+len(l.CHR))
+	}
+	for i, v := range b[:len(l.PRG)] {
+		l.PRG[i] |= v
+	}
+	for i, v := range b[len(l.PRG):] {
+		l.CHR[i] |= v
+	}
+	return nil
+}
+
+// Coverage returns the fraction of PRG and CHR bytes that have been logged.
+func (l *Logger) Coverage() (float64, float64) {
+	return coverage(l.PRG), coverage(l.CHR)
+}
+
+func coverage(b []byte) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+	var n int
+	for _, v := range b {
+		if v != 0 {
+			n++
+		}
+	}
+	return float64(n) / float64(len(b))
+}
@@ -0,0 +1,81 @@
+package cdl
+
+import (
+	"bytes"
+	"testing"
+
+	"gabe565.com/gones/internal/cartridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubLogger() *Logger {
+	cart := cartridge.FromBytes(nil)
+	cart.Header.CHRCount = 1
+	return New(cart, cartridge.NewMapper2(cart, false))
+}
+
+func TestLogger_PRG(t *testing.T) {
+	t.Parallel()
+
+	l := stubLogger()
+
+	// LDA ($10),Y at $8000 which reads from $C000
+	l.BeforeStep(0x8000, 0xB1)
+	l.OnRead(0x8000, 0xB1)
+	l.OnRead(0x8001, 0x10)
+	l.OnRead(0x0010, 0x00)
+	l.OnRead(0xC000, 0x00)
+	l.AfterStep(0x8002)
+
+	assert.EqualValues(t, Code, l.PRG[0x0000])
+	assert.EqualValues(t, Code, l.PRG[0x0001])
+	assert.EqualValues(t, IndirectData|Data|2<<2, l.PRG[0x4000])
+
+	// JMP ($FFF0) to $A000
+	l.BeforeStep(0x8002, 0x6C)
+	l.OnRead(0xFFF0, 0x00)
+	l.AfterStep(0xA000)
+	assert.EqualValues(t, Data|3<<2, l.PRG[0x7FF0])
+	assert.EqualValues(t, IndirectCode|1<<2, l.PRG[0x2000])
+
+	// DMC sample fetch outside of a CPU step
+	l.OnRead(0xE000, 0x00)
+	assert.EqualValues(t, PCM|3<<2, l.PRG[0x6000])
+}
+
+func TestLogger_CHR(t *testing.T) {
+	t.Parallel()
+
+	l := stubLogger()
+	l.OnCHRDraw(0x0010)
+	l.OnCHRRead(0x0010)
+	l.OnCHRRead(0x1FFF)
+	assert.EqualValues(t, Drawn|Read, l.CHR[0x0010])
+	assert.EqualValues(t, Read, l.CHR[0x1FFF])
+
+	prg, chr := l.Coverage()
+	assert.Zero(t, prg)
+	assert.InDelta(t, 2.0/0x2000, chr, 0.0001)
+}
+
+func TestLogger_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	l := stubLogger()
+	l.PRG[1] = Code
+	l.CHR[2] = Drawn
+
+	var buf bytes.Buffer
+	n, err := l.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, len(l.PRG)+len(l.CHR), n)
+
+	merged := stubLogger()
+	merged.PRG[1] = Data
+	require.NoError(t, merged.Merge(buf.Bytes()))
+	assert.EqualValues(t, Code|Data, merged.PRG[1])
+	assert.EqualValues(t, Drawn, merged.CHR[2])
+
+	require.ErrorIs(t, merged.Merge(buf.Bytes()[1:]), ErrSize)
+}
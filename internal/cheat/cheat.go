@@ -0,0 +1,94 @@
+// Package cheat applies Game Genie and raw memory cheats.
+package cheat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/genie"
+)
+
+// Cheat replaces the value at an address.
+//
+// Addresses in $8000-$FFFF patch ROM reads, like a Game Genie.
+// Lower addresses are written to RAM once per frame, like a Pro Action Replay.
+type Cheat struct {
+	Address uint16
+	Value   byte
+	// Compare is -1 when the value is always replaced.
+	Compare int
+}
+
+// IsROM reports whether the cheat patches ROM reads instead of writing to RAM.
+func (c Cheat) IsROM() bool {
+	return c.Address >= 0x8000
+}
+
+func (c Cheat) String() string {
+	if c.Compare == -1 {
+		return fmt.Sprintf("%04X:%02X", c.Address, c.Value)
+	}
+	return fmt.Sprintf("%04X:%02X:%02X", c.Address, c.Value, c.Compare)
+}
+
+var ErrInvalidCode = errors.New("invalid cheat code")
+
+// Parse parses a cheat code. Supported formats are:
+//   - Game Genie codes, for example "SXIOPO" or "YEUZUGAA"
+//   - ADDR:VALUE or ADDR:VALUE:COMPARE in hex, for example "0075:09" or "91D9:AD:B5"
+//   - Pro Action Replay codes, which are ADDR and VALUE without a separator, for example "007509".
+//     Codes that are also valid Game Genie codes, like "AEAEAE", are parsed as Game Genie codes.
+func Parse(code string) (Cheat, error) {
+	code = strings.TrimSpace(code)
+
+	if strings.Contains(code, ":") {
+		parts := strings.Split(code, ":")
+		if len(parts) > 3 {
+			return Cheat{}, fmt.Errorf("%w %q: expected ADDR:VALUE or ADDR:VALUE:COMPARE", ErrInvalidCode, code)
+		}
+		return parseRaw(code, parts[0], parts[1], parts[2:]...)
+	}
+
+	decoded, err := genie.Decode(code)
+	if err != nil {
+		// Six hex digits are a Pro Action Replay code, unless they are also a valid Game Genie code
+		if len(code) == 6 {
+			if _, hexErr := strconv.ParseUint(code, 16, 32); hexErr == nil {
+				return parseRaw(code, code[:4], code[4:])
+			}
+		}
+		return Cheat{}, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+	}
+	return Cheat{
+		Address: uint16(decoded.Address), //nolint:gosec
+		Value:   byte(decoded.Replace),
+		Compare: decoded.Compare,
+	}, nil
+}
+
+func parseRaw(code, addr, value string, compare ...string) (Cheat, error) {
+	c := Cheat{Compare: -1}
+
+	a, err := strconv.ParseUint(addr, 16, 16)
+	if err != nil {
+		return c, fmt.Errorf("%w %q: %w", ErrInvalidCode, code, err)
+	}
+	c.Address = uint16(a)
+
+	v, err := strconv.ParseUint(value, 16, 8)
+	if err != nil {
+		return c, fmt.Errorf("%w %q: %w", ErrInvalidCode, code, err)
+	}
+	c.Value = byte(v)
+
+	if len(compare) != 0 {
+		v, err := strconv.ParseUint(compare[0], 16, 8)
+		if err != nil {
+			return c, fmt.Errorf("%w %q: %w", ErrInvalidCode, code, err)
+		}
+		c.Compare = int(v)
+	}
+	return c, nil
+}
@@ -0,0 +1,37 @@
+package cheat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		code    string
+		want    Cheat
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"game genie 6", "SXIOPO", Cheat{Address: 0x91D9, Value: 0xAD, Compare: -1}, require.NoError},
+		{"game genie 8", "yeuzugaa", Cheat{Address: 0xACB3, Value: 0x07, Compare: 0x00}, require.NoError},
+		{"raw", "0075:09", Cheat{Address: 0x75, Value: 0x09, Compare: -1}, require.NoError},
+		{"raw compare", "91D9:AD:B5", Cheat{Address: 0x91D9, Value: 0xAD, Compare: 0xB5}, require.NoError},
+		{"pro action replay", "007509", Cheat{Address: 0x75, Value: 0x09, Compare: -1}, require.NoError},
+		{"game genie hex letters", "AEAEAE", Cheat{Address: 0x8088, Value: 0x08, Compare: -1}, require.NoError},
+		{"too many parts", "0075:09:00:00", Cheat{}, require.Error},
+		{"invalid value", "0075:100", Cheat{Address: 0x75, Compare: -1}, require.Error},
+		{"invalid genie", "SXIOP", Cheat{}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.code)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
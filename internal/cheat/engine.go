@@ -0,0 +1,63 @@
+package cheat
+
+import "gabe565.com/gones/internal/memory"
+
+// Engine applies a set of cheats.
+// It implements the bus patcher interface.
+type Engine struct {
+	rom map[uint16][]Cheat
+	ram []Cheat
+}
+
+// NewEngine creates an engine from a list of cheats.
+func NewEngine(cheats []Cheat) *Engine {
+	e := &Engine{rom: make(map[uint16][]Cheat)}
+	for _, c := range cheats {
+		e.Add(c)
+	}
+	return e
+}
+
+// Add enables another cheat.
+func (e *Engine) Add(c Cheat) {
+	if c.IsROM() {
+		e.rom[c.Address] = append(e.rom[c.Address], c)
+	} else {
+		e.ram = append(e.ram, c)
+	}
+}
+
+// Len returns the number of cheats.
+func (e *Engine) Len() int {
+	n := len(e.ram)
+	for _, c := range e.rom {
+		n += len(c)
+	}
+	return n
+}
+
+// PatchRead replaces a byte read from ROM.
+// When a compare value is set, the byte is only replaced if it matches.
+func (e *Engine) PatchRead(addr uint16, data byte) byte {
+	for _, c := range e.rom[addr] {
+		if c.Compare == -1 || byte(c.Compare) == data {
+			return c.Value
+		}
+	}
+	return data
+}
+
+// RAMWriter writes to memory without side effects.
+type RAMWriter interface {
+	memory.ReadSafe
+	WriteMemSafe(addr uint16, data byte) bool
+}
+
+// Apply writes RAM cheats. It should be called once per frame.
+func (e *Engine) Apply(mem RAMWriter) {
+	for _, c := range e.ram {
+		if c.Compare == -1 || byte(c.Compare) == mem.ReadMemSafe(c.Address) {
+			mem.WriteMemSafe(c.Address, c.Value)
+		}
+	}
+}
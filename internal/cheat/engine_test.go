@@ -0,0 +1,48 @@
+package cheat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMemory [0x10000]byte
+
+func (m *stubMemory) ReadMemSafe(addr uint16) byte { return m[addr] }
+
+func (m *stubMemory) WriteMemSafe(addr uint16, data byte) bool {
+	m[addr] = data
+	return true
+}
+
+func TestEngine_PatchRead(t *testing.T) {
+	t.Parallel()
+
+	e := NewEngine([]Cheat{
+		{Address: 0x8000, Value: 0xEA, Compare: -1},
+		{Address: 0x9000, Value: 0x01, Compare: 0x02},
+	})
+	assert.Equal(t, 2, e.Len())
+	assert.EqualValues(t, 0xEA, e.PatchRead(0x8000, 0x00))
+	assert.EqualValues(t, 0x01, e.PatchRead(0x9000, 0x02))
+	assert.EqualValues(t, 0x03, e.PatchRead(0x9000, 0x03))
+	assert.EqualValues(t, 0x42, e.PatchRead(0xA000, 0x42))
+}
+
+func TestEngine_Apply(t *testing.T) {
+	t.Parallel()
+
+	var mem stubMemory
+	mem[0x11] = 0x05
+	e := NewEngine([]Cheat{
+		{Address: 0x10, Value: 0x63, Compare: -1},
+		{Address: 0x11, Value: 0x09, Compare: 0x04},
+	})
+	e.Apply(&mem)
+	assert.EqualValues(t, 0x63, mem[0x10])
+	assert.EqualValues(t, 0x05, mem[0x11])
+
+	mem[0x11] = 0x04
+	e.Apply(&mem)
+	assert.EqualValues(t, 0x09, mem[0x11])
+}
@@ -0,0 +1,124 @@
+package cheat
+
+import (
+	"errors"
+	"slices"
+)
+
+// Comparison narrows the candidates of a [Search].
+type Comparison uint8
+
+const (
+	// Equal keeps addresses that have not changed since the last snapshot.
+	Equal Comparison = iota
+	// NotEqual keeps addresses that have changed since the last snapshot.
+	NotEqual
+	// Greater keeps addresses that have increased since the last snapshot.
+	Greater
+	// Less keeps addresses that have decreased since the last snapshot.
+	Less
+	// ChangedBy keeps addresses that have changed by a specific amount since the last snapshot.
+	ChangedBy
+	// Value keeps addresses that currently hold a specific value.
+	Value
+)
+
+func (c Comparison) match(current, previous byte, value int) bool {
+	switch c {
+	case Equal:
+		return current == previous
+	case NotEqual:
+		return current != previous
+	case Greater:
+		return current > previous
+	case Less:
+		return current < previous
+	case ChangedBy:
+		return current-previous == byte(value)
+	case Value:
+		return current == byte(value)
+	default:
+		return false
+	}
+}
+
+// Region is a block of CPU memory that can be searched.
+type Region struct {
+	Start uint16
+	Data  []byte
+}
+
+// Result is a candidate address of a [Search].
+type Result struct {
+	Address  uint16
+	Value    byte
+	Previous byte
+}
+
+var ErrRegionsChanged = errors.New("search regions changed size")
+
+// Search finds addresses by comparing memory snapshots across frames.
+type Search struct {
+	addrs      []uint16
+	snapshot   []byte
+	previous   []byte
+	candidates []int
+}
+
+// NewSearch snapshots the regions. Every address starts as a candidate.
+func NewSearch(regions ...Region) *Search {
+	s := &Search{}
+	for _, r := range regions {
+		for i := range r.Data {
+			s.addrs = append(s.addrs, r.Start+uint16(i)) //nolint:gosec
+		}
+		s.snapshot = append(s.snapshot, r.Data...)
+	}
+	s.previous = slices.Clone(s.snapshot)
+	s.candidates = make([]int, len(s.snapshot))
+	for i := range s.candidates {
+		s.candidates[i] = i
+	}
+	return s
+}
+
+// Filter compares the regions to the last snapshot and removes candidates which do not match.
+// The value is only used by [ChangedBy] and [Value]. The regions are then snapshotted for the next filter.
+// It returns the number of remaining candidates.
+func (s *Search) Filter(cmp Comparison, value int, regions ...Region) (int, error) {
+	current := make([]byte, 0, len(s.snapshot))
+	for _, r := range regions {
+		current = append(current, r.Data...)
+	}
+	if len(current) != len(s.snapshot) {
+		return len(s.candidates), ErrRegionsChanged
+	}
+
+	kept := s.candidates[:0]
+	for _, i := range s.candidates {
+		if cmp.match(current[i], s.snapshot[i], value) {
+			kept = append(kept, i)
+		}
+	}
+	s.candidates = kept
+	s.previous, s.snapshot = s.snapshot, current
+	return len(s.candidates), nil
+}
+
+// Len returns the number of remaining candidates.
+func (s *Search) Len() int {
+	return len(s.candidates)
+}
+
+// Results returns the remaining candidates with their values from the last two snapshots.
+func (s *Search) Results() []Result {
+	results := make([]Result, 0, len(s.candidates))
+	for _, i := range s.candidates {
+		results = append(results, Result{
+			Address:  s.addrs[i],
+			Value:    s.snapshot[i],
+			Previous: s.previous[i],
+		})
+	}
+	return results
+}
@@ -0,0 +1,62 @@
+package cheat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	ram := []byte{3, 3, 3, 3}
+	sram := []byte{0, 0}
+	regions := func() []Region {
+		return []Region{{Start: 0, Data: ram}, {Start: 0x6000, Data: sram}}
+	}
+
+	s := NewSearch(regions()...)
+	assert.Equal(t, 6, s.Len())
+
+	// Lives decrease
+	ram[1], ram[2] = 2, 4
+	sram[0] = 1
+	n, err := s.Filter(NotEqual, 0, regions()...)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = s.Filter(Equal, 0, regions()...)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	ram[1], ram[2] = 1, 5
+	n, err = s.Filter(Less, 0, regions()...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []Result{{Address: 1, Value: 1, Previous: 2}}, s.Results())
+
+	n, err = s.Filter(Value, 1, regions()...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	ram[1] = 0
+	n, err = s.Filter(ChangedBy, -1, regions()...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = s.Filter(Equal, 0, Region{Data: ram})
+	require.ErrorIs(t, err, ErrRegionsChanged)
+}
+
+func TestSearch_Greater(t *testing.T) {
+	t.Parallel()
+
+	ram := []byte{1, 2, 3}
+	s := NewSearch(Region{Data: ram})
+	ram[0], ram[2] = 5, 0
+	n, err := s.Filter(Greater, 0, Region{Data: ram})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []Result{{Address: 0, Value: 5, Previous: 1}}, s.Results())
+}
@@ -11,11 +11,15 @@ import (
 )
 
 type Config struct {
-	UI    UI    `toml:"ui"`
-	State State `toml:"state"`
-	Input Input `toml:"input"`
-	Audio Audio `toml:"audio"`
-	Debug Debug `toml:"debug,omitempty"`
+	UI     UI     `toml:"ui"`
+	System System `toml:"system"`
+	State  State  `toml:"state"`
+	Input  Input  `toml:"input"`
+	Audio  Audio  `toml:"audio"`
+	FDS    FDS    `toml:"fds"`
+	Cheats Cheats `toml:"cheats"`
+	Debug  Debug  `toml:"debug,omitempty"`
+	Movie  Movie  `toml:"movie,omitempty"`
 }
 
 type UI struct {
@@ -38,29 +42,53 @@ func (t Overscan) Rect() image.Rectangle {
 	return image.Rect(t.Left, t.Top, consts.Width-t.Right, consts.Height-t.Bottom)
 }
 
+type System struct {
+	Region Region `toml:"region" comment:"Console timing. One of auto, ntsc, pal, or dendy. auto uses the ROM's NES 2.0 header or the game database. Usually set in a game's config file."`
+}
+
 type State struct {
 	Resume           bool     `toml:"resume"            comment:"Automatically resumes the previous game state."`
 	AutosaveInterval Duration `toml:"autosave_interval" comment:"If resume is enabled, the game state will be saved regularly at the configured interval."`
 	UndoStateCount   int      `toml:"undo_state_count"  comment:"Number of undo states to keep in memory."`
+	RewindLength     Duration `toml:"rewind_length"     comment:"Amount of gameplay history to keep in memory for rewinding. Set to 0 to disable rewind."`
+	RewindInterval   int      `toml:"rewind_interval"   comment:"Number of frames between rewind snapshots (minimum: 1). Higher values use less memory, but rewind in larger steps."`
+}
+
+// RewindSnapshots returns the number of rewind snapshots that fit in RewindLength at the region's frame rate.
+func (s State) RewindSnapshots(region consts.Region) int {
+	if s.RewindInterval < 1 {
+		return 0
+	}
+	return int(time.Duration(s.RewindLength).Seconds() * float64(region.TargetFrameRate()) / float64(s.RewindInterval))
 }
 
 type Input struct {
 	Reset             Key      `toml:"reset"               comment:"Key to reset the game (must be held)."`
-	ResetHold         Duration `toml:"reset_hold"          comment:"Time the reset button must be held."`
+	PowerCycle        Key      `toml:"power_cycle"         comment:"Key to power cycle the game (must be held)."`
+	ResetHold         Duration `toml:"reset_hold"          comment:"Time the reset or power cycle button must be held."`
 	State1Save        Key      `toml:"state1_save"         comment:"Key to save the game state (separate from auto resume state)."`
 	State1Load        Key      `toml:"state1_load"         comment:"Key to load the last save state."`
 	StateUndoModifier Key      `toml:"state_undo_modifier" comment:"Hold this key and press the save/load state key, and the action will be undone."`
 	FastForward       Key      `toml:"fast_forward"        comment:"Key to fast-forward the game (must be held)."`
 	FastForwardRate   uint8    `toml:"fast_forward_rate"   comment:"Fast-forward rate multiplier."`
+	Rewind            Key      `toml:"rewind"              comment:"Key to rewind the game (must be held)."`
 	Fullscreen        Key      `toml:"fullscreen"          comment:"Key to toggle fullscreen."`
 	Screenshot        Key      `toml:"screenshot"          comment:"Key to take a screenshot."`
+	PPUViewer         Key      `toml:"ppu_viewer"          comment:"Key to cycle through the PPU viewers (pattern tables, nametables, sprites, palette). Screenshots capture the active viewer."`
+	FDSSwitchSide     Key      `toml:"fds_switch_side"     comment:"Key to eject the FDS disk and insert the next side."`
 	TurboDutyCycle    uint16   `toml:"turbo_duty_cycle"    comment:"Frame duty cycle when turbo key is held (minimum: 2)."`
+	Port1             Port     `toml:"port1"               comment:"Controller port 1."`
+	Port2             Port     `toml:"port2"               comment:"Controller port 2. Light gun and Arkanoid games usually expect the device in port 2."`
+	MultiTap          MultiTap `toml:"multitap"            comment:"Four player adapter. One of none, four_score (NES Four Score), or famicom (Famicom expansion port controllers). Usually set in a game's config file."`
 	Player1           Keymap   `toml:"player1"             comment:"Player 1 keymap."`
 	Player2           Keymap   `toml:"player2"             comment:"Player 2 keymap."`
+	Player3           Keymap   `toml:"player3"             comment:"Player 3 keymap. Only used when a multitap is enabled."`
+	Player4           Keymap   `toml:"player4"             comment:"Player 4 keymap. Only used when a multitap is enabled."`
 }
 
-func (i Input) ResetHoldFrames() int {
-	frames := int(time.Duration(i.ResetHold).Seconds() * 60)
+// ResetHoldFrames returns the number of frames that the reset or power cycle key must be held at the region's frame rate.
+func (i Input) ResetHoldFrames(region consts.Region) int {
+	frames := int(time.Duration(i.ResetHold).Seconds() * float64(region.TargetFrameRate()))
 	if frames == 0 {
 		return 1
 	}
@@ -80,11 +108,75 @@ type AudioChannels struct {
 	Square2  bool `toml:"square_2"`
 	Noise    bool `toml:"noise"`
 	PCM      bool `toml:"pcm"`
+	FDS      bool `toml:"fds"`
+	MMC5     bool `toml:"mmc5"`
+	VRC6     bool `toml:"vrc6"`
+	VRC7     bool `toml:"vrc7"`
+	N163     bool `toml:"n163"`
+}
+
+type FDS struct {
+	BIOS string `toml:"bios" comment:"Famicom Disk System BIOS (usually named disksys.rom). Defaults to disksys.rom in the config directory."`
+}
+
+// BIOSPath returns the configured BIOS path, or the default path in the config directory.
+func (f FDS) BIOSPath() (string, error) {
+	if f.BIOS != "" {
+		return f.BIOS, nil
+	}
+
+	configDir, err := GetDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "disksys.rom"), nil
+}
+
+type Cheats struct {
+	Enabled bool    `toml:"enabled"         comment:"Applies enabled cheat codes. Codes are usually added to a game's config file as [[cheats.codes]] tables with code, name, and enabled keys."`
+	Codes   []Cheat `toml:"codes,omitempty"`
+}
+
+// Cheat is a Game Genie, ADDR:VALUE[:COMPARE], or Pro Action Replay code.
+type Cheat struct {
+	Code    string `toml:"code"`
+	Name    string `toml:"name,omitempty"`
+	Enabled bool   `toml:"enabled"`
 }
 
 type Debug struct {
-	Enabled bool `toml:"enabled"`
-	Trace   bool `toml:"trace"`
+	Enabled  bool   `toml:"enabled"`
+	Trace    bool   `toml:"trace"`
+	Debugger bool   `toml:"debugger"`
+	GDB      string `toml:"gdb"`
+	CDL      bool   `toml:"cdl"`
+
+	TraceFile   string `toml:"trace_file"`
+	TraceFormat string `toml:"trace_format"`
+	TraceRange  string `toml:"trace_range"`
+	TraceBanks  string `toml:"trace_banks"`
+	TraceStart  string `toml:"trace_start"`
+	TraceStop   string `toml:"trace_stop"`
+
+	ViewerScanline int `toml:"viewer_scanline"`
+
+	// SkipSaveData starts the console without loading SRAM or FDS disk changes.
+	SkipSaveData bool `toml:"skip_save_data"`
+}
+
+// Movie configures movie recording and playback.
+// Unless a movie starts from a save state, the console is powered on with cleared SRAM and FDS disk writes
+// so that input replays the same way. Save data is never written while a movie is active.
+type Movie struct {
+	Record    string `toml:"record"`
+	Play      string `toml:"play"`
+	FromState bool   `toml:"from_state"`
+}
+
+// Enabled reports whether a movie will be recorded or played.
+func (m Movie) Enabled() bool {
+	return m.Record != "" || m.Play != ""
 }
 
 const configDir = "gones"
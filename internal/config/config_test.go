@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gabe565.com/gones/internal/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_RewindSnapshots(t *testing.T) {
+	t.Parallel()
+
+	s := State{RewindLength: Duration(time.Minute), RewindInterval: 2}
+	assert.Equal(t, 1800, s.RewindSnapshots(consts.RegionNTSC))
+	assert.Equal(t, 1500, s.RewindSnapshots(consts.RegionPAL))
+	assert.Equal(t, 1500, s.RewindSnapshots(consts.RegionDendy))
+}
+
+func TestInput_ResetHoldFrames(t *testing.T) {
+	t.Parallel()
+
+	i := Input{ResetHold: Duration(time.Second)}
+	assert.Equal(t, 60, i.ResetHoldFrames(consts.RegionNTSC))
+	assert.Equal(t, 50, i.ResetHoldFrames(consts.RegionPAL))
+
+	i.ResetHold = 0
+	assert.Equal(t, 1, i.ResetHoldFrames(consts.RegionNTSC))
+}
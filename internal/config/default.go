@@ -15,13 +15,19 @@ func NewDefault() *Config {
 			RemoveSpriteLimit: true,
 			Overscan:          Overscan{Top: 8, Bottom: 8},
 		},
+		System: System{
+			Region: RegionAuto,
+		},
 		State: State{
 			Resume:           true,
 			AutosaveInterval: Duration(time.Minute),
 			UndoStateCount:   5,
+			RewindLength:     Duration(time.Minute),
+			RewindInterval:   2,
 		},
 		Input: Input{
 			Reset:             Key(ebiten.KeyR),
+			PowerCycle:        Key(ebiten.KeyP),
 			ResetHold:         Duration(500 * time.Millisecond),
 			State1Save:        Key(ebiten.KeyF1),
 			State1Load:        Key(ebiten.KeyF5),
@@ -29,37 +35,49 @@ func NewDefault() *Config {
 
 			FastForward:     Key(ebiten.KeyF),
 			FastForwardRate: 3,
+			Rewind:          Key(ebiten.KeyBackspace),
 			Fullscreen:      Key(ebiten.KeyF11),
 
 			Screenshot: Key(ebiten.KeyBackslash),
+			PPUViewer:  Key(ebiten.KeyF9),
+
+			FDSSwitchSide: Key(ebiten.KeyF7),
 
 			TurboDutyCycle: 4,
+			Port1:          defaultPort(),
+			Port2:          defaultPort(),
+			MultiTap:       MultiTapNone,
 
 			Player1: Keymap{
-				A:      Key(ebiten.KeyM),
-				B:      Key(ebiten.KeyN),
-				Start:  Key(ebiten.KeyEnter),
-				Select: Key(ebiten.KeyShiftRight),
-				Up:     Key(ebiten.KeyW),
-				Down:   Key(ebiten.KeyS),
-				Left:   Key(ebiten.KeyA),
-				Right:  Key(ebiten.KeyD),
-				ATurbo: Key(ebiten.KeyK),
-				BTurbo: Key(ebiten.KeyJ),
+				A:       Key(ebiten.KeyM),
+				B:       Key(ebiten.KeyN),
+				Start:   Key(ebiten.KeyEnter),
+				Select:  Key(ebiten.KeyShiftRight),
+				Up:      Key(ebiten.KeyW),
+				Down:    Key(ebiten.KeyS),
+				Left:    Key(ebiten.KeyA),
+				Right:   Key(ebiten.KeyD),
+				ATurbo:  Key(ebiten.KeyK),
+				BTurbo:  Key(ebiten.KeyJ),
+				Gamepad: defaultGamepad(0),
 			},
 
 			Player2: Keymap{
-				A:      Key(ebiten.KeyKP3),
-				B:      Key(ebiten.KeyKP2),
-				Start:  Key(ebiten.KeyKPEnter),
-				Select: Key(ebiten.KeyKPAdd),
-				Up:     Key(ebiten.KeyHome),
-				Down:   Key(ebiten.KeyEnd),
-				Left:   Key(ebiten.KeyDelete),
-				Right:  Key(ebiten.KeyPageDown),
-				ATurbo: Key(ebiten.KeyKP6),
-				BTurbo: Key(ebiten.KeyKP5),
+				A:       Key(ebiten.KeyKP3),
+				B:       Key(ebiten.KeyKP2),
+				Start:   Key(ebiten.KeyKPEnter),
+				Select:  Key(ebiten.KeyKPAdd),
+				Up:      Key(ebiten.KeyHome),
+				Down:    Key(ebiten.KeyEnd),
+				Left:    Key(ebiten.KeyDelete),
+				Right:   Key(ebiten.KeyPageDown),
+				ATurbo:  Key(ebiten.KeyKP6),
+				BTurbo:  Key(ebiten.KeyKP5),
+				Gamepad: defaultGamepad(1),
 			},
+
+			Player3: unboundKeymap(2),
+			Player4: unboundKeymap(3),
 		},
 		Audio: Audio{
 			Enabled: true,
@@ -70,8 +88,60 @@ func NewDefault() *Config {
 				Square2:  true,
 				Noise:    true,
 				PCM:      true,
+				FDS:      true,
+				MMC5:     true,
+				VRC6:     true,
+				VRC7:     true,
+				N163:     true,
 			},
 			BufferSize: 40 * bytefmt.KiB,
 		},
+		Cheats: Cheats{
+			Enabled: true,
+		},
+	}
+}
+
+func defaultPort() Port {
+	return Port{
+		Type: DeviceGamepad,
+		PowerPad: []Key{
+			Key(ebiten.Key5), Key(ebiten.Key6), Key(ebiten.Key7), Key(ebiten.Key8),
+			Key(ebiten.KeyT), Key(ebiten.KeyY), Key(ebiten.KeyU), Key(ebiten.KeyI),
+			Key(ebiten.KeyX), Key(ebiten.KeyC), Key(ebiten.KeyV), Key(ebiten.KeyB),
+		},
+	}
+}
+
+func unboundKeymap(gamepad int) Keymap {
+	return Keymap{
+		A:       -1,
+		B:       -1,
+		Start:   -1,
+		Select:  -1,
+		Up:      -1,
+		Down:    -1,
+		Left:    -1,
+		Right:   -1,
+		ATurbo:  -1,
+		BTurbo:  -1,
+		Gamepad: defaultGamepad(gamepad),
+	}
+}
+
+func defaultGamepad(index int) Gamepad {
+	return Gamepad{
+		Index:    index,
+		Deadzone: 0.5,
+		A:        []GamepadInput{"RightRight"},
+		B:        []GamepadInput{"RightBottom"},
+		Start:    []GamepadInput{"CenterRight"},
+		Select:   []GamepadInput{"CenterLeft"},
+		Up:       []GamepadInput{"LeftTop", "LeftStickUp"},
+		Down:     []GamepadInput{"LeftBottom", "LeftStickDown"},
+		Left:     []GamepadInput{"LeftLeft", "LeftStickLeft"},
+		Right:    []GamepadInput{"LeftRight", "LeftStickRight"},
+		ATurbo:   []GamepadInput{"RightTop"},
+		BTurbo:   []GamepadInput{"RightLeft"},
 	}
 }
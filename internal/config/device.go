@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Port struct {
+	Type     DeviceType `toml:"type"      comment:"Device plugged into the port. One of gamepad, zapper, arkanoid, or power_pad."`
+	PowerPad []Key      `toml:"power_pad" comment:"Keys for Power Pad buttons 1-12, used when type is power_pad. Buttons are numbered left to right, top to bottom, as on side B of the mat."`
+}
+
+// DeviceType is a peripheral plugged into a controller port.
+type DeviceType string
+
+const (
+	DeviceGamepad DeviceType = "gamepad"
+	// DeviceZapper is the NES Zapper light gun, which is controlled with the mouse.
+	DeviceZapper DeviceType = "zapper"
+	// DeviceArkanoid is the NES Arkanoid Vaus controller, which is controlled with the mouse.
+	DeviceArkanoid DeviceType = "arkanoid"
+	// DevicePowerPad is the NES Power Pad mat, which is controlled with the keyboard.
+	DevicePowerPad DeviceType = "power_pad"
+)
+
+var ErrInvalidDevice = errors.New("invalid device")
+
+func (d DeviceType) MarshalText() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func (d *DeviceType) UnmarshalText(text []byte) error {
+	switch v := DeviceType(text); v {
+	case DeviceGamepad, DeviceZapper, DeviceArkanoid, DevicePowerPad:
+		*d = v
+	case "":
+		*d = DeviceGamepad
+	default:
+		return fmt.Errorf("%w %q: expected %s, %s, %s, or %s",
+			ErrInvalidDevice, v, DeviceGamepad, DeviceZapper, DeviceArkanoid, DevicePowerPad,
+		)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"gabe565.com/gones/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -14,9 +15,23 @@ func Flags(cmd *cobra.Command) {
 	); err != nil {
 		panic(err)
 	}
+	cmd.Flags().String("patch", "", "IPS, BPS, or UPS patch to apply to the ROM (default is a patch next to the ROM with the same name)")
+	if err := cmd.RegisterFlagCompletionFunc("patch", util.CompletePatch); err != nil {
+		panic(err)
+	}
 
 	cmd.Flags().Bool("debug", false, "Start with step debugging enabled")
 	cmd.Flags().Bool("trace", false, "Enable trace logging")
+	cmd.Flags().String("trace-file", "", "Write the trace log to a file instead of stdout (implies --trace)")
+	cmd.Flags().String("trace-format", "nestest", "Trace log format (one of nestest, mesen, or a Go template)")
+	cmd.Flags().String("trace-range", "", "Only trace instructions within a PC range (e.g. 8000-9FFF)")
+	cmd.Flags().String("trace-banks", "", "Only trace instructions within comma-separated 16 KiB PRG ROM banks (e.g. 0,7)")
+	cmd.Flags().String("trace-start", "", "Start tracing once a condition is met (e.g. PC=C000 or 0010!=00)")
+	cmd.Flags().String("trace-stop", "", "Stop tracing once a condition is met (e.g. PC=C000 or 0010!=00)")
+	cmd.Flags().Bool("debugger", false, "Start the interactive CPU debugger on stdin")
+	cmd.Flags().String("gdb", "", "Listen for GDB remote debugger connections on an address (e.g. localhost:2345)")
+	cmd.Flags().Bool("cdl", false, "Log which PRG and CHR bytes are used to a .cdl file next to the save data")
+	cmd.Flags().Int("viewer-scanline", 0, "Scanline (0-261, or 0-311 for PAL and Dendy) at which the PPU viewers refresh")
 	cmd.Flags().Float64("scale", 3, "Default UI scale")
 	cmd.Flags().BoolP("fullscreen", "f", false, "Start in fullscreen")
 	cmd.Flags().BoolP("audio", "a", true, "Enabled audio output")
@@ -33,17 +48,64 @@ func Flags(cmd *cobra.Command) {
 	cmd.Flags().Bool("pause-unfocused", true,
 		"Pauses when the window loses focus. Optional, but audio will be glitchy when the game is running in the background.",
 	)
+	cmd.Flags().String("port1", string(DeviceGamepad), "Device plugged into controller port 1 (one of gamepad, zapper, arkanoid, power_pad)")
+	cmd.Flags().String("port2", string(DeviceGamepad), "Device plugged into controller port 2 (one of gamepad, zapper, arkanoid, power_pad)")
+	cmd.Flags().String("multitap", string(MultiTapNone), "Four player adapter (one of none, four_score, famicom)")
+	cmd.Flags().String("region", string(RegionAuto), "Console timing (one of auto, ntsc, pal, dendy)")
+	cmd.Flags().String("fds-bios", "", "Famicom Disk System BIOS (default is disksys.rom in the config directory)")
+	if err := cmd.RegisterFlagCompletionFunc(
+		"fds-bios",
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return []string{"rom"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+	); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool("cheats", true, "Apply enabled cheat codes from the config")
+	cmd.Flags().StringArray("cheat", nil, "Apply a Game Genie, ADDR:VALUE[:COMPARE], or Pro Action Replay code (repeatable)")
+	cmd.Flags().String("movie-record", "", "Record controller input to an FM2 movie file. Save data is cleared at power-on and is not saved while a movie is active")
+	cmd.Flags().String("movie-play", "", "Play back controller input from an FM2 movie file. Save data is cleared at power-on and is not saved while a movie is active")
+	cmd.Flags().Bool("movie-from-state", false, "Start the recorded movie from the resume state instead of power-on")
+	for _, name := range []string{"movie-record", "movie-play"} {
+		if err := cmd.RegisterFlagCompletionFunc(
+			name,
+			func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+				return []string{"fm2"}, cobra.ShellCompDirectiveFilterFileExt
+			},
+		); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func flagTable() map[string]string {
 	return map[string]string{
-		"debug":           "debug.enabled",
-		"trace":           "debug.trace",
-		"scale":           "ui.scale",
-		"fullscreen":      "ui.fullscreen",
-		"audio":           "audio.enabled",
-		"resume":          "state.resume",
-		"palette":         "ui.palette",
-		"pause-unfocused": "ui.pause_unfocused",
+		"debug":            "debug.enabled",
+		"trace":            "debug.trace",
+		"trace-file":       "debug.trace_file",
+		"trace-format":     "debug.trace_format",
+		"trace-range":      "debug.trace_range",
+		"trace-banks":      "debug.trace_banks",
+		"trace-start":      "debug.trace_start",
+		"trace-stop":       "debug.trace_stop",
+		"debugger":         "debug.debugger",
+		"gdb":              "debug.gdb",
+		"cdl":              "debug.cdl",
+		"viewer-scanline":  "debug.viewer_scanline",
+		"scale":            "ui.scale",
+		"fullscreen":       "ui.fullscreen",
+		"audio":            "audio.enabled",
+		"resume":           "state.resume",
+		"palette":          "ui.palette",
+		"pause-unfocused":  "ui.pause_unfocused",
+		"port1":            "input.port1.type",
+		"port2":            "input.port2.type",
+		"multitap":         "input.multitap",
+		"region":           "system.region",
+		"fds-bios":         "fds.bios",
+		"cheats":           "cheats.enabled",
+		"movie-record":     "movie.record",
+		"movie-play":       "movie.play",
+		"movie-from-state": "movie.from_state",
 	}
 }
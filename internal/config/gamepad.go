@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/controller/button"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Gamepad struct {
+	Index    int     `toml:"index"    comment:"Connected gamepad to use, in the order they were connected. 0 is the first gamepad. Set to -1 to disable."`
+	Deadzone float64 `toml:"deadzone" comment:"Axis values closer to the center than this are ignored (between 0 and 1)."`
+
+	A      []GamepadInput `toml:"a"`
+	B      []GamepadInput `toml:"b"`
+	Start  []GamepadInput `toml:"start"`
+	Select []GamepadInput `toml:"select"`
+	Up     []GamepadInput `toml:"up"`
+	Down   []GamepadInput `toml:"down"`
+	Left   []GamepadInput `toml:"left"`
+	Right  []GamepadInput `toml:"right"`
+
+	ATurbo []GamepadInput `toml:"a_turbo"`
+	BTurbo []GamepadInput `toml:"b_turbo"`
+}
+
+func (g Gamepad) GetMap() map[button.Button][]GamepadBinding {
+	return map[button.Button][]GamepadBinding{
+		button.A:      parseGamepadInputs(g.A),
+		button.B:      parseGamepadInputs(g.B),
+		button.Start:  parseGamepadInputs(g.Start),
+		button.Select: parseGamepadInputs(g.Select),
+		button.Up:     parseGamepadInputs(g.Up),
+		button.Down:   parseGamepadInputs(g.Down),
+		button.Left:   parseGamepadInputs(g.Left),
+		button.Right:  parseGamepadInputs(g.Right),
+	}
+}
+
+func (g Gamepad) GetTurboMap() map[button.Button][]GamepadBinding {
+	return map[button.Button][]GamepadBinding{
+		button.A: parseGamepadInputs(g.ATurbo),
+		button.B: parseGamepadInputs(g.BTurbo),
+	}
+}
+
+func parseGamepadInputs(inputs []GamepadInput) []GamepadBinding {
+	bindings := make([]GamepadBinding, 0, len(inputs))
+	for _, input := range inputs {
+		// Inputs are validated when the config is unmarshalled
+		if binding, err := ParseGamepadInput(string(input)); err == nil {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings
+}
+
+// GamepadInput is a gamepad button or axis name.
+//
+// Standard layout buttons and axes are named like "RightBottom" or "LeftStickUp".
+// Raw indexes are named like "Button0", "Axis1+", or "Axis1-".
+type GamepadInput string
+
+func (g GamepadInput) MarshalText() ([]byte, error) {
+	return []byte(g), nil
+}
+
+func (g *GamepadInput) UnmarshalText(text []byte) error {
+	if _, err := ParseGamepadInput(string(text)); err != nil {
+		return err
+	}
+	*g = GamepadInput(text)
+	return nil
+}
+
+type GamepadInputType uint8
+
+const (
+	GamepadInputStandardButton GamepadInputType = iota
+	GamepadInputStandardAxis
+	GamepadInputButton
+	GamepadInputAxis
+)
+
+// GamepadBinding is a parsed [GamepadInput].
+type GamepadBinding struct {
+	Type GamepadInputType
+	// Index is an [ebiten.StandardGamepadButton], [ebiten.StandardGamepadAxis],
+	// [ebiten.GamepadButton], or raw axis index depending on Type.
+	Index int
+	// Negative is set when an axis binding is pressed by moving below the center.
+	Negative bool
+}
+
+var ErrInvalidGamepadInput = errors.New("invalid gamepad input")
+
+var standardGamepadButtons = map[string]ebiten.StandardGamepadButton{
+	"RightBottom":      ebiten.StandardGamepadButtonRightBottom,
+	"RightRight":       ebiten.StandardGamepadButtonRightRight,
+	"RightLeft":        ebiten.StandardGamepadButtonRightLeft,
+	"RightTop":         ebiten.StandardGamepadButtonRightTop,
+	"FrontTopLeft":     ebiten.StandardGamepadButtonFrontTopLeft,
+	"FrontTopRight":    ebiten.StandardGamepadButtonFrontTopRight,
+	"FrontBottomLeft":  ebiten.StandardGamepadButtonFrontBottomLeft,
+	"FrontBottomRight": ebiten.StandardGamepadButtonFrontBottomRight,
+	"CenterLeft":       ebiten.StandardGamepadButtonCenterLeft,
+	"CenterRight":      ebiten.StandardGamepadButtonCenterRight,
+	"LeftStick":        ebiten.StandardGamepadButtonLeftStick,
+	"RightStick":       ebiten.StandardGamepadButtonRightStick,
+	"LeftTop":          ebiten.StandardGamepadButtonLeftTop,
+	"LeftBottom":       ebiten.StandardGamepadButtonLeftBottom,
+	"LeftLeft":         ebiten.StandardGamepadButtonLeftLeft,
+	"LeftRight":        ebiten.StandardGamepadButtonLeftRight,
+	"CenterCenter":     ebiten.StandardGamepadButtonCenterCenter,
+}
+
+var standardGamepadAxes = map[string]GamepadBinding{
+	"LeftStickUp":     {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisLeftStickVertical), Negative: true},
+	"LeftStickDown":   {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisLeftStickVertical)},
+	"LeftStickLeft":   {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisLeftStickHorizontal), Negative: true},
+	"LeftStickRight":  {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisLeftStickHorizontal)},
+	"RightStickUp":    {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisRightStickVertical), Negative: true},
+	"RightStickDown":  {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisRightStickVertical)},
+	"RightStickLeft":  {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisRightStickHorizontal), Negative: true},
+	"RightStickRight": {Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisRightStickHorizontal)},
+}
+
+// ParseGamepadInput parses a standard layout button or axis name, or a raw button or axis index.
+func ParseGamepadInput(s string) (GamepadBinding, error) {
+	if b, ok := standardGamepadButtons[s]; ok {
+		return GamepadBinding{Type: GamepadInputStandardButton, Index: int(b)}, nil
+	}
+	if b, ok := standardGamepadAxes[s]; ok {
+		return b, nil
+	}
+
+	switch {
+	case strings.HasPrefix(s, "Button"):
+		i, err := strconv.Atoi(strings.TrimPrefix(s, "Button"))
+		if err != nil || i < 0 || i > int(ebiten.GamepadButtonMax) {
+			return GamepadBinding{}, fmt.Errorf("%w %q: button index must be between 0 and %d", ErrInvalidGamepadInput, s, ebiten.GamepadButtonMax)
+		}
+		return GamepadBinding{Type: GamepadInputButton, Index: i}, nil
+	case strings.HasPrefix(s, "Axis"):
+		axis := strings.TrimPrefix(s, "Axis")
+		var negative bool
+		switch {
+		case strings.HasSuffix(axis, "+"):
+		case strings.HasSuffix(axis, "-"):
+			negative = true
+		default:
+			return GamepadBinding{}, fmt.Errorf("%w %q: axis must end with + or -", ErrInvalidGamepadInput, s)
+		}
+		i, err := strconv.Atoi(axis[:len(axis)-1])
+		if err != nil || i < 0 {
+			return GamepadBinding{}, fmt.Errorf("%w %q: invalid axis index", ErrInvalidGamepadInput, s)
+		}
+		return GamepadBinding{Type: GamepadInputAxis, Index: i, Negative: negative}, nil
+	}
+	return GamepadBinding{}, fmt.Errorf("%w: %q", ErrInvalidGamepadInput, s)
+}
+
+// Pressed reports whether the binding is pressed on a gamepad.
+// Axis values closer to the center than deadzone are ignored.
+func (b GamepadBinding) Pressed(id ebiten.GamepadID, deadzone float64) bool {
+	var value float64
+	switch b.Type {
+	case GamepadInputStandardButton:
+		return ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButton(b.Index))
+	case GamepadInputButton:
+		return ebiten.IsGamepadButtonPressed(id, ebiten.GamepadButton(b.Index))
+	case GamepadInputStandardAxis:
+		value = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxis(b.Index))
+	case GamepadInputAxis:
+		value = ebiten.GamepadAxisValue(id, b.Index)
+	default:
+		return false
+	}
+	if b.Negative {
+		value = -value
+	}
+	return value > 0 && value >= deadzone
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGamepadInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    GamepadBinding
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"RightBottom", GamepadBinding{Type: GamepadInputStandardButton, Index: int(ebiten.StandardGamepadButtonRightBottom)}, require.NoError},
+		{"LeftStickUp", GamepadBinding{Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisLeftStickVertical), Negative: true}, require.NoError},
+		{"RightStickRight", GamepadBinding{Type: GamepadInputStandardAxis, Index: int(ebiten.StandardGamepadAxisRightStickHorizontal)}, require.NoError},
+		{"Button3", GamepadBinding{Type: GamepadInputButton, Index: 3}, require.NoError},
+		{"Axis1+", GamepadBinding{Type: GamepadInputAxis, Index: 1}, require.NoError},
+		{"Axis1-", GamepadBinding{Type: GamepadInputAxis, Index: 1, Negative: true}, require.NoError},
+		{"Button32", GamepadBinding{}, require.Error},
+		{"Axis1", GamepadBinding{}, require.Error},
+		{"Axis-", GamepadBinding{}, require.Error},
+		{"A", GamepadBinding{}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseGamepadInput(tt.input)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGamepadInput_UnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	var g GamepadInput
+	require.NoError(t, g.UnmarshalText([]byte("CenterRight")))
+	assert.Equal(t, GamepadInput("CenterRight"), g)
+
+	require.ErrorIs(t, g.UnmarshalText([]byte("Start")), ErrInvalidGamepadInput)
+	assert.Equal(t, GamepadInput("CenterRight"), g)
+}
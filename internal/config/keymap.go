@@ -17,6 +17,8 @@ type Keymap struct {
 
 	ATurbo Key `toml:"a_turbo" comment:"Key to press the A button repeatedly (must be held)."`
 	BTurbo Key `toml:"b_turbo" comment:"Key to press the B button repeatedly (must be held)."`
+
+	Gamepad Gamepad `toml:"gamepad" comment:"Gamepad bindings, used together with the keyboard. Each button accepts a list of inputs like RightBottom, LeftStickUp, Button0, or Axis1+."`
 }
 
 func (k Keymap) GetMap() map[button.Button]ebiten.Key {
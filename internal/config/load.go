@@ -21,7 +21,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func (conf *Config) Load(cmd *cobra.Command, name, hash string) error {
+// Load loads the main config, then game defaults, game overrides, and flags.
+// Game defaults are detected from the game, like the NES 2.0 default expansion device, and may be nil.
+func (conf *Config) Load(cmd *cobra.Command, name, hash string, defaults map[string]any) error {
 	log.Init(cmd.ErrOrStderr())
 
 	k := koanf.New(".")
@@ -48,6 +50,10 @@ func (conf *Config) Load(cmd *cobra.Command, name, hash string) error {
 		return err
 	}
 
+	if err := conf.loadGameDefaults(k, defaults); err != nil {
+		return err
+	}
+
 	if gameCfgFile != "" {
 		if err := conf.loadGameOverrides(k, gameCfgFile, name); err != nil {
 			return err
@@ -123,6 +129,25 @@ func (conf *Config) loadMainConfig(k *koanf.Koanf, path string) error {
 	return nil
 }
 
+func (conf *Config) loadGameDefaults(k *koanf.Koanf, defaults map[string]any) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	for key, value := range defaults {
+		if err := k.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := k.UnmarshalWithConf("", conf, koanf.UnmarshalConf{Tag: "toml"}); err != nil {
+		return err
+	}
+
+	slog.Info("Loaded game defaults", "values", defaults)
+	return nil
+}
+
 func (conf *Config) loadGameOverrides(k *koanf.Koanf, path, name string) error {
 	logger := slog.With("file", path)
 
@@ -173,7 +198,17 @@ func (conf *Config) loadFlags(k *koanf.Koanf, cmd *cobra.Command) error {
 		return err
 	}
 
-	return k.UnmarshalWithConf("", conf, koanf.UnmarshalConf{Tag: "toml"})
+	if err := k.UnmarshalWithConf("", conf, koanf.UnmarshalConf{Tag: "toml"}); err != nil {
+		return err
+	}
+
+	// Cheats from flags are added to the configured cheats
+	if cmd.Flags().Lookup("cheat") != nil {
+		for _, code := range must.Must2(cmd.Flags().GetStringArray("cheat")) {
+			conf.Cheats.Codes = append(conf.Cheats.Codes, Cheat{Code: code, Enabled: true})
+		}
+	}
+	return nil
 }
 
 func fixConfig(k *koanf.Koanf) error {
@@ -203,6 +238,14 @@ func fixConfig(k *koanf.Koanf) error {
 		}
 	}
 
+	// Rewind interval min
+	if val := k.Int("state.rewind_interval"); val < 1 {
+		slog.Warn("Rewind interval must be 1 or greater. Setting value to 1.")
+		if err := k.Set("state.rewind_interval", 1); err != nil {
+			return err
+		}
+	}
+
 	// Volume min/max
 	if val := k.Float64("audio.volume"); val < 0 {
 		slog.Warn("Minimum volume is 0. Setting to 0.")
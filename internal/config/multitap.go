@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MultiTap is a four player adapter.
+type MultiTap string
+
+const (
+	MultiTapNone MultiTap = "none"
+	// MultiTapFourScore is the NES Four Score, which chains players 3 and 4 after players 1 and 2.
+	MultiTapFourScore MultiTap = "four_score"
+	// MultiTapFamicom reads players 3 and 4 from Famicom expansion port controllers.
+	MultiTapFamicom MultiTap = "famicom"
+)
+
+var ErrInvalidMultiTap = errors.New("invalid multitap")
+
+func (m MultiTap) MarshalText() ([]byte, error) {
+	return []byte(m), nil
+}
+
+func (m *MultiTap) UnmarshalText(text []byte) error {
+	switch v := MultiTap(text); v {
+	case MultiTapNone, MultiTapFourScore, MultiTapFamicom:
+		*m = v
+	case "":
+		*m = MultiTapNone
+	default:
+		return fmt.Errorf("%w %q: expected %s, %s, or %s", ErrInvalidMultiTap, v, MultiTapNone, MultiTapFourScore, MultiTapFamicom)
+	}
+	return nil
+}
+
+// Players returns the number of controllers that are read.
+func (m MultiTap) Players() int {
+	switch m {
+	case MultiTapFourScore, MultiTapFamicom:
+		return 4
+	default:
+		return 2
+	}
+}
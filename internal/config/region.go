@@ -0,0 +1,35 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Region overrides the console timing.
+type Region string
+
+const (
+	// RegionAuto uses the region from the ROM's NES 2.0 header or the game database, falling back to NTSC.
+	RegionAuto  Region = "auto"
+	RegionNTSC  Region = "ntsc"
+	RegionPAL   Region = "pal"
+	RegionDendy Region = "dendy"
+)
+
+var ErrInvalidRegion = errors.New("invalid region")
+
+func (r Region) MarshalText() ([]byte, error) {
+	return []byte(r), nil
+}
+
+func (r *Region) UnmarshalText(text []byte) error {
+	switch v := Region(text); v {
+	case RegionAuto, RegionNTSC, RegionPAL, RegionDendy:
+		*r = v
+	case "":
+		*r = RegionAuto
+	default:
+		return fmt.Errorf("%w %q: expected %s, %s, %s, or %s", ErrInvalidRegion, v, RegionAuto, RegionNTSC, RegionPAL, RegionDendy)
+	}
+	return nil
+}
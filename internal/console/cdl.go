@@ -0,0 +1,68 @@
+//go:build !js
+
+package console
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gabe565.com/gones/internal/cdl"
+)
+
+// CDLPath returns the Code/Data Logger path, which is next to the SRAM.
+func (c *Console) CDLPath() (string, error) {
+	path, err := c.SRAMPath()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".cdl", nil
+}
+
+// startCDL enables the Code/Data Logger. Flags from an existing log are kept.
+func (c *Console) startCDL() error {
+	c.cdl = cdl.New(c.Cartridge, c.Mapper)
+	c.attachCDL()
+
+	path, err := c.CDLPath()
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	slog.Debug("Loading CDL from disk", "file", filepath.Base(path))
+	return c.cdl.Merge(b)
+}
+
+func (c *Console) SaveCDL() error {
+	if c.cdl == nil {
+		return nil
+	}
+
+	path, err := c.CDLPath()
+	if err != nil {
+		return err
+	}
+
+	prg, chr := c.cdl.Coverage()
+	slog.Debug("Writing CDL to disk", "file", filepath.Base(path), "prg", prg, "chr", chr)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.cdl.WriteTo(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o666)
+}
@@ -0,0 +1,9 @@
+package console
+
+func (c *Console) startCDL() error {
+	return nil
+}
+
+func (c *Console) SaveCDL() error {
+	return nil
+}
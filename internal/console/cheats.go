@@ -0,0 +1,80 @@
+package console
+
+import (
+	"errors"
+	"log/slog"
+
+	"gabe565.com/gones/internal/cheat"
+)
+
+// loadCheats parses the enabled cheats from the config.
+func (c *Console) loadCheats() error {
+	var cheats []cheat.Cheat
+	var errs []error
+	for _, code := range c.Config.Cheats.Codes {
+		if !code.Enabled {
+			continue
+		}
+		parsed, err := cheat.Parse(code.Code)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cheats = append(cheats, parsed)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if len(cheats) != 0 {
+		slog.Info("Enabled cheats", "count", len(cheats))
+		c.cheats = cheat.NewEngine(cheats)
+		c.attachCheats()
+	}
+	return nil
+}
+
+// AddCheat parses and enables a cheat code until the game is closed.
+func (c *Console) AddCheat(code string) (cheat.Cheat, error) {
+	parsed, err := cheat.Parse(code)
+	if err != nil {
+		return parsed, err
+	}
+
+	if c.cheats == nil {
+		c.cheats = cheat.NewEngine(nil)
+		c.attachCheats()
+	}
+	c.cheats.Add(parsed)
+	return parsed, nil
+}
+
+// SearchRegions returns the memory searched for cheats: CPU RAM and cartridge SRAM.
+// SRAM is read through the bus at $6000-$7FFF, so that results match the bank that the CPU sees.
+func (c *Console) SearchRegions() []cheat.Region {
+	var sram []byte
+	if len(c.Cartridge.SRAM) != 0 {
+		sram = make([]byte, 0x2000)
+		for i := range sram {
+			sram[i] = c.Bus.ReadMemSafe(0x6000 + uint16(i))
+		}
+	}
+	return []cheat.Region{
+		{Start: 0x0000, Data: c.Bus.CPUVRAM[:]},
+		{Start: 0x6000, Data: sram},
+	}
+}
+
+// attachCheats hooks ROM cheats into the current bus.
+func (c *Console) attachCheats() {
+	if c.cheats != nil {
+		c.Bus.SetPatcher(c.cheats)
+	}
+}
+
+// applyCheats writes RAM cheats. It is called at the start of each frame.
+func (c *Console) applyCheats() {
+	if c.cheats != nil {
+		c.cheats.Apply(c.Bus)
+	}
+}
@@ -1,8 +1,10 @@
 package console
 
 import (
+	"bytes"
 	"errors"
-	"fmt"
+	"image"
+	"io"
 	"log/slog"
 	"os"
 	"runtime"
@@ -11,11 +13,16 @@ import (
 	"gabe565.com/gones/internal/apu"
 	"gabe565.com/gones/internal/bus"
 	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/cdl"
+	"gabe565.com/gones/internal/cheat"
 	"gabe565.com/gones/internal/config"
 	"gabe565.com/gones/internal/consts"
 	"gabe565.com/gones/internal/cpu"
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/gdb"
 	"gabe565.com/gones/internal/ppu"
 	"gabe565.com/gones/internal/ppu/palette"
+	"gabe565.com/gones/internal/tracer"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 )
@@ -44,39 +51,77 @@ type Console struct {
 	Cartridge *cartridge.Cartridge
 	Mapper    cartridge.Mapper
 
+	// Region is the console timing.
+	Region consts.Region `msgpack:"-"`
+	// PPUClock is the remainder of PPU cycles that have not run yet, as a fraction of a CPU cycle.
+	PPUClock uint
+
 	audioCtx       *audio.Context
 	player         *audio.Player
 	actionOnUpdate UpdateAction
 	enableTrace    bool
+	tracer         *tracer.Logger
 	debug          Debug
+	debugger       *debugger.Debugger
+	debugCmds      <-chan string
+	debugOut       io.Writer
+	gdb            *gdb.Server
+	cdl            *cdl.Logger
+	cheats         *cheat.Engine
+	search         *cheat.Search
+	midFrame       bool
 
 	undoSaveStates [][]byte
 	undoLoadStates [][]byte
 
+	rewind           *rewindBuffer
+	rewindState      bytes.Buffer
+	rewindFrame      int
+	rewinding        bool
+	rewindAPUEnabled bool
+
+	movie *moviePlayer
+
 	autosave *time.Ticker
 	rate     uint8
 
 	willScreenshot bool
+	gamepadIDs     []ebiten.GamepadID
+
+	view      PPUView
+	viewImage *image.RGBA
+	viewLine  int
 }
 
 func New(conf *config.Config, cart *cartridge.Cartridge) (*Console, error) {
 	console := Console{
 		Config:    conf,
 		Cartridge: cart,
+		Region:    selectRegion(conf, cart),
 		rate:      1,
 
 		undoSaveStates: make([][]byte, 0, conf.State.UndoStateCount),
 		undoLoadStates: make([][]byte, 0, conf.State.UndoStateCount),
 	}
 
+	slog.Info("Selected region", "region", console.Region)
+
+	if cart.Disk != nil {
+		if err := console.loadBIOS(); err != nil {
+			return &console, err
+		}
+	}
+
 	var err error
 	console.Mapper, err = cartridge.NewMapper(cart)
 	if err != nil {
 		return &console, err
 	}
 
-	if err := console.LoadSRAM(); err != nil && !errors.Is(err, os.ErrNotExist) {
-		return &console, err
+	if !conf.Debug.SkipSaveData {
+		if err := console.LoadSRAM(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return &console, err
+		}
 	}
 
 	if err := palette.LoadPalFile(conf.UI.Palette); err != nil {
@@ -84,12 +129,21 @@ func New(conf *config.Config, cart *cartridge.Cartridge) (*Console, error) {
 	}
 
 	console.PPU = ppu.New(conf, console.Mapper)
+	console.PPU.SetRegion(console.Region)
 	console.APU = apu.New(conf)
+	console.APU.SetRegion(console.Region)
 	console.Bus = bus.New(conf, console.Mapper, console.PPU, console.APU)
 	console.CPU = cpu.New(console.Bus)
 
 	console.PPU.SetCPU(console.CPU)
 	console.APU.SetCPU(console.CPU)
+	console.attachExpansionAudio()
+
+	if conf.Cheats.Enabled {
+		if err := console.loadCheats(); err != nil {
+			return &console, err
+		}
+	}
 
 	if conf.Audio.Enabled {
 		console.audioCtx = audio.NewContext(consts.AudioSampleRate)
@@ -106,7 +160,7 @@ func New(conf *config.Config, cart *cartridge.Cartridge) (*Console, error) {
 		console.APU.Enabled = false
 	}
 
-	if conf.State.Resume {
+	if conf.State.Resume && !conf.Movie.Enabled() {
 		if err := console.LoadStateNum(AutoSaveNum); err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return &console, err
@@ -114,13 +168,39 @@ func New(conf *config.Config, cart *cartridge.Cartridge) (*Console, error) {
 		}
 	}
 
-	console.SetTrace(conf.Debug.Trace)
+	console.SetTrace(conf.Debug.Trace || conf.Debug.TraceFile != "")
 	console.SetDebug(conf.Debug.Enabled)
+	if runtime.GOOS != "js" {
+		if console.tracer, err = newTracer(conf.Debug); err != nil {
+			return &console, err
+		}
+		if conf.Debug.CDL {
+			if err := console.startCDL(); err != nil {
+				return &console, err
+			}
+		}
+		if conf.Debug.Debugger {
+			console.startDebugger()
+		}
+		if conf.Debug.GDB != "" {
+			if err := console.startGDB(conf.Debug.GDB); err != nil {
+				return &console, err
+			}
+		}
+	}
 
 	if duration := conf.State.AutosaveInterval; duration != 0 {
 		console.autosave = time.NewTicker(time.Duration(duration))
 	}
 
+	if conf.Movie.Enabled() {
+		if err := console.startMovie(); err != nil {
+			return &console, err
+		}
+	} else if size := conf.State.RewindSnapshots(console.Region); size > 0 {
+		console.rewind = newRewindBuffer(size)
+	}
+
 	return &console, nil
 }
 
@@ -129,6 +209,18 @@ func (c *Console) Close() error {
 	if c.autosave != nil {
 		c.autosave.Stop()
 	}
+	if c.gdb != nil {
+		errs = append(errs, c.gdb.Close())
+	}
+	if c.tracer != nil {
+		errs = append(errs, c.tracer.Close())
+	}
+	errs = append(errs, c.SaveCDL())
+	if c.movie != nil {
+		// Movies must not overwrite the player's own progress
+		errs = append(errs, c.saveMovie())
+		return errors.Join(errs...)
+	}
 	if c.Config.State.Resume {
 		errs = append(errs, c.SaveStateNum(AutoSaveNum, false))
 	}
@@ -136,23 +228,56 @@ func (c *Console) Close() error {
 	return errors.Join(errs...)
 }
 
+// selectRegion returns the region from the config, or the region that the cartridge was made for.
+func selectRegion(conf *config.Config, cart *cartridge.Cartridge) consts.Region {
+	switch conf.System.Region {
+	case config.RegionNTSC:
+		return consts.RegionNTSC
+	case config.RegionPAL:
+		return consts.RegionPAL
+	case config.RegionDendy:
+		return consts.RegionDendy
+	}
+
+	switch cart.Timing {
+	case cartridge.TimingPAL:
+		return consts.RegionPAL
+	case cartridge.TimingDendy:
+		return consts.RegionDendy
+	default:
+		return consts.RegionNTSC
+	}
+}
+
 func (c *Console) Step(render bool) {
-	if runtime.GOOS != "js" && c.enableTrace {
-		//nolint:forbidigo
-		fmt.Println(c.Trace())
+	if runtime.GOOS != "js" && c.enableTrace && c.tracer != nil {
+		c.logTrace()
 	}
 
 	var irq bool
 
+	if c.cdl != nil {
+		c.cdl.BeforeStep(c.CPU.ProgramCounter, c.Bus.ReadMemSafe(c.CPU.ProgramCounter))
+	}
 	cycles := c.CPU.Step()
+	if c.cdl != nil {
+		c.cdl.AfterStep(c.CPU.ProgramCounter)
+	}
 	if mapper, ok := c.Mapper.(cartridge.MapperOnCPUStep); ok {
 		mapper.OnCPUStep(cycles)
 	}
 
-	for range cycles * 3 {
+	ppuCycles, cpuCycles := c.Region.PPUCycles()
+	c.PPUClock += cycles * ppuCycles
+	for c.PPUClock >= cpuCycles {
+		c.PPUClock -= cpuCycles
 		c.PPU.Step(render)
 	}
 
+	if c.view != PPUViewNone {
+		c.stepViewer()
+	}
+
 	for range cycles {
 		irq = c.APU.Step() || irq
 	}
@@ -164,13 +289,89 @@ func (c *Console) Step(render bool) {
 	c.CPU.IRQPending = irq
 }
 
+// StepFrame runs the console until the next frame has been rendered.
+// Movie input is applied first. It is used when running without a window.
+func (c *Console) StepFrame() error {
+	c.stepMovie()
+	c.applyCheats()
+	c.PPU.RenderDone = false
+	for !c.PPU.RenderDone {
+		if c.Step(true); c.CPU.StepErr != nil {
+			return c.CPU.StepErr
+		}
+	}
+	return nil
+}
+
+// StepFrameUntil runs a frame like [Console.StepFrame], but checks stop after every instruction.
+// It returns true if stop was met, which can leave the frame unfinished. The next call continues that frame.
+func (c *Console) StepFrameUntil(stop func() bool) (bool, error) {
+	if !c.midFrame {
+		c.stepMovie()
+		c.applyCheats()
+		c.PPU.RenderDone = false
+	}
+	for !c.PPU.RenderDone {
+		if c.Step(true); c.CPU.StepErr != nil {
+			return false, c.CPU.StepErr
+		}
+		if stop() {
+			c.midFrame = !c.PPU.RenderDone
+			return true, nil
+		}
+	}
+	c.midFrame = false
+	return false, nil
+}
+
 func (c *Console) Reset() {
 	c.CPU.Reset()
 	c.PPU.Reset()
 	c.APU.Reset()
 }
 
+// PowerCycle emulates turning the console off and back on.
+// Unlike [Console.Reset], all memory and mapper state is cleared. Battery-backed SRAM is kept.
+func (c *Console) PowerCycle() error {
+	mapper, err := cartridge.NewMapper(c.Cartridge)
+	if err != nil {
+		return err
+	}
+	c.Mapper = mapper
+	c.Cartridge.Mirror = c.Cartridge.PowerOnMirror()
+
+	c.PPU = ppu.New(c.Config, c.Mapper)
+	c.PPU.SetRegion(c.Region)
+	c.PPUClock = 0
+	c.APU.Power()
+	c.Bus = bus.New(c.Config, c.Mapper, c.PPU, c.APU)
+	c.CPU = cpu.New(c.Bus)
+
+	c.PPU.SetCPU(c.CPU)
+	c.APU.SetCPU(c.CPU)
+	c.APU.Clear()
+	c.attachExpansionAudio()
+	c.attachDebugger()
+	c.attachCDL()
+	c.attachCheats()
+	c.resetRewind()
+	return nil
+}
+
+// attachExpansionAudio mixes the mapper's expansion audio with the APU.
+func (c *Console) attachExpansionAudio() {
+	if mapper, ok := c.Mapper.(cartridge.MapperAudio); ok {
+		c.APU.SetExpansions(mapper.ExpansionAudio())
+	} else {
+		c.APU.SetExpansions()
+	}
+}
+
 func (c *Console) Layout(_, _ int) (int, int) {
+	if c.viewImage != nil {
+		size := c.viewImage.Rect.Size()
+		return size.X, size.Y
+	}
 	return c.Width(), c.Height()
 }
 
@@ -191,35 +392,73 @@ func (c *Console) Update() error {
 		c.actionOnUpdate = ActionNone
 	}
 
+	c.processDebugCommands()
+	c.processGDBRequests()
 	c.CheckInput()
 
 	if runtime.GOOS != "js" && c.debug == DebugWait {
 		return nil
 	}
 
+	if c.rewinding {
+		if err := c.stepRewind(); err != nil {
+			slog.Error("Failed to rewind", "error", err)
+			c.SetRewinding(false)
+		}
+		return nil
+	}
+
+frames:
 	for i := range c.rate {
 		if c.rate != 1 {
 			c.PPU.RenderDone = false
 		}
+		if !c.midFrame {
+			c.stepMovie()
+			c.applyCheats()
+		}
 		for {
-			c.Step(i == c.rate-1)
+			render := i == c.rate-1
+			if c.debugger != nil {
+				if c.stepDebugger(render) {
+					c.midFrame = !c.PPU.RenderDone
+					break frames
+				}
+			} else {
+				c.Step(render)
+			}
 
 			if c.PPU.RenderDone || (runtime.GOOS != "js" && c.debug == DebugStepFrame) {
 				break
 			}
 		}
+		c.midFrame = !c.PPU.RenderDone
 	}
 
 	if runtime.GOOS != "js" && c.debug != DebugDisabled {
+		if c.debugger != nil && c.debug != DebugWait {
+			c.pauseDebugger("")
+		}
 		c.debug = DebugWait
+	} else {
+		c.captureRewind()
 	}
 
 	if c.autosave != nil {
 		select {
 		case <-c.autosave.C:
+			if c.movie != nil {
+				if err := c.saveMovie(); err != nil {
+					slog.Error("Movie auto-save failed", "error", err)
+				}
+				break
+			}
 			if err := c.SaveSRAM(); err != nil {
 				slog.Error("Auto-save failed", "error", err)
 			}
+			if err := c.SaveCDL(); err != nil {
+				slog.Error("CDL auto-save failed", "error", err)
+			}
 			if c.Config.State.Resume {
 				if err := c.SaveStateNum(AutoSaveNum, false); err != nil {
 					slog.Error("State auto-save failed", "error", err)
@@ -233,6 +472,12 @@ func (c *Console) Update() error {
 }
 
 func (c *Console) Draw(screen *ebiten.Image) {
+	if c.view != PPUViewNone {
+		// Draw first so that screenshots capture the viewer
+		c.drawViewer(screen)
+		c.PPU.RenderDone = false
+	}
+
 	if runtime.GOOS != "js" && c.willScreenshot {
 		c.willScreenshot = false
 		if err := c.writeScreenshot(screen); err != nil {
@@ -240,10 +485,12 @@ func (c *Console) Draw(screen *ebiten.Image) {
 		}
 	}
 
-	if c.PPU.RenderDone {
+	if c.PPU.RenderDone || (c.Bus.Zapper() != nil && c.view == PPUViewNone) {
+		// The crosshair moves between frames, so the frame is redrawn underneath it
 		img := c.PPU.Image()
 		screen.WritePixels(img.Pix)
 		c.PPU.RenderDone = false
+		c.drawCrosshair(screen)
 	}
 }
 
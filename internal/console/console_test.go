@@ -0,0 +1,40 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsole_StepFrameUntil(t *testing.T) {
+	t.Parallel()
+
+	conf := config.NewDefault()
+	conf.Audio.Enabled = false
+	conf.Debug.SkipSaveData = true
+	conf.State.Resume = false
+
+	// A ROM of NOPs that starts at $8000
+	cart := cartridge.New()
+	cart.PRG = bytes.Repeat([]byte{0xEA}, 0x8000)
+	cart.PRG[0x7FFC], cart.PRG[0x7FFD] = 0x00, 0x80
+	cart.CHR = make([]byte, 0x2000)
+	c, err := New(conf, cart)
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x8000), c.CPU.ProgramCounter)
+
+	met, err := c.StepFrameUntil(func() bool { return c.CPU.ProgramCounter == 0x8010 })
+	require.NoError(t, err)
+	assert.True(t, met)
+	assert.Equal(t, uint16(0x8010), c.CPU.ProgramCounter)
+	assert.False(t, c.PPU.RenderDone)
+
+	met, err = c.StepFrameUntil(func() bool { return false })
+	require.NoError(t, err)
+	assert.False(t, met)
+	assert.True(t, c.PPU.RenderDone)
+}
@@ -0,0 +1,348 @@
+package console
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/bus"
+	"gabe565.com/gones/internal/cpu"
+	"gabe565.com/gones/internal/debugger"
+)
+
+const debuggerHelp = `Execution:
+  c, continue            Run until a breakpoint is hit
+  s, step [N]            Step N instructions (default 1)
+  n, next                Step over subroutine calls
+  o, out                 Run until the current subroutine returns
+  f, frame               Run until the next frame is rendered
+  p, pause               Pause execution
+Breakpoints:
+  b, break ADDR[-END]    Break when code at an address is executed
+  b, break nmi|irq       Break when an interrupt is serviced
+  w, watch [r|w] ADDR[-END]
+                         Break when an address is read, written, or both
+  l, list                List breakpoints
+  d, delete ID           Delete a breakpoint
+Inspection:
+  r, regs                Show CPU registers
+  dis, disasm [ADDR] [N] Disassemble N instructions (default PC, 10)
+  x, mem ADDR [N]        Dump N bytes of CPU memory (default 64)
+  t, trace               Toggle trace logging
+Cheats:
+  search                 Start a new search of RAM and SRAM
+  search =|!=|>|<        Keep addresses that are equal, not equal, greater, or less than the last search
+  search +N|-N           Keep addresses that changed by N (hex)
+  search value N         Keep addresses that equal N (hex)
+  search list [N]        List up to N candidates (default 20)
+  cheat CODE             Apply a Game Genie, ADDR:VALUE[:COMPARE], or Pro Action Replay code
+Other:
+  q, quit                Exit GoNES`
+
+var (
+	ErrUnknownCommand  = errors.New("unknown command")
+	ErrMissingArgument = errors.New("missing argument")
+	ErrNoBreakpoint    = errors.New("no breakpoint")
+)
+
+// startDebugger pauses the console and reads debugger commands from stdin.
+func (c *Console) startDebugger() {
+	c.debugOut = os.Stdout
+	c.initDebugger()
+
+	cmds := make(chan string)
+	c.debugCmds = cmds
+	go func() {
+		defer close(cmds)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			cmds <- scanner.Text()
+		}
+	}()
+
+	_, _ = fmt.Fprintln(c.debugOut, `GoNES debugger. Type "help" for a list of commands.`)
+	c.pauseDebugger("")
+}
+
+func (c *Console) initDebugger() {
+	if c.debugger != nil {
+		return
+	}
+	c.debugger = debugger.New()
+	if c.debugOut == nil {
+		c.debugOut = io.Discard
+	}
+	c.attachDebugger()
+}
+
+// attachDebugger hooks the debugger into the current CPU and bus.
+func (c *Console) attachDebugger() {
+	if c.debugger == nil {
+		return
+	}
+	c.attachWatchers()
+	c.CPU.OnInterrupt = c.debugger.OnInterrupt
+}
+
+// attachCDL hooks the Code/Data Logger into the current mapper, PPU, and bus.
+func (c *Console) attachCDL() {
+	if c.cdl == nil {
+		return
+	}
+	c.cdl.SetMapper(c.Mapper)
+	c.PPU.SetCHRWatcher(c.cdl)
+	c.attachWatchers()
+}
+
+// attachWatchers sets the bus watcher to the debugger and Code/Data Logger.
+func (c *Console) attachWatchers() {
+	var watchers bus.Watchers
+	if c.debugger != nil {
+		watchers = append(watchers, c.debugger)
+	}
+	if c.cdl != nil {
+		watchers = append(watchers, c.cdl)
+	}
+
+	switch len(watchers) {
+	case 0:
+		c.Bus.SetWatcher(nil)
+	case 1:
+		c.Bus.SetWatcher(watchers[0])
+	default:
+		c.Bus.SetWatcher(watchers)
+	}
+}
+
+// processDebugCommands runs any debugger commands that were entered since the last update.
+func (c *Console) processDebugCommands() {
+	for {
+		select {
+		case line, ok := <-c.debugCmds:
+			if !ok {
+				c.debugCmds = nil
+				return
+			}
+			paused := c.debug == DebugWait
+			if err := c.runDebugCommand(strings.Fields(line)); err != nil {
+				_, _ = fmt.Fprintln(c.debugOut, "Error:", err)
+			}
+			if paused && c.debug == DebugWait {
+				c.printDebugPrompt()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// stepDebugger runs a single CPU step while checking breakpoints.
+// It returns true if execution should pause.
+func (c *Console) stepDebugger(render bool) bool {
+	if reason, stop := c.debugger.BeforeStep(c.CPU.ProgramCounter); stop {
+		c.pauseDebugger(reason)
+		return true
+	}
+
+	op := c.Bus.ReadMemSafe(c.CPU.ProgramCounter)
+	c.Step(render)
+
+	if reason, stop := c.debugger.AfterStep(op, c.CPU.StackPointer); stop {
+		c.pauseDebugger(reason)
+		return true
+	}
+	return false
+}
+
+func (c *Console) pauseDebugger(reason string) {
+	c.debug = DebugWait
+	if reason != "" {
+		_, _ = fmt.Fprintln(c.debugOut, reason)
+	}
+	c.printDebugLocation()
+	c.printDebugPrompt()
+	if c.gdb != nil {
+		c.gdb.NotifyStop()
+	}
+}
+
+func (c *Console) printDebugLocation() {
+	line, _ := cpu.Disassemble(c.Bus, c.CPU.ProgramCounter)
+	_, _ = fmt.Fprintf(c.debugOut, "%s\n%s\n", line, c.debugRegisters())
+}
+
+func (c *Console) printDebugPrompt() {
+	_, _ = io.WriteString(c.debugOut, "(gones) ")
+}
+
+func (c *Console) debugRegisters() string {
+	return fmt.Sprintf(
+		"PC:%04X A:%02X X:%02X Y:%02X P:%02X SP:%02X PPU:%3d,%3d CYC:%d",
+		c.CPU.ProgramCounter,
+		c.CPU.Accumulator,
+		c.CPU.RegisterX,
+		c.CPU.RegisterY,
+		c.CPU.Status.Get(),
+		c.CPU.StackPointer,
+		c.PPU.Scanline,
+		c.PPU.Cycles,
+		c.CPU.GetCycles(),
+	)
+}
+
+//nolint:gocyclo,cyclop,funlen
+func (c *Console) runDebugCommand(fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	cmd, args := fields[0], fields[1:]
+	out := c.debugOut
+
+	switch cmd {
+	case "h", "help", "?":
+		_, _ = fmt.Fprintln(out, debuggerHelp)
+	case "c", "continue":
+		c.debugger.Continue()
+		c.debug = DebugDisabled
+	case "s", "step":
+		n := 1
+		if len(args) != 0 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil {
+				return err
+			}
+		}
+		c.debugger.StepInto(n)
+		c.debug = DebugDisabled
+	case "n", "next":
+		c.debugger.StepOver(c.CPU.ProgramCounter, c.Bus.ReadMemSafe(c.CPU.ProgramCounter))
+		c.debug = DebugDisabled
+	case "o", "out", "finish":
+		c.debugger.StepOut(c.CPU.StackPointer)
+		c.debug = DebugDisabled
+	case "f", "frame":
+		c.debugger.Continue()
+		c.debug = DebugRunRender
+	case "p", "pause":
+		if c.debug != DebugWait {
+			c.pauseDebugger("Paused")
+		}
+	case "b", "break":
+		if len(args) == 0 {
+			return fmt.Errorf("%w: address", ErrMissingArgument)
+		}
+		var b debugger.Breakpoint
+		switch strings.ToLower(args[0]) {
+		case "nmi":
+			b = c.debugger.Add(debugger.KindNMI, 0, 0)
+		case "irq":
+			b = c.debugger.Add(debugger.KindIRQ, 0, 0)
+		default:
+			start, end, err := debugger.ParseRange(args[0])
+			if err != nil {
+				return err
+			}
+			b = c.debugger.Add(debugger.KindExec, start, end)
+		}
+		_, _ = fmt.Fprintln(out, "Added breakpoint", b)
+	case "w", "watch":
+		kind := debugger.KindAccess
+		if len(args) > 1 {
+			switch strings.ToLower(args[0]) {
+			case "r":
+				kind = debugger.KindRead
+			case "w":
+				kind = debugger.KindWrite
+			case "rw":
+			default:
+				return fmt.Errorf("%w: watch mode %q", ErrUnknownCommand, args[0])
+			}
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("%w: address", ErrMissingArgument)
+		}
+		start, end, err := debugger.ParseRange(args[0])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out, "Added breakpoint", c.debugger.Add(kind, start, end))
+	case "l", "list":
+		breakpoints := c.debugger.Breakpoints()
+		if len(breakpoints) == 0 {
+			_, _ = fmt.Fprintln(out, "No breakpoints")
+		}
+		for _, b := range breakpoints {
+			_, _ = fmt.Fprintln(out, b)
+		}
+	case "d", "delete":
+		if len(args) == 0 {
+			return fmt.Errorf("%w: breakpoint ID", ErrMissingArgument)
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return err
+		}
+		if !c.debugger.Delete(id) {
+			return fmt.Errorf("%w #%d", ErrNoBreakpoint, id)
+		}
+	case "r", "regs":
+		_, _ = fmt.Fprintln(out, c.debugRegisters())
+	case "dis", "disasm":
+		addr, n := c.CPU.ProgramCounter, 10
+		if len(args) != 0 {
+			var err error
+			if addr, err = debugger.ParseAddress(args[0]); err != nil {
+				return err
+			}
+		}
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				return err
+			}
+		}
+		for range n {
+			line, size := cpu.Disassemble(c.Bus, addr)
+			_, _ = fmt.Fprintln(out, line)
+			addr += size
+		}
+	case "x", "mem":
+		if len(args) == 0 {
+			return fmt.Errorf("%w: address", ErrMissingArgument)
+		}
+		addr, err := debugger.ParseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		n := 64
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < n; i += 16 {
+			row := make([]byte, 0, 16)
+			for j := i; j < min(i+16, n); j++ {
+				row = append(row, c.Bus.ReadMemSafe(addr+uint16(j)))
+			}
+			_, _ = fmt.Fprintf(out, "%04X  % X\n", addr+uint16(i), row)
+		}
+	case "t", "trace":
+		c.enableTrace = !c.enableTrace
+		_, _ = fmt.Fprintln(out, "Trace logging:", c.enableTrace)
+	case "search":
+		return c.runSearchCommand(args)
+	case "cheat":
+		return c.runCheatCommand(args)
+	case "q", "quit":
+		c.SetUpdateAction(ActionExit)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, cmd)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package console
+
+import (
+	"fmt"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/movie"
+)
+
+// loadBIOS loads the FDS BIOS from the configured path.
+func (c *Console) loadBIOS() error {
+	path, err := c.Config.FDS.BIOSPath()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Cartridge.LoadBIOS(path); err != nil {
+		return fmt.Errorf("failed to load FDS BIOS: %w", err)
+	}
+	return nil
+}
+
+// switchDiskSide ejects the FDS disk and inserts the next side.
+func (c *Console) switchDiskSide() {
+	mapper, ok := c.Mapper.(*cartridge.MapperFDS)
+	if !ok {
+		return
+	}
+	if !c.queueMovieCommand(movie.CommandFDSSelect) {
+		mapper.SwitchSide()
+	}
+}
@@ -0,0 +1,103 @@
+package console
+
+import (
+	"log/slog"
+
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/gdb"
+)
+
+// startGDB listens for GDB remote connections.
+func (c *Console) startGDB(addr string) error {
+	server, err := gdb.Listen(addr)
+	if err != nil {
+		return err
+	}
+	c.gdb = server
+	c.initDebugger()
+
+	slog.Info("Listening for GDB connections", "address", server.Addr())
+	go func() {
+		if err := server.Serve(gdbTarget{c}); err != nil {
+			slog.Error("GDB server failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// processGDBRequests runs any pending GDB requests on the emulator goroutine.
+func (c *Console) processGDBRequests() {
+	if c.gdb == nil {
+		return
+	}
+	for {
+		select {
+		case fn := <-c.gdb.Requests():
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+// gdbTarget exposes the console to the GDB server.
+type gdbTarget struct {
+	c *Console
+}
+
+func (t gdbTarget) Registers() gdb.Registers {
+	cpu := t.c.CPU
+	return gdb.Registers{
+		A:  cpu.Accumulator,
+		X:  cpu.RegisterX,
+		Y:  cpu.RegisterY,
+		SP: cpu.StackPointer,
+		PC: cpu.ProgramCounter,
+		P:  cpu.Status.Get(),
+	}
+}
+
+func (t gdbTarget) SetRegisters(r gdb.Registers) {
+	cpu := t.c.CPU
+	cpu.Accumulator = r.A
+	cpu.RegisterX = r.X
+	cpu.RegisterY = r.Y
+	cpu.StackPointer = r.SP
+	cpu.ProgramCounter = r.PC
+	cpu.Status.Set(r.P)
+}
+
+func (t gdbTarget) ReadMem(addr uint16) byte {
+	return t.c.Bus.ReadMemSafe(addr)
+}
+
+func (t gdbTarget) WriteMem(addr uint16, data byte) bool {
+	return t.c.Bus.WriteMemSafe(addr, data)
+}
+
+func (t gdbTarget) AddBreakpoint(kind debugger.Kind, start, end uint16) {
+	t.c.debugger.Add(kind, start, end)
+}
+
+func (t gdbTarget) RemoveBreakpoint(kind debugger.Kind, start, end uint16) bool {
+	b, ok := t.c.debugger.Find(kind, start, end)
+	return ok && t.c.debugger.Delete(b.ID)
+}
+
+func (t gdbTarget) Continue() {
+	t.c.debugger.Continue()
+	t.c.debug = DebugDisabled
+}
+
+func (t gdbTarget) Step() {
+	t.c.debugger.StepInto(1)
+	t.c.debug = DebugDisabled
+}
+
+func (t gdbTarget) Halt() {
+	if t.c.debug == DebugWait {
+		t.c.gdb.NotifyStop()
+		return
+	}
+	t.c.pauseDebugger("Halted by GDB")
+}
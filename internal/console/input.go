@@ -5,29 +5,43 @@ import (
 	"runtime"
 
 	"gabe565.com/gones/internal/controller"
+	"gabe565.com/gones/internal/movie"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 func (c *Console) CheckInput() {
+	c.gamepadIDs = inpututil.AppendJustConnectedGamepadIDs(c.gamepadIDs[:0])
+	for _, id := range c.gamepadIDs {
+		slog.Info("Gamepad connected", "id", id, "name", ebiten.GamepadName(id), "standard", ebiten.IsStandardGamepadLayoutAvailable(id))
+	}
+
 	c.Bus.UpdateInput()
 
 	if duration := inpututil.KeyPressDuration(ebiten.Key(c.Config.Input.Reset)); duration != 0 {
-		if duration == c.Config.Input.ResetHoldFrames() {
-			c.Reset()
+		if duration == c.Config.Input.ResetHoldFrames(c.Region) {
+			if !c.queueMovieCommand(movie.CommandSoftReset) {
+				c.Reset()
+			}
 		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.FastForward)) {
-		if c.player != nil {
-			c.player.SetVolume(c.Config.Audio.Volume / 2)
+	if duration := inpututil.KeyPressDuration(ebiten.Key(c.Config.Input.PowerCycle)); duration != 0 {
+		if duration == c.Config.Input.ResetHoldFrames(c.Region) {
+			if !c.queueMovieCommand(movie.CommandPowerCycle) {
+				if err := c.PowerCycle(); err != nil {
+					slog.Error("Failed to power cycle", "error", err)
+				}
+			}
 		}
+	}
+
+	c.SetRewinding(ebiten.IsKeyPressed(ebiten.Key(c.Config.Input.Rewind)))
+
+	if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.FastForward)) {
 		c.SetRate(c.Config.Input.FastForwardRate)
 	} else if inpututil.IsKeyJustReleased(ebiten.Key(c.Config.Input.FastForward)) {
 		c.SetRate(1)
-		if c.player != nil {
-			c.player.SetVolume(c.Config.Audio.Volume)
-		}
 	}
 
 	if runtime.GOOS != "js" {
@@ -62,6 +76,14 @@ func (c *Console) CheckInput() {
 		if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.Screenshot)) {
 			c.willScreenshot = true
 		}
+
+		if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.PPUViewer)) {
+			c.CycleViewer()
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.FDSSwitchSide)) {
+		c.switchDiskSide()
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.Key(c.Config.Input.Fullscreen)) {
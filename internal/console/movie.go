@@ -0,0 +1,202 @@
+package console
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/controller/button"
+	"gabe565.com/gones/internal/movie"
+)
+
+var ErrMovieActive = errors.New("not available while a movie is active")
+
+type moviePlayer struct {
+	movie     *movie.Movie
+	path      string
+	recording bool
+	frame     int
+	command   movie.Command
+}
+
+// startMovie begins recording or playing back the movie in the config.
+func (c *Console) startMovie() error {
+	conf := c.Config.Movie
+	checksum := c.movieChecksum()
+
+	if conf.Play != "" {
+		f, err := os.Open(conf.Play)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		m, err := movie.Read(f)
+		if err != nil {
+			return err
+		}
+
+		logger := slog.With("file", filepath.Base(conf.Play), "frames", len(m.Frames))
+		if m.Header.ROMChecksum != "" && m.Header.ROMChecksum != checksum {
+			logger.Warn("Movie was recorded with a different ROM", "want", m.Header.ROMChecksum, "got", checksum)
+		}
+
+		if m.Header.FourScore && c.Bus.Players() != 4 {
+			logger.Warn("Movie was recorded with four players, but a multitap is not enabled")
+		}
+
+		if pal := c.Region == consts.RegionPAL; m.Header.PAL != pal {
+			logger.Warn("Movie was recorded with a different region", "pal", m.Header.PAL)
+		}
+
+		if m.Header.Savestate != nil {
+			if err := c.LoadState(bytes.NewReader(m.Header.Savestate)); err != nil {
+				return err
+			}
+		} else if err := c.powerOnMovie(); err != nil {
+			return err
+		}
+
+		logger.Info("Playing movie")
+		c.movie = &moviePlayer{movie: m, path: conf.Play}
+		return nil
+	}
+
+	m := movie.New(c.Cartridge.Name(), checksum)
+	m.Header.FourScore = c.Bus.Players() == 4
+	m.Header.PAL = c.Region == consts.RegionPAL
+	if conf.FromState {
+		if err := c.LoadStateNum(AutoSaveNum); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := c.SaveState(&buf); err != nil {
+			return err
+		}
+		m.Header.Savestate = buf.Bytes()
+	} else if err := c.powerOnMovie(); err != nil {
+		return err
+	}
+
+	slog.Info("Recording movie", "file", filepath.Base(conf.Record))
+	c.movie = &moviePlayer{movie: m, path: conf.Record, recording: true}
+	return nil
+}
+
+// powerOnMovie puts the console into a clean power-on state so that the movie is deterministic.
+func (c *Console) powerOnMovie() error {
+	clear(c.Cartridge.SRAM)
+	if c.Cartridge.Disk != nil {
+		c.Cartridge.Disk.Reset()
+	}
+	return c.PowerCycle()
+}
+
+// movieChecksum returns the FM2 ROM checksum, which covers PRG and CHR ROM.
+func (c *Console) movieChecksum() string {
+	h := md5.New() //nolint:gosec
+	_, _ = h.Write(c.Cartridge.PRG)
+	if !c.Cartridge.CHRIsRAM() {
+		_, _ = h.Write(c.Cartridge.CHR)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// stepMovie records or plays back the input for the next frame.
+func (c *Console) stepMovie() {
+	m := c.movie
+	if m == nil {
+		return
+	}
+
+	if m.recording {
+		frame := movie.Frame{Command: m.command}
+		m.command = 0
+		// Input is read before the command runs, since a power cycle replaces the controllers
+		for i := range c.Bus.Players() {
+			for btn, pressed := range c.Bus.Controller(i).Buttons() {
+				frame.Buttons[i].Set(button.Button(btn), pressed)
+			}
+		}
+		c.runMovieCommand(frame.Command)
+		c.setMovieButtons(frame)
+		m.movie.Frames = append(m.movie.Frames, frame)
+		return
+	}
+
+	switch {
+	case m.frame < len(m.movie.Frames):
+		frame := m.movie.Frames[m.frame]
+		c.runMovieCommand(frame.Command)
+		c.setMovieButtons(frame)
+	case m.frame == len(m.movie.Frames):
+		slog.Info("Movie playback finished")
+	}
+	m.frame++
+}
+
+// setMovieButtons sets each controller to a frame's input.
+func (c *Console) setMovieButtons(frame movie.Frame) {
+	for i, buttons := range frame.Buttons[:c.Bus.Players()] {
+		var state [8]bool
+		for btn := range state {
+			state[btn] = buttons.Pressed(button.Button(btn))
+		}
+		c.Bus.Controller(i).SetButtons(state)
+	}
+}
+
+func (c *Console) runMovieCommand(cmd movie.Command) {
+	if cmd&movie.CommandPowerCycle != 0 {
+		if err := c.PowerCycle(); err != nil {
+			slog.Error("Failed to power cycle", "error", err)
+		}
+	} else if cmd&movie.CommandSoftReset != 0 {
+		c.Reset()
+	}
+
+	if cmd&movie.CommandFDSSelect != 0 {
+		if mapper, ok := c.Mapper.(*cartridge.MapperFDS); ok {
+			mapper.SwitchSide()
+		}
+	}
+}
+
+// queueMovieCommand records a command to run at the start of the next frame.
+// It returns false if no movie is active and the caller should run the command itself.
+// During playback, commands come from the movie, so the request is ignored.
+func (c *Console) queueMovieCommand(cmd movie.Command) bool {
+	if c.movie == nil {
+		return false
+	}
+	if c.movie.recording {
+		c.movie.command |= cmd
+	}
+	return true
+}
+
+// saveMovie writes the movie file when recording.
+func (c *Console) saveMovie() error {
+	if c.movie == nil || !c.movie.recording {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.movie.path), 0o777); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := c.movie.movie.Write(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(c.movie.path, buf.Bytes(), 0o666)
+}
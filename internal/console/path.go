@@ -19,6 +19,17 @@ func (c *Console) SRAMPath() (string, error) {
 	return filepath.Join(sramDir, sramName), nil
 }
 
+// DiskPatchPath returns the path of the IPS patch that stores writes to an FDS disk.
+func (c *Console) DiskPatchPath() (string, error) {
+	sramDir, err := config.GetSRAMDir()
+	if err != nil {
+		return "", err
+	}
+
+	patchName := c.Cartridge.Hash() + ".ips"
+	return filepath.Join(sramDir, patchName), nil
+}
+
 func (c *Console) StatePath(num uint8) (string, error) {
 	statesDir, err := config.GetStatesDir()
 	if err != nil {
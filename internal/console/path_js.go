@@ -8,6 +8,11 @@ func (c *Console) SRAMPath() (string, error) {
 	return fmt.Sprintf("%s.sav", c.Cartridge.Hash()), nil
 }
 
+// DiskPatchPath returns the path of the IPS patch that stores writes to an FDS disk.
+func (c *Console) DiskPatchPath() (string, error) {
+	return fmt.Sprintf("%s.ips", c.Cartridge.Hash()), nil
+}
+
 func (c *Console) StatePath(num uint8) (string, error) {
 	return fmt.Sprintf("%s.%d.state.gz", c.Cartridge.Hash(), num), nil
 }
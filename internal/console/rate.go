@@ -7,5 +7,22 @@ import "gabe565.com/gones/internal/apu"
 func (c *Console) SetRate(rate uint8) {
 	c.rate = rate
 	c.APU.Clear()
-	c.APU.SampleRate = apu.DefaultSampleRate * float64(rate)
+	c.APU.SampleRate = apu.SampleRate(c.Region) * float64(rate)
+	c.updateVolume()
+}
+
+// updateVolume sets the player's volume for the current rate. Fast-forward plays at half volume,
+// and rewinding is muted.
+func (c *Console) updateVolume() {
+	if c.player == nil {
+		return
+	}
+	switch {
+	case c.rewinding:
+		c.player.SetVolume(0)
+	case c.rate != 1:
+		c.player.SetVolume(c.Config.Audio.Volume / 2)
+	default:
+		c.player.SetVolume(c.Config.Audio.Volume)
+	}
 }
@@ -2,11 +2,18 @@ package console
 
 func (c *Console) SetRate(rate uint8) {
 	c.rate = rate
-	if rate == 1 {
-		c.APU.Enabled = true
+	c.APU.Enabled = rate == 1
+	c.updateVolume()
+}
+
+// updateVolume mutes the player unless the game is running at normal speed.
+func (c *Console) updateVolume() {
+	if c.player == nil {
+		return
+	}
+	if c.rate == 1 && !c.rewinding {
 		c.player.SetVolume(1)
 	} else {
-		c.APU.Enabled = false
 		c.player.SetVolume(0)
 	}
 }
@@ -0,0 +1,182 @@
+package console
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"log/slog"
+)
+
+// rewindBuffer is a fixed-size ring of save states used to rewind gameplay.
+//
+// Only the newest state is kept in full. Every older state is stored as a
+// flate-compressed XOR against the state that followed it. Consecutive frames
+// differ very little, so most deltas compress down to a few hundred bytes.
+type rewindBuffer struct {
+	entries []rewindEntry
+	start   int
+	len     int
+
+	head []byte
+	buf  bytes.Buffer
+	fw   *flate.Writer
+}
+
+type rewindEntry struct {
+	delta []byte
+	size  int
+}
+
+// newRewindBuffer returns a buffer that holds up to size states, including the newest.
+func newRewindBuffer(size int) *rewindBuffer {
+	fw, _ := flate.NewWriter(nil, flate.BestSpeed)
+	return &rewindBuffer{
+		entries: make([]rewindEntry, max(size-1, 0)),
+		fw:      fw,
+	}
+}
+
+// Push stores a new state. The previous state is converted into a delta.
+func (r *rewindBuffer) Push(state []byte) error {
+	if r.head != nil && len(r.entries) != 0 {
+		delta := xorBytes(state, r.head)
+
+		r.buf.Reset()
+		r.fw.Reset(&r.buf)
+		if _, err := r.fw.Write(delta); err != nil {
+			return err
+		}
+		if err := r.fw.Close(); err != nil {
+			return err
+		}
+
+		entry := rewindEntry{
+			delta: bytes.Clone(r.buf.Bytes()),
+			size:  len(r.head),
+		}
+		if r.len == len(r.entries) {
+			r.entries[r.start] = entry
+			r.start = (r.start + 1) % len(r.entries)
+		} else {
+			r.entries[(r.start+r.len)%len(r.entries)] = entry
+			r.len++
+		}
+	}
+
+	r.head = bytes.Clone(state)
+	return nil
+}
+
+// Pop discards the newest state and returns the one before it, which becomes the newest.
+// It returns false once the oldest state is reached.
+func (r *rewindBuffer) Pop() ([]byte, bool, error) {
+	if r.head == nil || r.len == 0 {
+		return nil, false, nil
+	}
+
+	i := (r.start + r.len - 1) % len(r.entries)
+	entry := r.entries[i]
+	r.entries[i] = rewindEntry{}
+	r.len--
+
+	delta, err := io.ReadAll(flate.NewReader(bytes.NewReader(entry.delta)))
+	if err != nil {
+		return nil, false, err
+	}
+
+	prev := xorBytes(delta[:entry.size], r.head)
+	r.head = prev[:entry.size]
+	return r.head, true, nil
+}
+
+// Len returns the number of stored states.
+func (r *rewindBuffer) Len() int {
+	if r.head == nil {
+		return 0
+	}
+	return r.len + 1
+}
+
+// Reset discards all stored states.
+func (r *rewindBuffer) Reset() {
+	clear(r.entries)
+	r.start = 0
+	r.len = 0
+	r.head = nil
+}
+
+// xorBytes returns a XOR b. The shorter slice is treated as if it were zero-padded.
+func xorBytes(a, b []byte) []byte {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	result := bytes.Clone(a)
+	for i, v := range b {
+		result[i] ^= v
+	}
+	return result
+}
+
+func (c *Console) captureRewind() {
+	if c.rewind == nil {
+		return
+	}
+
+	c.rewindFrame += int(c.rate)
+	if c.rewindFrame < c.Config.State.RewindInterval {
+		return
+	}
+	c.rewindFrame = 0
+
+	c.rewindState.Reset()
+	if err := c.encodeState(&c.rewindState); err != nil {
+		slog.Error("Failed to capture rewind state", "error", err)
+		return
+	}
+	if err := c.rewind.Push(c.rewindState.Bytes()); err != nil {
+		slog.Error("Failed to capture rewind state", "error", err)
+	}
+}
+
+// resetRewind discards the rewind history so that rewinding can't cross a state load or power cycle.
+func (c *Console) resetRewind() {
+	if c.rewind != nil {
+		c.rewind.Reset()
+		c.rewindFrame = 0
+	}
+}
+
+func (c *Console) SetRewinding(v bool) {
+	if c.rewind == nil || c.rewinding == v {
+		return
+	}
+	c.rewinding = v
+
+	// Mute audio instead of playing it forwards over each rewound frame
+	if v {
+		c.rewindAPUEnabled = c.APU.Enabled
+		c.APU.Enabled = false
+	} else {
+		c.APU.Enabled = c.rewindAPUEnabled
+		c.rewindFrame = 0
+	}
+	c.updateVolume()
+}
+
+// stepRewind loads the previous rewind snapshot, then renders a single frame from it.
+func (c *Console) stepRewind() error {
+	state, ok, err := c.rewind.Pop()
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := c.decodeState(bytes.NewReader(state)); err != nil {
+		return err
+	}
+
+	c.PPU.RenderDone = false
+	for !c.PPU.RenderDone {
+		c.Step(true)
+	}
+	return nil
+}
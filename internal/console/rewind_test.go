@@ -0,0 +1,56 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rewindBuffer(t *testing.T) {
+	t.Parallel()
+
+	states := [][]byte{
+		[]byte("first state"),
+		[]byte("second state"),
+		[]byte("third"),
+		[]byte("fourth state is the longest"),
+		[]byte("fifth state"),
+	}
+
+	buf := newRewindBuffer(3)
+	for _, state := range states {
+		require.NoError(t, buf.Push(state))
+	}
+	assert.Equal(t, 3, buf.Len())
+
+	for i := len(states) - 2; i >= 2; i-- {
+		got, ok, err := buf.Pop()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, states[i], got)
+	}
+
+	got, ok, err := buf.Pop()
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, got)
+	assert.Equal(t, 1, buf.Len())
+}
+
+func Test_rewindBuffer_Reset(t *testing.T) {
+	t.Parallel()
+
+	buf := newRewindBuffer(2)
+	require.NoError(t, buf.Push([]byte("a")))
+	require.NoError(t, buf.Push([]byte("b")))
+	buf.Reset()
+	assert.Equal(t, 0, buf.Len())
+
+	require.NoError(t, buf.Push([]byte("c")))
+	require.NoError(t, buf.Push([]byte("d")))
+	got, ok, err := buf.Pop()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("c"), got)
+}
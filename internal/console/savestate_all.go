@@ -16,18 +16,21 @@ func (c *Console) SaveState(w io.Writer) error {
 		_ = gzw.Close()
 	}()
 
-	encoder := msgpack.NewEncoder(gzw)
-	encoder.UseCompactFloats(true)
-	encoder.UseCompactInts(true)
-	encoder.SetSortMapKeys(true)
-
-	if err := encoder.Encode(c); err != nil {
+	if err := c.encodeState(gzw); err != nil {
 		return err
 	}
 
 	return gzw.Close()
 }
 
+func (c *Console) encodeState(w io.Writer) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.UseCompactFloats(true)
+	encoder.UseCompactInts(true)
+	encoder.SetSortMapKeys(true)
+	return encoder.Encode(c)
+}
+
 func (c *Console) LoadState(r io.Reader) error {
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
@@ -37,7 +40,7 @@ func (c *Console) LoadState(r io.Reader) error {
 		_ = gzr.Close()
 	}()
 
-	if err := msgpack.NewDecoder(gzr).Decode(c); err != nil {
+	if err := c.decodeState(gzr); err != nil {
 		return err
 	}
 
@@ -45,11 +48,21 @@ func (c *Console) LoadState(r io.Reader) error {
 		return err
 	}
 
-	c.PPU.UpdatePalette(c.PPU.Mask.Get())
 	c.APU.Clear()
 	return nil
 }
 
+func (c *Console) decodeState(r io.Reader) error {
+	if err := msgpack.NewDecoder(r).Decode(c); err != nil {
+		return err
+	}
+
+	// Mappers can reference cartridge memory that was replaced by the decode
+	c.Mapper.SetCartridge(c.Cartridge)
+	c.PPU.UpdatePalette(c.PPU.Mask.Get())
+	return nil
+}
+
 var ErrNoPreviousState = errors.New("no previous state available")
 
 func (c *Console) CreateUndoSaveState(oldState []byte) error {
@@ -103,6 +116,9 @@ func (c *Console) CreateUndoLoadState() error {
 }
 
 func (c *Console) UndoLoadState() error {
+	if c.movie != nil {
+		return ErrMovieActive
+	}
 	if len(c.undoLoadStates) == 0 {
 		return ErrNoPreviousState
 	}
@@ -113,5 +129,6 @@ func (c *Console) UndoLoadState() error {
 	}
 
 	c.undoLoadStates = slices.Delete(c.undoLoadStates, len(c.undoLoadStates)-1, len(c.undoLoadStates))
+	c.resetRewind()
 	return nil
 }
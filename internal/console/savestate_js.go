@@ -42,6 +42,10 @@ func (c *Console) SaveStateNum(num uint8, createUndo bool) error {
 }
 
 func (c *Console) LoadStateNum(num uint8) error {
+	if c.movie != nil {
+		return ErrMovieActive
+	}
+
 	path, err := c.StatePath(num)
 	if err != nil {
 		return err
@@ -69,5 +73,6 @@ func (c *Console) LoadStateNum(num uint8) error {
 		return err
 	}
 
+	c.resetRewind()
 	return nil
 }
@@ -0,0 +1,89 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"gabe565.com/gones/internal/apu"
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsole_LoadState_N163(t *testing.T) {
+	t.Parallel()
+
+	conf := config.NewDefault()
+	conf.Audio.Enabled = false
+	conf.Debug.SkipSaveData = true
+	conf.State.Resume = false
+
+	newConsole := func() *Console {
+		cart := cartridge.New()
+		cart.Header.SetMapper(19)
+		cart.PRG = make([]byte, 0x8000)
+		cart.CHR = make([]byte, 0x2000)
+		c, err := New(conf, cart)
+		require.NoError(t, err)
+		return c
+	}
+
+	writeWavetable := func(c *Console, data byte) {
+		c.Bus.WriteMem(0xF800, 0x80)
+		for range apu.N163RAMSize {
+			c.Bus.WriteMem(0x4800, data)
+		}
+	}
+
+	c := newConsole()
+	writeWavetable(c, 0x12)
+	var state bytes.Buffer
+	require.NoError(t, c.SaveState(&state))
+
+	c = newConsole()
+	writeWavetable(c, 0x34)
+	require.NoError(t, c.LoadState(&state))
+
+	mapper, ok := c.Mapper.(*cartridge.Mapper19)
+	require.True(t, ok)
+	assert.Equal(t, bytes.Repeat([]byte{0x12}, apu.N163RAMSize), mapper.Audio.RAM)
+
+	// The wavetable must still be backed by SRAM
+	writeWavetable(c, 0x56)
+	assert.Equal(t, mapper.Audio.RAM, c.Cartridge.SRAM[len(c.Cartridge.SRAM)-apu.N163RAMSize:])
+	assert.Equal(t, byte(0x56), c.Cartridge.SRAM[len(c.Cartridge.SRAM)-1])
+}
+
+func TestConsole_UndoSaveState_KeepsRewind(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	conf := config.NewDefault()
+	conf.Audio.Enabled = false
+	conf.Debug.SkipSaveData = true
+	conf.State.Resume = false
+
+	cart := cartridge.New()
+	cart.PRG = make([]byte, 0x8000)
+	cart.CHR = make([]byte, 0x2000)
+	c, err := New(conf, cart)
+	require.NoError(t, err)
+	require.NotNil(t, c.rewind)
+
+	var state bytes.Buffer
+	require.NoError(t, c.SaveState(&state))
+	require.NoError(t, c.CreateUndoSaveState(state.Bytes()))
+
+	for range 3 {
+		c.rewindFrame = c.Config.State.RewindInterval
+		c.captureRewind()
+	}
+	require.Equal(t, 3, c.rewind.Len())
+
+	require.NoError(t, c.UndoSaveState())
+	assert.Equal(t, 3, c.rewind.Len())
+
+	require.NoError(t, c.CreateUndoLoadState())
+	require.NoError(t, c.UndoLoadState())
+	assert.Zero(t, c.rewind.Len())
+}
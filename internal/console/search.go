@@ -0,0 +1,100 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/cheat"
+)
+
+var (
+	ErrNoSearch     = errors.New(`no search in progress; run "search" first`)
+	ErrInvalidCount = errors.New("count must be greater than 0")
+)
+
+// runSearchCommand runs a cheat search debugger command.
+func (c *Console) runSearchCommand(args []string) error {
+	out := c.debugOut
+	if len(args) == 0 {
+		c.search = cheat.NewSearch(c.SearchRegions()...)
+		_, _ = fmt.Fprintf(out, "Started search with %d candidates\n", c.search.Len())
+		return nil
+	}
+
+	if c.search == nil {
+		return ErrNoSearch
+	}
+
+	var cmp cheat.Comparison
+	var value int
+	switch op := args[0]; op {
+	case "list":
+		n := 20
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				return err
+			}
+			if n <= 0 {
+				return fmt.Errorf("%w: %d", ErrInvalidCount, n)
+			}
+		}
+		results := c.search.Results()
+		for _, r := range results[:min(n, len(results))] {
+			_, _ = fmt.Fprintf(out, "$%04X = %02X (was %02X)\n", r.Address, r.Value, r.Previous)
+		}
+		if len(results) > n {
+			_, _ = fmt.Fprintf(out, "... %d more\n", len(results)-n)
+		}
+		return nil
+	case "=", "==", "eq":
+		cmp = cheat.Equal
+	case "!=", "ne":
+		cmp = cheat.NotEqual
+	case ">", "gt":
+		cmp = cheat.Greater
+	case "<", "lt":
+		cmp = cheat.Less
+	case "value":
+		if len(args) < 2 {
+			return fmt.Errorf("%w: value", ErrMissingArgument)
+		}
+		v, err := strconv.ParseUint(strings.TrimPrefix(args[1], "$"), 16, 8)
+		if err != nil {
+			return err
+		}
+		cmp, value = cheat.Value, int(v)
+	default:
+		if !strings.HasPrefix(op, "+") && !strings.HasPrefix(op, "-") {
+			return fmt.Errorf("%w: search %q", ErrUnknownCommand, op)
+		}
+		v, err := strconv.ParseInt(strings.Replace(op, "$", "", 1), 16, 16)
+		if err != nil {
+			return err
+		}
+		cmp, value = cheat.ChangedBy, int(v)
+	}
+
+	n, err := c.search.Filter(cmp, value, c.SearchRegions()...)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%d candidates\n", n)
+	return nil
+}
+
+// runCheatCommand enables a cheat code from the debugger.
+func (c *Console) runCheatCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: code", ErrMissingArgument)
+	}
+	parsed, err := c.AddCheat(args[0])
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.debugOut, "Enabled cheat %s. Add it to the game config to keep it:\n", parsed)
+	_, _ = fmt.Fprintf(c.debugOut, "[[cheats.codes]]\ncode = %q\nenabled = true\n", args[0])
+	return nil
+}
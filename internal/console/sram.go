@@ -10,6 +10,9 @@ import (
 )
 
 func (c *Console) SaveSRAM() error {
+	if c.Cartridge.Disk != nil {
+		return c.saveDisk()
+	}
 	if !c.Cartridge.Battery {
 		return nil
 	}
@@ -33,6 +36,9 @@ func (c *Console) SaveSRAM() error {
 }
 
 func (c *Console) LoadSRAM() error {
+	if c.Cartridge.Disk != nil {
+		return c.loadDisk()
+	}
 	if !c.Cartridge.Battery {
 		return nil
 	}
@@ -49,6 +55,52 @@ func (c *Console) LoadSRAM() error {
 
 	slog.Debug("Loading save from disk", "file", filepath.Base(path))
 
-	c.Cartridge.SRAM = sram
+	// Copy into the existing RAM, which is sized by the header
+	copy(c.Cartridge.SRAM, sram)
 	return nil
 }
+
+// saveDisk writes changes to the FDS disk as an IPS patch, leaving the disk image unmodified.
+func (c *Console) saveDisk() error {
+	if !c.Cartridge.Disk.Modified() {
+		return nil
+	}
+
+	path, err := c.DiskPatchPath()
+	if err != nil {
+		return err
+	}
+
+	diff, err := c.Cartridge.Disk.Diff()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Writing disk changes to disk", "file", filepath.Base(path))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return err
+	}
+
+	if err := os.Rename(path, path+".bak"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return os.WriteFile(path, diff, 0o666)
+}
+
+func (c *Console) loadDisk() error {
+	path, err := c.DiskPatchPath()
+	if err != nil {
+		return err
+	}
+
+	diff, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading disk changes from disk", "file", filepath.Base(path))
+
+	return c.Cartridge.Disk.Apply(diff)
+}
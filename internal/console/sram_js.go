@@ -8,6 +8,9 @@ import (
 )
 
 func (c *Console) SaveSRAM() error {
+	if c.Cartridge.Disk != nil {
+		return c.saveDisk()
+	}
 	if !c.Cartridge.Battery {
 		return nil
 	}
@@ -26,6 +29,9 @@ func (c *Console) SaveSRAM() error {
 }
 
 func (c *Console) LoadSRAM() error {
+	if c.Cartridge.Disk != nil {
+		return c.loadDisk()
+	}
 	if !c.Cartridge.Battery {
 		return nil
 	}
@@ -53,3 +59,53 @@ func (c *Console) LoadSRAM() error {
 
 	return nil
 }
+
+// saveDisk writes changes to the FDS disk as an IPS patch, leaving the disk image unmodified.
+func (c *Console) saveDisk() error {
+	if !c.Cartridge.Disk.Modified() {
+		return nil
+	}
+
+	path, err := c.DiskPatchPath()
+	if err != nil {
+		return err
+	}
+
+	diff, err := c.Cartridge.Disk.Diff()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Writing disk changes to db", "file", filepath.Base(path))
+
+	data := base64.StdEncoding.EncodeToString(diff)
+
+	_, err = await(js.Global().Get("GonesClient").Call("dbPut", "saves", path, data))
+	return err
+}
+
+func (c *Console) loadDisk() error {
+	path, err := c.DiskPatchPath()
+	if err != nil {
+		return err
+	}
+
+	vals, err := await(js.Global().Get("GonesClient").Call("dbGet", "saves", path))
+	if err != nil {
+		return err
+	}
+	data := vals[0]
+
+	if data.IsNull() {
+		return nil
+	}
+
+	slog.Info("Loading disk changes from db", "file", filepath.Base(path))
+
+	diff, err := base64.StdEncoding.DecodeString(data.String())
+	if err != nil {
+		return err
+	}
+
+	return c.Cartridge.Disk.Apply(diff)
+}
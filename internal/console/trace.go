@@ -1,19 +1,97 @@
 package console
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"runtime"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/tracer"
 )
 
 func (c *Console) Trace() string {
 	if runtime.GOOS == "js" {
 		return "DISABLED"
 	}
-	return fmt.Sprintf(
-		"%s PPU:%3d,%3d CYC:%d",
-		c.CPU.Trace(),
-		c.PPU.Scanline,
-		c.PPU.Cycles,
-		c.CPU.GetCycles(),
-	)
+	return c.traceState().Nestest()
+}
+
+func (c *Console) traceState() tracer.State {
+	return tracer.NewState(c.CPU, c.Bus, c.PPU.Scanline, c.PPU.Cycles, c.prgBank(c.CPU.ProgramCounter))
+}
+
+// prgBank returns the 16 KiB PRG ROM bank mapped to addr, or -1 if it is not mapped to PRG ROM.
+func (c *Console) prgBank(addr uint16) int {
+	if mapper, ok := c.Mapper.(cartridge.MapperPRGOffset); ok {
+		if offset, ok := mapper.PRGOffset(addr); ok {
+			return offset / consts.PRGChunkSize
+		}
+	}
+	return -1
+}
+
+var ErrInvalidTraceBank = errors.New("invalid trace bank")
+
+func newTracer(conf config.Debug) (*tracer.Logger, error) {
+	opts := tracer.Options{
+		Format: conf.TraceFormat,
+		End:    0xFFFF,
+	}
+
+	if conf.TraceRange != "" {
+		var err error
+		if opts.Start, opts.End, err = debugger.ParseRange(conf.TraceRange); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.TraceBanks != "" {
+		for _, s := range strings.Split(conf.TraceBanks, ",") {
+			bank, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidTraceBank, s, err)
+			}
+			opts.Banks = append(opts.Banks, bank)
+		}
+	}
+
+	if conf.TraceStart != "" {
+		cond, err := debugger.ParseCondition(conf.TraceStart)
+		if err != nil {
+			return nil, err
+		}
+		opts.StartOn = &cond
+	}
+
+	if conf.TraceStop != "" {
+		cond, err := debugger.ParseCondition(conf.TraceStop)
+		if err != nil {
+			return nil, err
+		}
+		opts.StopOn = &cond
+	}
+
+	if conf.TraceFile != "" {
+		return tracer.Create(conf.TraceFile, opts)
+	}
+	return tracer.New(os.Stdout, opts)
+}
+
+func (c *Console) logTrace() {
+	pc := c.CPU.ProgramCounter
+	if !c.tracer.Enabled(pc, c.prgBank(pc), c.Bus) {
+		return
+	}
+
+	if err := c.tracer.Log(c.traceState()); err != nil {
+		slog.Error("Failed to write trace log", "error", err)
+		c.enableTrace = false
+	}
 }
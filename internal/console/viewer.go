@@ -0,0 +1,69 @@
+package console
+
+import (
+	"image"
+	"log/slog"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:generate go tool stringer -type PPUView -trimprefix PPUView
+
+// PPUView is a debug view of the PPU state which replaces the game screen.
+type PPUView uint8
+
+const (
+	PPUViewNone PPUView = iota
+	PPUViewPatternTables
+	PPUViewNametables
+	PPUViewSprites
+	PPUViewPalette
+)
+
+// CycleViewer switches to the next PPU view.
+func (c *Console) CycleViewer() {
+	c.view++
+	if c.view > PPUViewPalette {
+		c.view = PPUViewNone
+	}
+
+	if c.view == PPUViewNone {
+		c.viewImage = nil
+		slog.Info("Disable PPU viewer")
+		return
+	}
+	c.viewImage = c.ViewImage(c.view)
+	slog.Info("Enable PPU viewer", "view", c.view, "scanline", c.Config.Debug.ViewerScanline)
+}
+
+// ViewImage renders a PPU view from the current state.
+func (c *Console) ViewImage(view PPUView) *image.RGBA {
+	switch view {
+	case PPUViewPatternTables:
+		return c.PPU.PatternTablesImage(0)
+	case PPUViewNametables:
+		return c.PPU.NametablesImage()
+	case PPUViewSprites:
+		return c.PPU.SpritesImage()
+	case PPUViewPalette:
+		return c.PPU.PaletteImage()
+	default:
+		return nil
+	}
+}
+
+// stepViewer refreshes the active PPU view once the configured scanline is reached.
+func (c *Console) stepViewer() {
+	line := c.PPU.Scanline
+	if line == c.Config.Debug.ViewerScanline && c.viewLine != line {
+		c.viewImage = c.ViewImage(c.view)
+	}
+	c.viewLine = line
+}
+
+func (c *Console) drawViewer(screen *ebiten.Image) {
+	if c.viewImage == nil || screen.Bounds().Size() != c.viewImage.Rect.Size() {
+		return
+	}
+	screen.WritePixels(c.viewImage.Pix)
+}
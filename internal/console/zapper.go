@@ -0,0 +1,32 @@
+package console
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+//nolint:gochecknoglobals
+var crosshairColor = color.RGBA{R: 0xFF, A: 0xFF}
+
+// drawCrosshair draws a crosshair at the mouse cursor when a Zapper is plugged in.
+func (c *Console) drawCrosshair(screen *ebiten.Image) {
+	if c.Bus.Zapper() == nil {
+		return
+	}
+
+	x, y := ebiten.CursorPosition()
+	if !image.Pt(x, y).In(screen.Bounds()) {
+		return
+	}
+
+	// Offset to the center of the pixel, leaving a gap so the target is visible
+	cx, cy := float32(x)+0.5, float32(y)+0.5
+	const gap, size = 2, 6
+	vector.StrokeLine(screen, cx-size, cy, cx-gap, cy, 1, crosshairColor, false)
+	vector.StrokeLine(screen, cx+gap, cy, cx+size, cy, 1, crosshairColor, false)
+	vector.StrokeLine(screen, cx, cy-size, cx, cy-gap, 1, crosshairColor, false)
+	vector.StrokeLine(screen, cx, cy+gap, cx, cy+size, 1, crosshairColor, false)
+}
@@ -0,0 +1,91 @@
+package consts
+
+//go:generate go tool stringer -type Region -trimprefix Region
+
+// Region is the console timing that is emulated.
+//
+// See [Cycle reference chart].
+//
+// [Cycle reference chart]: https://www.nesdev.org/wiki/Cycle_reference_chart
+type Region uint8
+
+const (
+	RegionNTSC Region = iota
+	RegionPAL
+	// RegionDendy is a Famicom clone with PAL frame timing, but NTSC CPU/PPU ratio and APU tables.
+	RegionDendy
+)
+
+// CPUFrequency returns the CPU clock rate in Hz.
+func (r Region) CPUFrequency() float64 {
+	switch r {
+	case RegionPAL:
+		return 1662607
+	case RegionDendy:
+		return 1773448
+	default:
+		return CPUFrequency
+	}
+}
+
+// FrameRate returns the hardware frame rate.
+func (r Region) FrameRate() float64 {
+	switch r {
+	case RegionPAL, RegionDendy:
+		return 50.0069789081886
+	default:
+		return HardwareFrameRate
+	}
+}
+
+// TargetFrameRate returns the number of frames that are drawn each second.
+func (r Region) TargetFrameRate() int {
+	switch r {
+	case RegionPAL, RegionDendy:
+		return 50
+	default:
+		return TargetFrameRate
+	}
+}
+
+// PPUCycles returns the number of PPU cycles that run for a number of CPU cycles.
+// PAL runs 3.2 PPU cycles per CPU cycle, so the result is a fraction, returned as ppu/cpu.
+func (r Region) PPUCycles() (uint, uint) {
+	if r == RegionPAL {
+		return 16, 5
+	}
+	return 3, 1
+}
+
+// Scanlines returns the number of scanlines in a frame, including vblank and the pre-render line.
+func (r Region) Scanlines() int {
+	switch r {
+	case RegionPAL, RegionDendy:
+		return 312
+	default:
+		return 262
+	}
+}
+
+// VBlankScanline returns the scanline where vblank starts.
+// Dendy keeps rendering idle for 50 lines after the picture before vblank starts.
+func (r Region) VBlankScanline() int {
+	if r == RegionDendy {
+		return 291
+	}
+	return 241
+}
+
+// SkipsOddFrameCycle reports whether the PPU skips a cycle on odd frames when rendering is enabled.
+func (r Region) SkipsOddFrameCycle() bool {
+	return r == RegionNTSC
+}
+
+// FrameCounterRate returns the number of CPU cycles between APU frame counter steps.
+// Dendy uses the NTSC cycle counts.
+func (r Region) FrameCounterRate() float64 {
+	if r == RegionPAL {
+		return 8313
+	}
+	return CPUFrequency / 240.0
+}
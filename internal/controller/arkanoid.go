@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"image"
+
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The range of potentiometer values which Arkanoid accepts.
+const (
+	arkanoidMin = 0x62
+	arkanoidMax = 0xF2
+)
+
+// Arkanoid is the NES Arkanoid Vaus controller.
+// The potentiometer is reported inverted on D4, most significant bit first, and the button is reported on D3.
+//
+// See [Arkanoid controller].
+//
+// [Arkanoid controller]: https://www.nesdev.org/wiki/Arkanoid_controller
+type Arkanoid struct {
+	Position byte
+	Fire     bool
+
+	visible image.Rectangle
+	strobe  bool
+	latch   byte
+}
+
+func NewArkanoid(conf *config.Config) *Arkanoid {
+	return &Arkanoid{
+		Position: (arkanoidMin + arkanoidMax) / 2,
+		visible:  conf.UI.Overscan.Rect(),
+	}
+}
+
+func (a *Arkanoid) Write(data byte) {
+	a.strobe = data&1 == 1
+	if a.strobe {
+		a.latch = ^a.Position
+	}
+}
+
+func (a *Arkanoid) Read() byte {
+	if a.strobe {
+		a.latch = ^a.Position
+	}
+
+	value := a.latch >> 7 << 4
+	if !a.strobe {
+		a.latch <<= 1
+	}
+	if a.Fire {
+		value |= 0x08
+	}
+	return value
+}
+
+// UpdateInput maps the mouse cursor's horizontal position to the potentiometer.
+// The left button fires.
+func (a *Arkanoid) UpdateInput() {
+	pos, _ := cursorPosition(a.visible)
+	x := min(max(pos.X, 0), consts.Width-1)
+	a.Position = byte(arkanoidMin + x*(arkanoidMax-arkanoidMin)/(consts.Width-1))
+	a.Fire = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+}
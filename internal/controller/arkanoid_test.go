@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArkanoid_Read(t *testing.T) {
+	t.Parallel()
+
+	a := Arkanoid{Position: 0xA5, Fire: true}
+	a.Write(1)
+	a.Write(0)
+
+	var position byte
+	for range 8 {
+		value := a.Read()
+		assert.Equal(t, byte(0x08), value&0x08)
+		position = position<<1 | value>>4&1
+	}
+	assert.Equal(t, byte(0xA5), ^position)
+
+	// Reads after the potentiometer report 1 after inversion
+	assert.Equal(t, byte(0x08), a.Read())
+}
@@ -11,6 +11,8 @@ type Player string
 const (
 	Player1 Player = "player1"
 	Player2 Player = "player2"
+	Player3 Player = "player3"
+	Player4 Player = "player4"
 )
 
 func NewController(conf *config.Config, player Player) Controller {
@@ -52,24 +54,40 @@ func (j *Controller) Read() byte {
 		return 1
 	}
 
-	var value byte
-	if j.buttons[j.index] {
-		value = 1
-	}
-
+	value := j.bit(j.index)
 	if !j.strobe && j.index < 8 {
 		j.index++
 	}
 	return value
 }
 
+// bit returns 1 if the button at index is pressed.
+func (j *Controller) bit(index byte) byte {
+	if j.Enabled && j.buttons[index] {
+		return 1
+	}
+	return 0
+}
+
+// Buttons returns the current button state, indexed by [button.Button].
+func (j *Controller) Buttons() [8]bool {
+	return j.buttons
+}
+
+// SetButtons overrides the current button state, indexed by [button.Button].
+func (j *Controller) SetButtons(buttons [8]bool) {
+	j.buttons = buttons
+}
+
 func (j *Controller) UpdateInput() {
+	j.Keymap.Gamepad.Update()
+
 	var turboPressed bool
 	for button, key := range j.Keymap.Regular {
-		pressed := ebiten.IsKeyPressed(key)
+		pressed := ebiten.IsKeyPressed(key) || j.Keymap.Gamepad.Pressed(button)
 		if !pressed {
 			turboKey, ok := j.Keymap.Turbo[button]
-			if ok && ebiten.IsKeyPressed(turboKey) {
+			if (ok && ebiten.IsKeyPressed(turboKey)) || j.Keymap.Gamepad.TurboPressed(button) {
 				turboPressed = true
 				j.buttons[button] = j.turbo < j.turboDutyCycle/2
 				continue
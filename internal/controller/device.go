@@ -0,0 +1,31 @@
+package controller
+
+// Device is a peripheral plugged into a controller port.
+type Device interface {
+	// Write is called for each write to $4016. Bit 0 is the strobe.
+	Write(data byte)
+	// Read returns data lines D0-D4 for a read from the port.
+	Read() byte
+	// UpdateInput polls the host's input devices. It is called once per frame.
+	UpdateInput()
+}
+
+// Expansion reads two controllers from a Famicom port, with the second controller
+// on D1 like controllers plugged into the Famicom expansion port.
+type Expansion struct {
+	First, Second *Controller
+}
+
+func (e Expansion) Write(data byte) {
+	e.First.Write(data)
+	e.Second.Write(data)
+}
+
+func (e Expansion) Read() byte {
+	return e.First.Read() | e.Second.Read()<<1
+}
+
+func (e Expansion) UpdateInput() {
+	e.First.UpdateInput()
+	e.Second.UpdateInput()
+}
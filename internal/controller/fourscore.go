@@ -0,0 +1,71 @@
+package controller
+
+// FourScore is the NES Four Score adapter.
+//
+// Each port reports 8 buttons from the first controller, 8 buttons from the second controller,
+// then an 8-bit signature which games use to detect the adapter.
+//
+// See [Four Score].
+//
+// [Four Score]: https://www.nesdev.org/wiki/Four_player_adapters
+type FourScore struct {
+	strobe bool
+	index  [2]byte
+}
+
+// fourScoreSignature is the signature for each port, read most significant bit first.
+//
+//nolint:gochecknoglobals
+var fourScoreSignature = [2]byte{0x10, 0x20}
+
+func (f *FourScore) Write(data byte) {
+	f.strobe = data&1 == 1
+	if f.strobe {
+		f.index = [2]byte{}
+	}
+}
+
+// Read reads the next bit from a port (0 or 1).
+// The first controller is player 1 or 2, and the second controller is player 3 or 4.
+func (f *FourScore) Read(port int, first, second *Controller) byte {
+	index := f.index[port]
+	if !f.strobe && index < 24 {
+		f.index[port]++
+	}
+
+	switch {
+	case index < 8:
+		return first.bit(index)
+	case index < 16:
+		return second.bit(index - 8)
+	case index < 24:
+		return fourScoreSignature[port] >> (23 - index) & 1
+	default:
+		return 1
+	}
+}
+
+// Port returns the device for a port (0 or 1).
+// The first controller is player 1 or 2, and the second controller is player 3 or 4.
+func (f *FourScore) Port(port int, first, second *Controller) Device {
+	return fourScorePort{fourScore: f, port: port, first: first, second: second}
+}
+
+type fourScorePort struct {
+	fourScore     *FourScore
+	port          int
+	first, second *Controller
+}
+
+func (p fourScorePort) Write(data byte) {
+	p.fourScore.Write(data)
+}
+
+func (p fourScorePort) Read() byte {
+	return p.fourScore.Read(p.port, p.first, p.second)
+}
+
+func (p fourScorePort) UpdateInput() {
+	p.first.UpdateInput()
+	p.second.UpdateInput()
+}
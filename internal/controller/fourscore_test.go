@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"testing"
+
+	"gabe565.com/gones/internal/controller/button"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFourScore_Read(t *testing.T) {
+	t.Parallel()
+
+	controllers := [4]Controller{{Enabled: true}, {Enabled: true}, {Enabled: true}, {Enabled: true}}
+	controllers[0].buttons[button.A] = true
+	controllers[1].buttons[button.Start] = true
+	controllers[2].buttons[button.B] = true
+	controllers[3].buttons[button.Right] = true
+
+	var f FourScore
+	f.Write(1)
+	f.Write(0)
+
+	read := func(port int) []byte {
+		bits := make([]byte, 0, 26)
+		for range 26 {
+			bits = append(bits, f.Read(port, &controllers[port], &controllers[port+2]))
+		}
+		return bits
+	}
+
+	assert.Equal(t, []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // Player 1
+		0, 1, 0, 0, 0, 0, 0, 0, // Player 3
+		0, 0, 0, 1, 0, 0, 0, 0, // Signature
+		1, 1,
+	}, read(0))
+	assert.Equal(t, []byte{
+		0, 0, 0, 1, 0, 0, 0, 0, // Player 2
+		0, 0, 0, 0, 0, 0, 0, 1, // Player 4
+		0, 0, 1, 0, 0, 0, 0, 0, // Signature
+		1, 1,
+	}, read(1))
+
+	// Strobing repeatedly returns the A button
+	f.Write(1)
+	assert.Equal(t, byte(1), f.Read(0, &controllers[0], &controllers[2]))
+	assert.Equal(t, byte(1), f.Read(0, &controllers[0], &controllers[2]))
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"slices"
+
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/controller/button"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Gamepad struct {
+	Index    int
+	Deadzone float64
+	Regular  map[button.Button][]config.GamepadBinding
+	Turbo    map[button.Button][]config.GamepadBinding
+
+	ids       []ebiten.GamepadID
+	id        ebiten.GamepadID
+	connected bool
+}
+
+// Update finds the configured gamepad. It must be called once per frame so that gamepads can be hot-plugged.
+func (g *Gamepad) Update() {
+	g.connected = false
+	if g.Index < 0 {
+		return
+	}
+
+	// Gamepad IDs increase as gamepads are connected
+	g.ids = ebiten.AppendGamepadIDs(g.ids[:0])
+	if g.Index >= len(g.ids) {
+		return
+	}
+	slices.Sort(g.ids)
+	g.id = g.ids[g.Index]
+	g.connected = true
+}
+
+func (g *Gamepad) Pressed(b button.Button) bool {
+	return g.pressed(g.Regular[b])
+}
+
+func (g *Gamepad) TurboPressed(b button.Button) bool {
+	return g.pressed(g.Turbo[b])
+}
+
+func (g *Gamepad) pressed(bindings []config.GamepadBinding) bool {
+	if !g.connected {
+		return false
+	}
+	for _, binding := range bindings {
+		if binding.Pressed(g.id, g.Deadzone) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 type Keymap struct {
 	Regular map[button.Button]ebiten.Key
 	Turbo   map[button.Button]ebiten.Key
+	Gamepad Gamepad
 }
 
 func NewKeymap(conf *config.Config, player Player) Keymap {
@@ -18,6 +19,10 @@ func NewKeymap(conf *config.Config, player Player) Keymap {
 		keymap = conf.Input.Player1
 	case Player2:
 		keymap = conf.Input.Player2
+	case Player3:
+		keymap = conf.Input.Player3
+	case Player4:
+		keymap = conf.Input.Player4
 	default:
 		panic("invalid player: " + player)
 	}
@@ -25,6 +30,12 @@ func NewKeymap(conf *config.Config, player Player) Keymap {
 	return Keymap{
 		Regular: keymap.GetMap(),
 		Turbo:   keymap.GetTurboMap(),
+		Gamepad: Gamepad{
+			Index:    keymap.Gamepad.Index,
+			Deadzone: keymap.Gamepad.Deadzone,
+			Regular:  keymap.Gamepad.GetMap(),
+			Turbo:    keymap.Gamepad.GetTurboMap(),
+		},
 	}
 }
 
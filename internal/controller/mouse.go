@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// cursorPosition returns the mouse cursor in screen coordinates.
+// It returns false when the cursor is outside the visible area.
+func cursorPosition(visible image.Rectangle) (image.Point, bool) {
+	x, y := ebiten.CursorPosition()
+	pos := image.Pt(x, y).Add(visible.Min)
+	return pos, pos.In(visible)
+}
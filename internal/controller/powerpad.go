@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"gabe565.com/gones/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The order that Power Pad buttons are reported on D3 and D4. Buttons are numbered from 1.
+//
+//nolint:gochecknoglobals
+var (
+	powerPadD3 = [8]int{2, 1, 5, 9, 6, 10, 11, 7}
+	powerPadD4 = [4]int{4, 3, 12, 8}
+)
+
+// PowerPad is the NES Power Pad mat.
+//
+// See [Power Pad].
+//
+// [Power Pad]: https://www.nesdev.org/wiki/Power_Pad
+type PowerPad struct {
+	Keys [12]ebiten.Key
+	// Buttons is the state of buttons 1-12.
+	Buttons [12]bool
+
+	strobe bool
+	d3, d4 byte
+}
+
+func NewPowerPad(keys []config.Key) *PowerPad {
+	p := &PowerPad{}
+	for i := range p.Keys {
+		p.Keys[i] = -1
+		if i < len(keys) {
+			p.Keys[i] = ebiten.Key(keys[i])
+		}
+	}
+	return p
+}
+
+func (p *PowerPad) Write(data byte) {
+	p.strobe = data&1 == 1
+	if p.strobe {
+		p.latch()
+	}
+}
+
+func (p *PowerPad) Read() byte {
+	if p.strobe {
+		p.latch()
+	}
+
+	value := (p.d3&1)<<3 | (p.d4&1)<<4
+	if !p.strobe {
+		// Reads after the last button return 1
+		p.d3 = p.d3>>1 | 0x80
+		p.d4 = p.d4>>1 | 0x80
+	}
+	return value
+}
+
+func (p *PowerPad) latch() {
+	p.d3, p.d4 = 0, 0xF0
+	for i, b := range powerPadD3 {
+		if p.Buttons[b-1] {
+			p.d3 |= 1 << i
+		}
+	}
+	for i, b := range powerPadD4 {
+		if p.Buttons[b-1] {
+			p.d4 |= 1 << i
+		}
+	}
+}
+
+func (p *PowerPad) UpdateInput() {
+	for i, key := range p.Keys {
+		p.Buttons[i] = ebiten.IsKeyPressed(key)
+	}
+}
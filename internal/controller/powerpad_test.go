@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerPad_Read(t *testing.T) {
+	t.Parallel()
+
+	p := NewPowerPad(nil)
+	// Buttons 1, 6, and 12
+	p.Buttons[0] = true
+	p.Buttons[5] = true
+	p.Buttons[11] = true
+	p.Write(1)
+	p.Write(0)
+
+	var d3, d4 []byte
+	for range 9 {
+		value := p.Read()
+		d3 = append(d3, value>>3&1)
+		d4 = append(d4, value>>4&1)
+	}
+	// D3 reports buttons 2, 1, 5, 9, 6, 10, 11, 7
+	assert.Equal(t, []byte{0, 1, 0, 0, 1, 0, 0, 0, 1}, d3)
+	// D4 reports buttons 4, 3, 12, 8
+	assert.Equal(t, []byte{0, 0, 1, 0, 1, 1, 1, 1, 1}, d4)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"image"
+	"image/color"
+
+	"gabe565.com/gones/internal/config"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Screen is the PPU output sampled by the [Zapper].
+type Screen interface {
+	// BeamPosition returns the scanline and dot that the PPU is drawing.
+	BeamPosition() (int, int)
+	// Pixel returns the color drawn at a screen position during the current frame.
+	// It returns false when the position is not visible.
+	Pixel(x, y int) (color.RGBA, bool)
+}
+
+const (
+	// zapperRadius is the distance in pixels from the cursor that the Zapper can see.
+	zapperRadius = 2
+	// zapperDecay is the number of scanlines that a pixel stays lit after the beam passes.
+	zapperDecay = 20
+	// zapperBrightness is the minimum average of a pixel's RGB values that the Zapper detects.
+	zapperBrightness = 0x55
+)
+
+// Zapper is the NES Zapper light gun.
+//
+// See [Zapper].
+//
+// [Zapper]: https://www.nesdev.org/wiki/Zapper
+type Zapper struct {
+	// X and Y are the screen position that the Zapper is aimed at, or -1 when it is aimed off-screen.
+	X, Y    int
+	Trigger bool
+
+	screen  Screen
+	visible image.Rectangle
+}
+
+func NewZapper(conf *config.Config, screen Screen) *Zapper {
+	return &Zapper{
+		X:       -1,
+		Y:       -1,
+		screen:  screen,
+		visible: conf.UI.Overscan.Rect(),
+	}
+}
+
+func (z *Zapper) Write(byte) {}
+
+// Read returns the light sense bit (D3, cleared when light is detected) and the trigger bit (D4).
+func (z *Zapper) Read() byte {
+	var value byte
+	if !z.LightDetected() {
+		value |= 0x08
+	}
+	if z.Trigger {
+		value |= 0x10
+	}
+	return value
+}
+
+// UpdateInput aims the Zapper at the mouse cursor.
+// The left button fires at the cursor and the right button fires off-screen.
+func (z *Zapper) UpdateInput() {
+	pos, ok := cursorPosition(z.visible)
+	if !ok || ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		z.X, z.Y = -1, -1
+	} else {
+		z.X, z.Y = pos.X, pos.Y
+	}
+	z.Trigger = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
+}
+
+// LightDetected reports whether a bright pixel near the cursor was recently drawn.
+func (z *Zapper) LightDetected() bool {
+	if z.X < 0 || z.Y < 0 {
+		return false
+	}
+
+	scanline, dot := z.screen.BeamPosition()
+	for y := z.Y - zapperRadius; y <= z.Y+zapperRadius; y++ {
+		if y > scanline || scanline-y > zapperDecay {
+			continue
+		}
+		for x := z.X - zapperRadius; x <= z.X+zapperRadius; x++ {
+			// Dot 1 draws pixel 0
+			if y == scanline && x >= dot-1 {
+				continue
+			}
+			c, ok := z.screen.Pixel(x, y)
+			if ok && (int(c.R)+int(c.G)+int(c.B))/3 >= zapperBrightness {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubScreen struct {
+	img      *image.RGBA
+	scanline int
+	dot      int
+}
+
+func (s stubScreen) BeamPosition() (int, int) {
+	return s.scanline, s.dot
+}
+
+func (s stubScreen) Pixel(x, y int) (color.RGBA, bool) {
+	if !image.Pt(x, y).In(s.img.Rect) {
+		return color.RGBA{}, false
+	}
+	return s.img.RGBAAt(x, y), true
+}
+
+func TestZapper_Read(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewRGBA(image.Rect(0, 0, 256, 240))
+	img.SetRGBA(100, 50, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+
+	tests := []struct {
+		name     string
+		scanline int
+		dot      int
+		want     byte
+	}{
+		{"before beam", 49, 0, 0x08},
+		{"beam on pixel", 50, 101, 0x08},
+		{"beam passed", 50, 102, 0x00},
+		{"lit", 60, 0, 0x00},
+		{"decayed", 71, 0, 0x08},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			z := Zapper{X: 101, Y: 51, screen: stubScreen{img: img, scanline: tt.scanline, dot: tt.dot}}
+			assert.Equal(t, tt.want, z.Read())
+		})
+	}
+
+	t.Run("trigger", func(t *testing.T) {
+		t.Parallel()
+		z := Zapper{X: -1, Y: -1, Trigger: true, screen: stubScreen{img: img, scanline: 60}}
+		assert.Equal(t, byte(0x18), z.Read())
+	})
+
+	t.Run("dark", func(t *testing.T) {
+		t.Parallel()
+		z := Zapper{X: 20, Y: 20, screen: stubScreen{img: img, scanline: 30}}
+		assert.Equal(t, byte(0x08), z.Read())
+	})
+}
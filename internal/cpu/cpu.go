@@ -57,6 +57,9 @@ type CPU struct {
 	Stall uint16
 
 	StepErr error `msgpack:"-"`
+
+	// OnInterrupt is called with the vector address whenever an NMI or IRQ is serviced.
+	OnInterrupt func(vector uint16) `msgpack:"-"`
 }
 
 // Reset resets the CPU and sets ProgramCounter to the value of the [Reset] Vector.
@@ -72,6 +75,9 @@ func (c *CPU) nmi() {
 	sei(c, 0)
 	c.Cycles += 7
 	c.ProgramCounter = c.ReadMem16(interrupt.NMIVector)
+	if c.OnInterrupt != nil {
+		c.OnInterrupt(interrupt.NMIVector)
+	}
 	c.NMIPending = false
 }
 
@@ -81,6 +87,9 @@ func (c *CPU) irq() {
 	sei(c, 0)
 	c.Cycles += 7
 	c.ProgramCounter = c.ReadMem16(interrupt.IRQVector)
+	if c.OnInterrupt != nil {
+		c.OnInterrupt(interrupt.IRQVector)
+	}
 	c.IRQPending = false
 }
 
@@ -0,0 +1,71 @@
+package cpu
+
+import (
+	"fmt"
+	"strings"
+
+	"gabe565.com/gones/internal/memory"
+)
+
+// Disassemble decodes the instruction at addr without side effects.
+// It returns the formatted instruction and its length in bytes.
+//
+// Unlike [CPU.Trace], operands are shown as written and are not resolved against the current registers.
+func Disassemble(mem memory.ReadSafe, addr uint16) (string, uint16) {
+	code := mem.ReadMemSafe(addr)
+	op := opcodes[code]
+	if op == nil {
+		return fmt.Sprintf("%04X  %-8s  .db $%02X", addr, fmt.Sprintf("%02X", code), code), 1
+	}
+
+	hexDump := make([]byte, op.Len)
+	hexDump[0] = code
+	for i := uint16(1); i < uint16(op.Len); i++ {
+		hexDump[i] = mem.ReadMemSafe(addr + i)
+	}
+
+	var operand string
+	switch op.Len {
+	case 1:
+		if op.Mode == Accumulator {
+			operand = "A"
+		}
+	case 2:
+		arg := hexDump[1]
+		switch op.Mode {
+		case Immediate:
+			operand = fmt.Sprintf("#$%02X", arg)
+		case ZeroPage:
+			operand = fmt.Sprintf("$%02X", arg)
+		case ZeroPageX:
+			operand = fmt.Sprintf("$%02X,X", arg)
+		case ZeroPageY:
+			operand = fmt.Sprintf("$%02X,Y", arg)
+		case IndirectX:
+			operand = fmt.Sprintf("($%02X,X)", arg)
+		case IndirectY:
+			operand = fmt.Sprintf("($%02X),Y", arg)
+		default:
+			operand = fmt.Sprintf("$%04X", uint16(int8(arg))+addr+2)
+		}
+	case 3:
+		arg := uint16(hexDump[2])<<8 | uint16(hexDump[1])
+		switch op.Mode {
+		case Indirect:
+			operand = fmt.Sprintf("($%04X)", arg)
+		case AbsoluteX:
+			operand = fmt.Sprintf("$%04X,X", arg)
+		case AbsoluteY:
+			operand = fmt.Sprintf("$%04X,Y", arg)
+		default:
+			operand = fmt.Sprintf("$%04X", arg)
+		}
+	}
+
+	undocumented := ' '
+	if op.Undocumented {
+		undocumented = '*'
+	}
+	line := fmt.Sprintf("%04X  %-8s %c%3s %s", addr, fmt.Sprintf("% X", hexDump), undocumented, op.Name, operand)
+	return strings.TrimRight(line, " "), uint16(op.Len)
+}
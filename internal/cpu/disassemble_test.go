@@ -0,0 +1,38 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisassemble(t *testing.T) {
+	t.Parallel()
+
+	_, bus := stubCPUMockBus([]byte{
+		0xA2, 0x01, // LDX #$01
+		0x0A,             // ASL A
+		0xBD, 0x00, 0x02, // LDA $0200,X
+		0xD0, 0xF8, // BNE $8000
+		0x6C, 0xFC, 0xFF, // JMP ($FFFC)
+		0x02, // unsupported
+	})
+
+	tests := []struct {
+		addr    uint16
+		want    string
+		wantLen uint16
+	}{
+		{0x8000, "8000  A2 01     LDX #$01", 2},
+		{0x8002, "8002  0A        ASL A", 1},
+		{0x8003, "8003  BD 00 02  LDA $0200,X", 3},
+		{0x8006, "8006  D0 F8     BNE $8000", 2},
+		{0x8008, "8008  6C FC FF  JMP ($FFFC)", 3},
+		{0x800B, "800B  02        .db $02", 1},
+	}
+	for _, tt := range tests {
+		got, gotLen := Disassemble(bus, tt.addr)
+		assert.Equal(t, tt.want, got)
+		assert.Equal(t, tt.wantLen, gotLen)
+	}
+}
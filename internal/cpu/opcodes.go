@@ -39,6 +39,11 @@ func (o *OpCode) Code() int {
 	return slices.Index(opcodes, o)
 }
 
+// Lookup returns the opcode for a byte, or nil if it is not supported.
+func Lookup(code byte) *OpCode {
+	return opcodes[code]
+}
+
 // opcodes is a list of supported opcodes.
 //
 // See [6502 Instruction Reference].
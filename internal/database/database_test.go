@@ -32,3 +32,24 @@ func TestFindNameByHash(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPAL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Super Mario Bros. 3 (USA)", false},
+		{"Super Mario Bros. 3 (Europe)", true},
+		{"Super Mario Bros. 3 (Europe) (Rev 1)", true},
+		{"Tetris (Germany, Spain)", true},
+		{"Tetris (USA, Europe)", false},
+		{"Tetris", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsPAL(tt.name))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"slices"
+	"strings"
+)
+
+// palRegions are No-Intro regions that only sold PAL consoles.
+//
+//nolint:gochecknoglobals
+var palRegions = []string{
+	"Europe", "Australia", "France", "Germany", "Italy", "Netherlands",
+	"Scandinavia", "Spain", "Sweden", "United Kingdom", "UK",
+}
+
+// IsPAL reports whether a No-Intro game name is only released in PAL regions.
+// The region is the first parenthesized group, like "(Europe)" or "(Germany, Spain)".
+func IsPAL(name string) bool {
+	_, after, ok := strings.Cut(name, " (")
+	if !ok {
+		return false
+	}
+	group, _, ok := strings.Cut(after, ")")
+	if !ok {
+		return false
+	}
+
+	for region := range strings.SplitSeq(group, ", ") {
+		if !slices.Contains(palRegions, region) {
+			return false
+		}
+	}
+	return true
+}
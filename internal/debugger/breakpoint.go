@@ -0,0 +1,96 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the event that triggers a breakpoint.
+type Kind uint8
+
+const (
+	KindExec Kind = iota
+	KindRead
+	KindWrite
+	KindAccess
+	KindNMI
+	KindIRQ
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindExec:
+		return "exec"
+	case KindRead:
+		return "read"
+	case KindWrite:
+		return "write"
+	case KindAccess:
+		return "access"
+	case KindNMI:
+		return "nmi"
+	case KindIRQ:
+		return "irq"
+	default:
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Breakpoint pauses execution when its event occurs within an address range.
+// NMI and IRQ breakpoints ignore the range.
+type Breakpoint struct {
+	ID    int
+	Kind  Kind
+	Start uint16
+	End   uint16
+}
+
+func (b Breakpoint) String() string {
+	switch b.Kind {
+	case KindNMI, KindIRQ:
+		return fmt.Sprintf("#%d %s", b.ID, b.Kind)
+	}
+	if b.Start == b.End {
+		return fmt.Sprintf("#%d %s $%04X", b.ID, b.Kind, b.Start)
+	}
+	return fmt.Sprintf("#%d %s $%04X-$%04X", b.ID, b.Kind, b.Start, b.End)
+}
+
+func (b Breakpoint) contains(addr uint16) bool {
+	return b.Start <= addr && addr <= b.End
+}
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ParseAddress parses a hex address with an optional "$" or "0x" prefix.
+func ParseAddress(s string) (uint16, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(s, "$"), "0x")
+	v, err := strconv.ParseUint(trimmed, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAddress, s)
+	}
+	return uint16(v), nil
+}
+
+// ParseRange parses a single address or an inclusive START-END range.
+func ParseRange(s string) (uint16, uint16, error) {
+	startStr, endStr, ok := strings.Cut(s, "-")
+	start, err := ParseAddress(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		return start, start, nil
+	}
+
+	end, err := ParseAddress(endStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("%w range %q: end is before start", ErrInvalidAddress, s)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,74 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/memory"
+)
+
+// Condition compares a CPU memory address or the program counter to a value.
+type Condition struct {
+	PC      bool
+	Address uint16
+	Value   uint16
+	Not     bool
+}
+
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// ParseCondition parses ADDR=VALUE or ADDR!=VALUE in hex.
+// ADDR may be "PC" to compare the program counter to a 16-bit value.
+func ParseCondition(s string) (Condition, error) {
+	var cond Condition
+
+	addr, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return cond, fmt.Errorf("%w %q: expected ADDR=VALUE or ADDR!=VALUE", ErrInvalidCondition, s)
+	}
+	if trimmed, ok := strings.CutSuffix(addr, "!"); ok {
+		addr = trimmed
+		cond.Not = true
+	}
+
+	bitSize := 8
+	if strings.EqualFold(addr, "pc") {
+		cond.PC = true
+		bitSize = 16
+	} else {
+		a, err := strconv.ParseUint(strings.TrimPrefix(addr, "$"), 16, 16)
+		if err != nil {
+			return cond, fmt.Errorf("%w %q: %w", ErrInvalidCondition, s, err)
+		}
+		cond.Address = uint16(a)
+	}
+
+	v, err := strconv.ParseUint(strings.TrimPrefix(value, "$"), 16, bitSize)
+	if err != nil {
+		return cond, fmt.Errorf("%w %q: %w", ErrInvalidCondition, s, err)
+	}
+	cond.Value = uint16(v)
+
+	return cond, nil
+}
+
+// Met reports whether the condition is true for the given program counter and memory.
+func (c Condition) Met(pc uint16, mem memory.ReadSafe) bool {
+	if c.PC {
+		return (pc == c.Value) != c.Not
+	}
+	return (uint16(mem.ReadMemSafe(c.Address)) == c.Value) != c.Not
+}
+
+func (c Condition) String() string {
+	op := "="
+	if c.Not {
+		op = "!="
+	}
+	if c.PC {
+		return fmt.Sprintf("PC%s%04X", op, c.Value)
+	}
+	return fmt.Sprintf("%04X%s%02X", c.Address, op, c.Value)
+}
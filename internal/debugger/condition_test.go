@@ -0,0 +1,51 @@
+package debugger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Condition
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"equal", "6000=80", Condition{Address: 0x6000, Value: 0x80}, require.NoError},
+		{"not equal", "6000!=80", Condition{Address: 0x6000, Value: 0x80, Not: true}, require.NoError},
+		{"dollar prefix", "$00FF=$1", Condition{Address: 0xFF, Value: 1}, require.NoError},
+		{"pc", "pc=C000", Condition{PC: true, Value: 0xC000}, require.NoError},
+		{"missing value", "6000", Condition{}, require.Error},
+		{"invalid address", "G000=0", Condition{}, require.Error},
+		{"value overflow", "6000=100", Condition{Address: 0x6000}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCondition(tt.s)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type stubMemory [0x10000]byte
+
+func (m *stubMemory) ReadMemSafe(addr uint16) byte { return m[addr] }
+
+func TestCondition_Met(t *testing.T) {
+	t.Parallel()
+
+	var mem stubMemory
+	mem[0x10] = 0x42
+
+	assert.True(t, Condition{Address: 0x10, Value: 0x42}.Met(0, &mem))
+	assert.False(t, Condition{Address: 0x10, Value: 0x42, Not: true}.Met(0, &mem))
+	assert.True(t, Condition{PC: true, Value: 0xC000}.Met(0xC000, &mem))
+	assert.False(t, Condition{PC: true, Value: 0xC000}.Met(0xC001, &mem))
+}
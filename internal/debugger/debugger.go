@@ -0,0 +1,198 @@
+package debugger
+
+import (
+	"fmt"
+	"slices"
+
+	"gabe565.com/gones/internal/interrupt"
+)
+
+const (
+	opJSR = 0x20
+	opRTI = 0x40
+	opRTS = 0x60
+)
+
+type stepMode uint8
+
+const (
+	stepNone stepMode = iota
+	stepInto
+	stepOver
+	stepOut
+)
+
+// Debugger tracks breakpoints and stepping state.
+//
+// The console calls [Debugger.BeforeStep] and [Debugger.AfterStep] around every CPU step,
+// and registers the Debugger as the bus watcher so read and write breakpoints can trigger.
+type Debugger struct {
+	breakpoints []Breakpoint
+	nextID      int
+
+	hit      string
+	skipExec bool
+
+	mode   stepMode
+	count  int
+	target uint16
+	sp     byte
+}
+
+func New() *Debugger {
+	return &Debugger{nextID: 1}
+}
+
+// Add creates a breakpoint.
+func (d *Debugger) Add(kind Kind, start, end uint16) Breakpoint {
+	b := Breakpoint{ID: d.nextID, Kind: kind, Start: start, End: end}
+	d.nextID++
+	d.breakpoints = append(d.breakpoints, b)
+	return b
+}
+
+// Delete removes a breakpoint by ID. It returns false if the breakpoint does not exist.
+func (d *Debugger) Delete(id int) bool {
+	i := slices.IndexFunc(d.breakpoints, func(b Breakpoint) bool {
+		return b.ID == id
+	})
+	if i == -1 {
+		return false
+	}
+	d.breakpoints = slices.Delete(d.breakpoints, i, i+1)
+	return true
+}
+
+// Find returns the first breakpoint with a matching kind and range.
+func (d *Debugger) Find(kind Kind, start, end uint16) (Breakpoint, bool) {
+	i := slices.IndexFunc(d.breakpoints, func(b Breakpoint) bool {
+		return b.Kind == kind && b.Start == start && b.End == end
+	})
+	if i == -1 {
+		return Breakpoint{}, false
+	}
+	return d.breakpoints[i], true
+}
+
+// Breakpoints returns all breakpoints in creation order.
+func (d *Debugger) Breakpoints() []Breakpoint {
+	return slices.Clone(d.breakpoints)
+}
+
+func (d *Debugger) OnRead(addr uint16, data byte) {
+	d.onAccess(KindRead, addr, data)
+}
+
+func (d *Debugger) OnWrite(addr uint16, data byte) {
+	d.onAccess(KindWrite, addr, data)
+}
+
+func (d *Debugger) onAccess(kind Kind, addr uint16, data byte) {
+	if d.hit != "" {
+		return
+	}
+	for _, b := range d.breakpoints {
+		if (b.Kind == kind || b.Kind == KindAccess) && b.contains(addr) {
+			d.hit = fmt.Sprintf("Hit breakpoint %s (%s $%04X = $%02X)", b, kind, addr, data)
+			return
+		}
+	}
+}
+
+// OnInterrupt should be called whenever the CPU services an interrupt.
+func (d *Debugger) OnInterrupt(vector uint16) {
+	if d.hit != "" {
+		return
+	}
+	kind := KindIRQ
+	if vector == interrupt.NMIVector {
+		kind = KindNMI
+	}
+	for _, b := range d.breakpoints {
+		if b.Kind == kind {
+			d.hit = "Hit breakpoint " + b.String()
+			return
+		}
+	}
+}
+
+// Continue runs until a breakpoint is hit.
+func (d *Debugger) Continue() {
+	d.resume(stepNone)
+}
+
+// StepInto runs n instructions.
+func (d *Debugger) StepInto(n int) {
+	d.resume(stepInto)
+	d.count = n
+}
+
+// StepOver runs one instruction. If the instruction at pc is a subroutine call, the whole subroutine is run.
+func (d *Debugger) StepOver(pc uint16, op byte) {
+	if op != opJSR {
+		d.StepInto(1)
+		return
+	}
+	d.resume(stepOver)
+	d.target = pc + 3
+}
+
+// StepOut runs until the current subroutine or interrupt handler returns.
+func (d *Debugger) StepOut(sp byte) {
+	d.resume(stepOut)
+	d.sp = sp
+}
+
+func (d *Debugger) resume(mode stepMode) {
+	d.mode = mode
+	d.hit = ""
+	// Execution stopped at the current instruction, so its breakpoint must not fire again
+	d.skipExec = true
+}
+
+// BeforeStep reports whether execution should pause before the instruction at pc runs.
+func (d *Debugger) BeforeStep(pc uint16) (string, bool) {
+	if d.mode == stepOver && pc == d.target {
+		d.mode = stepNone
+		return "Stepped over", true
+	}
+
+	if d.skipExec {
+		d.skipExec = false
+		return "", false
+	}
+
+	for _, b := range d.breakpoints {
+		if b.Kind == KindExec && b.contains(pc) {
+			d.mode = stepNone
+			return "Hit breakpoint " + b.String(), true
+		}
+	}
+	return "", false
+}
+
+// AfterStep reports whether execution should pause after a CPU step.
+// op is the opcode that was at the program counter before the step, and sp is the new stack pointer.
+func (d *Debugger) AfterStep(op, sp byte) (string, bool) {
+	if d.hit != "" {
+		reason := d.hit
+		d.hit = ""
+		d.mode = stepNone
+		return reason, true
+	}
+
+	switch d.mode {
+	case stepInto:
+		d.count--
+		if d.count <= 0 {
+			d.mode = stepNone
+			return "", true
+		}
+	case stepOut:
+		if (op == opRTS || op == opRTI) && sp > d.sp {
+			d.mode = stepNone
+			return "Stepped out", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,122 @@
+package debugger
+
+import (
+	"testing"
+
+	"gabe565.com/gones/internal/interrupt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugger_Exec(t *testing.T) {
+	t.Parallel()
+
+	d := New()
+	b := d.Add(KindExec, 0xC000, 0xC000)
+	assert.Equal(t, 1, b.ID)
+
+	_, stop := d.BeforeStep(0x8000)
+	assert.False(t, stop)
+	reason, stop := d.BeforeStep(0xC000)
+	assert.True(t, stop)
+	assert.Equal(t, "Hit breakpoint #1 exec $C000", reason)
+
+	// Resuming must not immediately hit the same breakpoint
+	d.Continue()
+	_, stop = d.BeforeStep(0xC000)
+	assert.False(t, stop)
+	_, stop = d.BeforeStep(0xC000)
+	assert.True(t, stop)
+
+	require.True(t, d.Delete(b.ID))
+	assert.False(t, d.Delete(b.ID))
+	assert.Empty(t, d.Breakpoints())
+}
+
+func TestDebugger_Access(t *testing.T) {
+	t.Parallel()
+
+	d := New()
+	d.Add(KindWrite, 0x0200, 0x02FF)
+
+	d.OnRead(0x0250, 1)
+	_, stop := d.AfterStep(0xEA, 0xFD)
+	assert.False(t, stop)
+
+	d.OnWrite(0x0250, 1)
+	reason, stop := d.AfterStep(0xEA, 0xFD)
+	assert.True(t, stop)
+	assert.Equal(t, "Hit breakpoint #1 write $0200-$02FF (write $0250 = $01)", reason)
+}
+
+func TestDebugger_Interrupt(t *testing.T) {
+	t.Parallel()
+
+	d := New()
+	d.Add(KindNMI, 0, 0)
+
+	d.OnInterrupt(interrupt.IRQVector)
+	_, stop := d.AfterStep(0xEA, 0xFD)
+	assert.False(t, stop)
+
+	d.OnInterrupt(interrupt.NMIVector)
+	reason, stop := d.AfterStep(0xEA, 0xFD)
+	assert.True(t, stop)
+	assert.Equal(t, "Hit breakpoint #1 nmi", reason)
+}
+
+func TestDebugger_Step(t *testing.T) {
+	t.Parallel()
+
+	d := New()
+
+	d.StepInto(2)
+	_, stop := d.AfterStep(0xEA, 0xFD)
+	assert.False(t, stop)
+	_, stop = d.AfterStep(0xEA, 0xFD)
+	assert.True(t, stop)
+
+	d.StepOver(0x8000, opJSR)
+	_, stop = d.BeforeStep(0x8000)
+	assert.False(t, stop)
+	_, stop = d.BeforeStep(0x9000)
+	assert.False(t, stop)
+	reason, stop := d.BeforeStep(0x8003)
+	assert.True(t, stop)
+	assert.Equal(t, "Stepped over", reason)
+
+	d.StepOut(0xFB)
+	_, stop = d.AfterStep(opJSR, 0xF9)
+	assert.False(t, stop)
+	_, stop = d.AfterStep(opRTS, 0xFB)
+	assert.False(t, stop)
+	reason, stop = d.AfterStep(opRTS, 0xFD)
+	assert.True(t, stop)
+	assert.Equal(t, "Stepped out", reason)
+}
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s         string
+		wantStart uint16
+		wantEnd   uint16
+		wantErr   require.ErrorAssertionFunc
+	}{
+		{"C000", 0xC000, 0xC000, require.NoError},
+		{"$0200-$02FF", 0x0200, 0x02FF, require.NoError},
+		{"0x10", 0x10, 0x10, require.NoError},
+		{"0300-0200", 0, 0, require.Error},
+		{"nope", 0, 0, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+			start, end, err := ParseRange(tt.s)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gdb
+
+import (
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/debugger"
+)
+
+func encodeRegisters(r Registers) []byte {
+	return []byte{r.A, r.X, r.Y, r.SP, byte(r.PC), byte(r.PC >> 8), r.P}
+}
+
+func decodeRegisters(b []byte) Registers {
+	return Registers{
+		A:  b[0],
+		X:  b[1],
+		Y:  b[2],
+		SP: b[3],
+		PC: uint16(b[5])<<8 | uint16(b[4]),
+		P:  b[6],
+	}
+}
+
+// registerBytes returns a single register in target byte order.
+func registerBytes(r Registers, n int) []byte {
+	b := encodeRegisters(r)
+	switch {
+	case n < 4:
+		return b[n : n+1]
+	case n == 4:
+		return b[4:6]
+	default:
+		return b[6:7]
+	}
+}
+
+func setRegisterBytes(r *Registers, n int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	switch n {
+	case 0:
+		r.A = v[0]
+	case 1:
+		r.X = v[0]
+	case 2:
+		r.Y = v[0]
+	case 3:
+		r.SP = v[0]
+	case 4:
+		r.PC = uint16(v[0])
+		if len(v) > 1 {
+			r.PC |= uint16(v[1]) << 8
+		}
+	case 5:
+		r.P = v[0]
+	}
+}
+
+func parseHex(s string) (uint64, bool) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	return v, err == nil
+}
+
+// parseAddrLen parses an "addr,length" pair.
+func parseAddrLen(s string) (uint16, int, bool) {
+	addrStr, lenStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	addr, addrOK := parseHex(addrStr)
+	length, lenOK := parseHex(lenStr)
+	if !addrOK || !lenOK || addr > 0xFFFF || length > 0x10000 {
+		return 0, 0, false
+	}
+	return uint16(addr), int(length), true
+}
+
+// parseBreakpoint parses the "type,addr,kind" arguments of a Z or z packet into an inclusive address range.
+// Ranges that run past $FFFF are rejected.
+func parseBreakpoint(s string) (debugger.Kind, uint16, uint16, bool) {
+	typ, rest, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	addr, length, ok := parseAddrLen(rest)
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	var kind debugger.Kind
+	switch typ {
+	case "0", "1":
+		// The third field is the instruction size, not a range
+		return debugger.KindExec, addr, addr, true
+	case "2":
+		kind = debugger.KindWrite
+	case "3":
+		kind = debugger.KindRead
+	case "4":
+		kind = debugger.KindAccess
+	default:
+		return 0, 0, 0, false
+	}
+
+	end := int(addr) + max(length, 1) - 1
+	if end > 0xFFFF {
+		return 0, 0, 0, false
+	}
+	return kind, addr, uint16(end), true
+}
+
+// xferChunk returns part of a qXfer object.
+func xferChunk(data string, offset, length int) string {
+	if offset >= len(data) {
+		return "l"
+	}
+	end := offset + length
+	if end >= len(data) {
+		return "l" + data[offset:]
+	}
+	return "m" + data[offset:end]
+}
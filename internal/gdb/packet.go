@@ -0,0 +1,89 @@
+package gdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const interruptByte = 0x03
+
+var ErrChecksum = errors.New("packet checksum mismatch")
+
+// readPacket reads the next packet or interrupt request from the client.
+// Acknowledgements are skipped. An interrupt request is returned as a packet containing only 0x03.
+func readPacket(r *bufio.Reader) (string, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		switch b {
+		case interruptByte:
+			return string(rune(interruptByte)), nil
+		case '$':
+			return readPacketBody(r)
+		}
+	}
+}
+
+func readPacketBody(r *bufio.Reader) (string, error) {
+	data, err := r.ReadBytes('#')
+	if err != nil {
+		return "", err
+	}
+	data = data[:len(data)-1]
+
+	var sumHex [2]byte
+	if _, err := io.ReadFull(r, sumHex[:]); err != nil {
+		return "", err
+	}
+	want, err := strconv.ParseUint(string(sumHex[:]), 16, 8)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrChecksum, err)
+	}
+	if got := checksum(data); got != byte(want) {
+		return "", fmt.Errorf("%w: got %02x, want %02x", ErrChecksum, got, want)
+	}
+
+	return string(unescape(data)), nil
+}
+
+func unescape(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] == '}' && i+1 < len(data) {
+			i++
+			result = append(result, data[i]^0x20)
+		} else {
+			result = append(result, data[i])
+		}
+	}
+	return result
+}
+
+// writePacket frames and writes a packet.
+func writePacket(w io.Writer, data string) error {
+	escaped := make([]byte, 0, len(data))
+	for _, b := range []byte(data) {
+		switch b {
+		case '$', '#', '}', '*':
+			escaped = append(escaped, '}', b^0x20)
+		default:
+			escaped = append(escaped, b)
+		}
+	}
+	_, err := fmt.Fprintf(w, "$%s#%02x", escaped, checksum(escaped))
+	return err
+}
+
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return sum
+}
@@ -0,0 +1,362 @@
+package gdb
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Server implements a GDB Remote Serial Protocol stub for the 6502 core.
+//
+// See [GDB Remote Protocol].
+//
+// [GDB Remote Protocol]: https://sourceware.org/gdb/current/onlinedocs/gdb.html/Remote-Protocol.html
+type Server struct {
+	listener net.Listener
+	requests chan func()
+	stops    chan struct{}
+	closed   chan struct{}
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Listen creates a server that listens for TCP connections on addr.
+func Listen(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		listener: listener,
+		requests: make(chan func()),
+		stops:    make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}, nil
+}
+
+// Addr returns the listener's address.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Requests returns a channel of functions that must be run on the emulator goroutine.
+func (s *Server) Requests() <-chan func() {
+	return s.requests
+}
+
+// NotifyStop tells the connected client that the target has stopped.
+// It should be called whenever execution pauses.
+func (s *Server) NotifyStop() {
+	select {
+	case s.stops <- struct{}{}:
+	default:
+	}
+}
+
+// Serve accepts clients one at a time until the server is closed.
+func (s *Server) Serve(t Target) error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conn = conn
+		s.mu.Unlock()
+
+		logger := slog.With("remote", conn.RemoteAddr())
+		logger.Info("GDB client connected")
+		if err := s.handle(conn, t); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+			logger.Error("GDB connection failed", "error", err)
+		}
+		logger.Info("GDB client disconnected")
+		_ = conn.Close()
+
+		// Let the game keep running once the client is gone
+		s.do(t.Continue)
+	}
+}
+
+// Close stops listening and disconnects the current client.
+func (s *Server) Close() error {
+	close(s.closed)
+	err := s.listener.Close()
+	s.mu.Lock()
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// do runs fn on the emulator goroutine and waits for it to finish.
+func (s *Server) do(fn func()) {
+	done := make(chan struct{})
+	select {
+	case s.requests <- func() {
+		fn()
+		close(done)
+	}:
+	case <-s.closed:
+		return
+	}
+	select {
+	case <-done:
+	case <-s.closed:
+	}
+}
+
+type session struct {
+	server  *Server
+	target  Target
+	conn    net.Conn
+	noAck   bool
+	running bool
+	halted  bool
+}
+
+func (s *Server) handle(conn net.Conn, t Target) error {
+	sess := &session{server: s, target: t, conn: conn}
+
+	// The client expects the target to be stopped once it attaches
+	s.do(t.Halt)
+	sess.drainStops()
+
+	packets := make(chan string)
+	errs := make(chan error, 1)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		r := bufio.NewReader(conn)
+		for {
+			p, err := readPacket(r)
+			if err != nil {
+				if errors.Is(err, ErrChecksum) {
+					if _, err := conn.Write([]byte{'-'}); err == nil {
+						continue
+					}
+				}
+				errs <- err
+				return
+			}
+			select {
+			case packets <- p:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case p := <-packets:
+			if p != string(rune(interruptByte)) && !sess.noAck {
+				if _, err := conn.Write([]byte{'+'}); err != nil {
+					return err
+				}
+			}
+
+			reply, send, closeConn := sess.handlePacket(p)
+			if send {
+				if err := writePacket(conn, reply); err != nil {
+					return err
+				}
+			}
+			if closeConn {
+				return nil
+			}
+		case <-s.stops:
+			if !sess.running {
+				continue
+			}
+			sess.running = false
+			signal := "S05"
+			if sess.halted {
+				signal = "S02"
+				sess.halted = false
+			}
+			if err := writePacket(conn, signal); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (sess *session) drainStops() {
+	select {
+	case <-sess.server.stops:
+	default:
+	}
+}
+
+// handlePacket runs a single command.
+// It returns the reply, whether a reply should be sent, and whether the connection should be closed.
+//
+//nolint:gocyclo,cyclop,funlen
+func (sess *session) handlePacket(p string) (string, bool, bool) {
+	s, t := sess.server, sess.target
+
+	if p == string(rune(interruptByte)) {
+		if sess.running {
+			sess.halted = true
+			s.do(t.Halt)
+		}
+		return "", false, false
+	}
+
+	if p == "" {
+		return "", true, false
+	}
+
+	switch p[0] {
+	case '?':
+		return "S05", true, false
+	case 'g':
+		var regs Registers
+		s.do(func() { regs = t.Registers() })
+		return hex.EncodeToString(encodeRegisters(regs)), true, false
+	case 'G':
+		b, err := hex.DecodeString(p[1:])
+		if err != nil || len(b) != 7 {
+			return "E01", true, false
+		}
+		s.do(func() { t.SetRegisters(decodeRegisters(b)) })
+		return "OK", true, false
+	case 'p':
+		n, ok := parseHex(p[1:])
+		if !ok || n >= registerCount {
+			return "E01", true, false
+		}
+		var regs Registers
+		s.do(func() { regs = t.Registers() })
+		return hex.EncodeToString(registerBytes(regs, int(n))), true, false
+	case 'P':
+		nStr, vStr, ok := strings.Cut(p[1:], "=")
+		n, nOK := parseHex(nStr)
+		v, err := hex.DecodeString(vStr)
+		if !ok || !nOK || err != nil || n >= registerCount {
+			return "E01", true, false
+		}
+		s.do(func() {
+			regs := t.Registers()
+			setRegisterBytes(&regs, int(n), v)
+			t.SetRegisters(regs)
+		})
+		return "OK", true, false
+	case 'm':
+		addr, length, ok := parseAddrLen(p[1:])
+		if !ok {
+			return "E01", true, false
+		}
+		data := make([]byte, length)
+		s.do(func() {
+			for i := range data {
+				data[i] = t.ReadMem(addr + uint16(i))
+			}
+		})
+		return hex.EncodeToString(data), true, false
+	case 'M':
+		header, dataHex, ok := strings.Cut(p[1:], ":")
+		addr, length, lenOK := parseAddrLen(header)
+		data, err := hex.DecodeString(dataHex)
+		if !ok || !lenOK || err != nil || len(data) != length {
+			return "E01", true, false
+		}
+		var failed bool
+		s.do(func() {
+			for i, b := range data {
+				if !t.WriteMem(addr+uint16(i), b) {
+					failed = true
+				}
+			}
+		})
+		if failed {
+			return "E0E", true, false
+		}
+		return "OK", true, false
+	case 'c', 's':
+		if len(p) > 1 {
+			addr, ok := parseHex(p[1:])
+			if !ok {
+				return "E01", true, false
+			}
+			s.do(func() {
+				regs := t.Registers()
+				regs.PC = uint16(addr)
+				t.SetRegisters(regs)
+			})
+		}
+		sess.drainStops()
+		sess.running = true
+		if p[0] == 'c' {
+			s.do(t.Continue)
+		} else {
+			s.do(t.Step)
+		}
+		return "", false, false
+	case 'Z', 'z':
+		kind, addr, end, ok := parseBreakpoint(p[1:])
+		if !ok {
+			return "E01", true, false
+		}
+		if p[0] == 'Z' {
+			s.do(func() { t.AddBreakpoint(kind, addr, end) })
+			return "OK", true, false
+		}
+		var removed bool
+		s.do(func() { removed = t.RemoveBreakpoint(kind, addr, end) })
+		if !removed {
+			return "E01", true, false
+		}
+		return "OK", true, false
+	case 'D':
+		return "OK", true, true
+	case 'k':
+		return "", false, true
+	case 'H', 'T':
+		return "OK", true, false
+	case 'q':
+		return sess.handleQuery(p), true, false
+	case 'Q':
+		if p == "QStartNoAckMode" {
+			sess.noAck = true
+			return "OK", true, false
+		}
+	}
+	return "", true, false
+}
+
+func (sess *session) handleQuery(p string) string {
+	switch {
+	case strings.HasPrefix(p, "qSupported"):
+		return "PacketSize=4000;qXfer:features:read+;QStartNoAckMode+"
+	case p == "qAttached":
+		return "1"
+	case p == "qC":
+		return "QC1"
+	case p == "qfThreadInfo":
+		return "m1"
+	case p == "qsThreadInfo":
+		return "l"
+	case strings.HasPrefix(p, "qXfer:features:read:target.xml:"):
+		offset, length, ok := parseAddrLen(strings.TrimPrefix(p, "qXfer:features:read:target.xml:"))
+		if !ok {
+			return "E01"
+		}
+		return xferChunk(targetXML, int(offset), length)
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package gdb
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"gabe565.com/gones/internal/debugger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTarget struct {
+	server  *Server
+	regs    Registers
+	mem     [0x10000]byte
+	running bool
+	d       *debugger.Debugger
+}
+
+func (s *stubTarget) Registers() Registers     { return s.regs }
+func (s *stubTarget) SetRegisters(r Registers) { s.regs = r }
+func (s *stubTarget) ReadMem(addr uint16) byte { return s.mem[addr] }
+
+func (s *stubTarget) WriteMem(addr uint16, data byte) bool {
+	if addr >= 0x8000 {
+		return false
+	}
+	s.mem[addr] = data
+	return true
+}
+
+func (s *stubTarget) AddBreakpoint(kind debugger.Kind, start, end uint16) {
+	s.d.Add(kind, start, end)
+}
+
+func (s *stubTarget) RemoveBreakpoint(kind debugger.Kind, start, end uint16) bool {
+	b, ok := s.d.Find(kind, start, end)
+	return ok && s.d.Delete(b.ID)
+}
+
+func (s *stubTarget) Continue() { s.running = true }
+func (s *stubTarget) Step()     { s.regs.PC++; s.server.NotifyStop() }
+
+func (s *stubTarget) Halt() {
+	s.running = false
+	s.server.NotifyStop()
+}
+
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *client) send(data string) {
+	require.NoError(c.t, writePacket(c.conn, data))
+}
+
+func (c *client) recv() string {
+	require.NoError(c.t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	p, err := readPacket(c.r)
+	require.NoError(c.t, err)
+	return p
+}
+
+func (c *client) roundTrip(data string) string {
+	c.send(data)
+	return c.recv()
+}
+
+func newTestServer(t *testing.T) (*stubTarget, *client) {
+	server, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	target := &stubTarget{
+		server: server,
+		regs:   Registers{A: 1, X: 2, Y: 3, SP: 0xFD, PC: 0xC000, P: 0x24},
+		d:      debugger.New(),
+	}
+	go func() {
+		_ = server.Serve(target)
+	}()
+	go func() {
+		for {
+			select {
+			case fn := <-server.Requests():
+				fn()
+			case <-server.closed:
+				return
+			}
+		}
+	}()
+
+	conn, err := net.Dial("tcp", server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return target, &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	target, c := newTestServer(t)
+
+	assert.Equal(t, "OK", c.roundTrip("QStartNoAckMode"))
+	assert.Equal(t, "S05", c.roundTrip("?"))
+
+	t.Run("registers", func(t *testing.T) {
+		assert.Equal(t, "010203fd00c024", c.roundTrip("g"))
+		assert.Equal(t, "00c0", c.roundTrip("p4"))
+		assert.Equal(t, "OK", c.roundTrip("P0=ff"))
+		assert.Equal(t, "OK", c.roundTrip("P4=3412"))
+		assert.Equal(t, "ff0203fd341224", c.roundTrip("g"))
+		assert.Equal(t, "OK", c.roundTrip("G010203fd00c024"))
+		assert.Equal(t, Registers{A: 1, X: 2, Y: 3, SP: 0xFD, PC: 0xC000, P: 0x24}, target.regs)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		assert.Equal(t, "OK", c.roundTrip("M200,3:aabbcc"))
+		assert.Equal(t, "aabbcc00", c.roundTrip("m200,4"))
+		assert.Equal(t, "E0E", c.roundTrip("M8000,1:00"))
+	})
+
+	t.Run("breakpoints", func(t *testing.T) {
+		assert.Equal(t, "OK", c.roundTrip("Z0,c010,1"))
+		assert.Equal(t, "OK", c.roundTrip("Z2,200,2"))
+		assert.Equal(t, []debugger.Breakpoint{
+			{ID: 1, Kind: debugger.KindExec, Start: 0xC010, End: 0xC010},
+			{ID: 2, Kind: debugger.KindWrite, Start: 0x200, End: 0x201},
+		}, target.d.Breakpoints())
+		assert.Equal(t, "OK", c.roundTrip("z0,c010,1"))
+		assert.Equal(t, "E01", c.roundTrip("z0,c010,1"))
+		assert.Equal(t, "E01", c.roundTrip("Z2,fff0,20"), "range past $FFFF should be rejected")
+	})
+
+	t.Run("step", func(t *testing.T) {
+		assert.Equal(t, "S05", c.roundTrip("s"))
+		assert.Equal(t, uint16(0xC001), target.regs.PC)
+	})
+
+	t.Run("continue and halt", func(t *testing.T) {
+		c.send("c")
+		_, err := c.conn.Write([]byte{interruptByte})
+		require.NoError(t, err)
+		assert.Equal(t, "S02", c.recv())
+		assert.False(t, target.running)
+	})
+
+	t.Run("target description", func(t *testing.T) {
+		assert.Contains(t, c.roundTrip("qSupported:xmlRegisters=i386"), "qXfer:features:read+")
+		assert.Equal(t, "l"+targetXML, c.roundTrip("qXfer:features:read:target.xml:0,ffff"))
+	})
+}
+
+func Test_parseBreakpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s         string
+		wantKind  debugger.Kind
+		wantStart uint16
+		wantEnd   uint16
+		wantOK    bool
+	}{
+		{"0,c010,1", debugger.KindExec, 0xC010, 0xC010, true},
+		{"1,c010,3", debugger.KindExec, 0xC010, 0xC010, true},
+		{"2,200,2", debugger.KindWrite, 0x200, 0x201, true},
+		{"3,300,0", debugger.KindRead, 0x300, 0x300, true},
+		{"4,fff0,10", debugger.KindAccess, 0xFFF0, 0xFFFF, true},
+		{"2,fff0,20", 0, 0, 0, false},
+		{"2,0,10000", debugger.KindWrite, 0, 0xFFFF, true},
+		{"2,1,10000", 0, 0, 0, false},
+		{"5,200,1", 0, 0, 0, false},
+		{"2,200", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+			kind, start, end, ok := parseBreakpoint(tt.s)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package gdb
+
+import "gabe565.com/gones/internal/debugger"
+
+// Registers contains the 6502 registers in GDB register order.
+type Registers struct {
+	A  byte
+	X  byte
+	Y  byte
+	SP byte
+	PC uint16
+	P  byte
+}
+
+const registerCount = 6
+
+// Target is the emulator being debugged.
+//
+// Methods are only ever called from the goroutine that drains [Server.Requests],
+// so implementations do not need to be thread-safe.
+type Target interface {
+	Registers() Registers
+	SetRegisters(r Registers)
+
+	// ReadMem reads memory without side effects.
+	ReadMem(addr uint16) byte
+	// WriteMem writes memory without side effects. It returns false if the address is not writable.
+	WriteMem(addr uint16, data byte) bool
+
+	AddBreakpoint(kind debugger.Kind, start, end uint16)
+	RemoveBreakpoint(kind debugger.Kind, start, end uint16) bool
+
+	// Continue resumes execution until a breakpoint is hit.
+	Continue()
+	// Step runs a single instruction.
+	Step()
+	// Halt pauses execution.
+	Halt()
+}
+
+const targetXML = `<?xml version="1.0"?>
+<!DOCTYPE target SYSTEM "gdb-target.dtd">
+<target version="1.0">
+  <feature name="com.gabe565.gones.cpu">
+    <reg name="a" bitsize="8" regnum="0" type="uint8"/>
+    <reg name="x" bitsize="8" type="uint8"/>
+    <reg name="y" bitsize="8" type="uint8"/>
+    <reg name="sp" bitsize="8" type="uint8"/>
+    <reg name="pc" bitsize="16" type="code_ptr"/>
+    <reg name="p" bitsize="8" type="uint8"/>
+  </feature>
+</target>
+`
@@ -0,0 +1,102 @@
+package genie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	type args struct {
+		code string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Code
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"upper", args{"YEUZUGAA"}, Code{
+			Code:    "YEUZUGAA",
+			Address: 0xACB3,
+			Replace: 0x07,
+			Compare: 0x00,
+		}, require.NoError},
+		{"lower", args{"yeuzugaa"}, Code{
+			Code:    "YEUZUGAA",
+			Address: 0xACB3,
+			Replace: 0x07,
+			Compare: 0x00,
+		}, require.NoError},
+		{"valid YELZUGAA", args{"YELZUGAA"}, Code{
+			Code:    "YELZUGAA",
+			Address: 0xACB3,
+			Replace: 0x07,
+			Compare: 0x00,
+		}, require.NoError},
+		{"valid SXIOPO", args{"SXIOPO"}, Code{
+			Code:    "SXIOPO",
+			Address: 0x91D9,
+			Replace: 0xAD,
+			Compare: -1,
+		}, require.NoError},
+		{"valid SXSOPO", args{"SXSOPO"}, Code{
+			Code:    "SXSOPO",
+			Address: 0x91D9,
+			Replace: 0xAD,
+			Compare: -1,
+		}, require.NoError},
+		{"invalid len", args{"YEUZUGA"}, Code{Code: "YEUZUGA", Compare: -1}, require.Error},
+		{"invalid chars", args{"YEUZUGAF"}, Code{Code: "YEUZUGAF", Compare: -1}, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.args.code)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	type args struct {
+		address int
+		replace int
+		compare int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"valid YEUZUGAA", args{
+			address: 0xACB3,
+			replace: 0x07,
+			compare: 0x00,
+		}, "YEUZUGAA", require.NoError},
+		{"valid YEUZUGAA", args{
+			address: 0x2CB3,
+			replace: 0x07,
+			compare: 0x00,
+		}, "YEUZUGAA", require.NoError},
+		{"valid SXIOPO", args{
+			address: 0x91D9,
+			replace: 0xAD,
+			compare: -1,
+		}, "SXIOPO", require.NoError},
+		{"valid SXIOPO", args{
+			address: 0x11D9,
+			replace: 0xAD,
+			compare: -1,
+		}, "SXIOPO", require.NoError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.args.address, tt.args.replace, tt.args.compare)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package movie
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gabe565.com/gones/internal/controller/button"
+)
+
+//nolint:gochecknoglobals
+var fm2ButtonOrder = [8]struct {
+	button button.Button
+	char   byte
+}{
+	{button.Right, 'R'},
+	{button.Left, 'L'},
+	{button.Down, 'D'},
+	{button.Up, 'U'},
+	{button.Start, 'T'},
+	{button.Select, 'S'},
+	{button.B, 'B'},
+	{button.A, 'A'},
+}
+
+const (
+	fm2Base64Prefix  = "base64:"
+	fm2SavestateKey  = "gonesSavestate"
+	maxFM2LineLength = 32 * 1024 * 1024
+)
+
+var (
+	ErrInvalidFM2 = errors.New("invalid FM2 movie")
+	ErrBinaryFM2  = errors.New("binary FM2 movies are not supported")
+	// ErrFCEUXSavestate is returned for movies that begin from an FCEUX save state, which can't be loaded.
+	ErrFCEUXSavestate = errors.New("FM2 movies that start from an FCEUX save state are not supported")
+)
+
+// Read parses a movie in the FCEUX FM2 text format.
+func Read(r io.Reader) (*Movie, error) {
+	m := &Movie{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFM2LineLength)
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if line[0] == '|' {
+			frame, err := parseFM2Frame(line, m.Header.players())
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			m.Frames = append(m.Frames, frame)
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if err := m.Header.set(key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if m.Header.Version == 0 {
+		return nil, fmt.Errorf("%w: missing version", ErrInvalidFM2)
+	}
+	return m, nil
+}
+
+func (h *Header) set(key, value string) error {
+	var err error
+	switch key {
+	case "version":
+		h.Version, err = strconv.Atoi(value)
+	case "emuVersion":
+		h.EmuVersion, err = strconv.Atoi(value)
+	case "rerecordCount":
+		h.RerecordCount, err = strconv.Atoi(value)
+	case "palFlag":
+		h.PAL = value == "1"
+	case "NewPPU":
+		h.NewPPU = value == "1"
+	case "FDS":
+		h.FDS = value == "1"
+	case "fourscore":
+		h.FourScore = value == "1"
+	case "microphone":
+		h.Microphone = value == "1"
+	case "port0", "port1", "port2":
+		h.Ports[key[4]-'0'], err = strconv.Atoi(value)
+	case "romFilename":
+		h.ROMFilename = value
+	case "romChecksum":
+		h.ROMChecksum = value
+	case "guid":
+		h.GUID = value
+	case "comment":
+		h.Comments = append(h.Comments, value)
+	case "subtitle":
+		h.Subtitles = append(h.Subtitles, value)
+	case "binary":
+		if value == "1" {
+			return ErrBinaryFM2
+		}
+	case "savestate":
+		return ErrFCEUXSavestate
+	case fm2SavestateKey:
+		h.Savestate, err = decodeFM2Base64(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s: %w", ErrInvalidFM2, key, err)
+	}
+	return nil
+}
+
+func parseFM2Frame(line string, players int) (Frame, error) {
+	var frame Frame
+
+	fields := strings.Split(line[1:], "|")
+	if len(fields) < 2 {
+		return frame, fmt.Errorf("%w: malformed input line", ErrInvalidFM2)
+	}
+
+	command, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return frame, fmt.Errorf("%w: invalid command: %w", ErrInvalidFM2, err)
+	}
+	frame.Command = Command(command)
+
+	for i := range players {
+		if i+1 >= len(fields) {
+			break
+		}
+		frame.Buttons[i] = parseFM2Buttons(fields[i+1])
+	}
+	return frame, nil
+}
+
+func parseFM2Buttons(s string) Buttons {
+	var b Buttons
+	for i := range min(len(s), len(fm2ButtonOrder)) {
+		if s[i] != '.' && s[i] != ' ' {
+			b.Set(fm2ButtonOrder[i].button, true)
+		}
+	}
+	return b
+}
+
+func decodeFM2Base64(s string) ([]byte, error) {
+	if after, ok := strings.CutPrefix(s, fm2Base64Prefix); ok {
+		return base64.StdEncoding.DecodeString(after)
+	}
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// Write encodes the movie in the FCEUX FM2 text format.
+func (m *Movie) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	h := m.Header
+
+	fields := []struct {
+		key   string
+		value any
+	}{
+		{"version", h.Version},
+		{"emuVersion", h.EmuVersion},
+		{"rerecordCount", h.RerecordCount},
+		{"palFlag", fm2Bool(h.PAL)},
+		{"romFilename", h.ROMFilename},
+		{"romChecksum", h.ROMChecksum},
+		{"guid", h.GUID},
+		{"fourscore", fm2Bool(h.FourScore)},
+		{"microphone", fm2Bool(h.Microphone)},
+		{"port0", h.Ports[0]},
+		{"port1", h.Ports[1]},
+		{"port2", h.Ports[2]},
+		{"FDS", fm2Bool(h.FDS)},
+		{"NewPPU", fm2Bool(h.NewPPU)},
+	}
+	for _, field := range fields {
+		if _, err := fmt.Fprintf(bw, "%s %v\n", field.key, field.value); err != nil {
+			return err
+		}
+	}
+	for _, comment := range h.Comments {
+		if _, err := fmt.Fprintf(bw, "comment %s\n", comment); err != nil {
+			return err
+		}
+	}
+	for _, subtitle := range h.Subtitles {
+		if _, err := fmt.Fprintf(bw, "subtitle %s\n", subtitle); err != nil {
+			return err
+		}
+	}
+	if h.Savestate != nil {
+		if _, err := fmt.Fprintf(bw, "%s %s%s\n",
+			fm2SavestateKey, fm2Base64Prefix, base64.StdEncoding.EncodeToString(h.Savestate),
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, frame := range m.Frames {
+		if _, err := bw.WriteString(frame.fm2String(h.players())); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (f Frame) fm2String(players int) string {
+	var s strings.Builder
+	s.WriteByte('|')
+	s.WriteString(strconv.Itoa(int(f.Command)))
+	s.WriteByte('|')
+	for _, b := range f.Buttons[:players] {
+		for _, btn := range fm2ButtonOrder {
+			if b.Pressed(btn.button) {
+				s.WriteByte(btn.char)
+			} else {
+				s.WriteByte('.')
+			}
+		}
+		s.WriteByte('|')
+	}
+	s.WriteString("|\n")
+	return s.String()
+}
+
+func fm2Bool(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
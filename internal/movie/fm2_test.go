@@ -0,0 +1,107 @@
+package movie
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gabe565.com/gones/internal/controller/button"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFM2 = `version 3
+emuVersion 22020
+rerecordCount 12
+palFlag 0
+romFilename Super Mario Bros.
+romChecksum base64:jjYwGG411HcjG/j9UOVM3Q==
+guid 5A7E8C4D-0B1F-4C36-9D6A-2B3E1F0A9C8D
+fourscore 0
+microphone 0
+port0 1
+port1 1
+port2 0
+FDS 0
+NewPPU 0
+comment author someone
+|2|........|........||
+|0|.......A|........||
+|0|R......A|.L......||
+|1|....T...|........||
+`
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	m, err := Read(strings.NewReader(testFM2))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, m.Header.Version)
+	assert.Equal(t, 22020, m.Header.EmuVersion)
+	assert.Equal(t, 12, m.Header.RerecordCount)
+	assert.Equal(t, "Super Mario Bros.", m.Header.ROMFilename)
+	assert.Equal(t, "base64:jjYwGG411HcjG/j9UOVM3Q==", m.Header.ROMChecksum)
+	assert.Equal(t, [3]int{PortGamepad, PortGamepad, PortNone}, m.Header.Ports)
+	assert.Equal(t, []string{"author someone"}, m.Header.Comments)
+	require.Len(t, m.Frames, 4)
+
+	assert.Equal(t, CommandPowerCycle, m.Frames[0].Command)
+	assert.True(t, m.Frames[1].Buttons[0].Pressed(button.A))
+	assert.False(t, m.Frames[1].Buttons[0].Pressed(button.B))
+	assert.True(t, m.Frames[2].Buttons[0].Pressed(button.Right))
+	assert.True(t, m.Frames[2].Buttons[1].Pressed(button.Left))
+	assert.Equal(t, CommandSoftReset, m.Frames[3].Command)
+	assert.True(t, m.Frames[3].Buttons[0].Pressed(button.Start))
+}
+
+func TestRead_Binary(t *testing.T) {
+	t.Parallel()
+
+	_, err := Read(strings.NewReader("version 3\nbinary 1\n"))
+	require.ErrorIs(t, err, ErrBinaryFM2)
+}
+
+func TestRead_FCEUXSavestate(t *testing.T) {
+	t.Parallel()
+
+	_, err := Read(strings.NewReader("version 3\nsavestate base64:AQID\n"))
+	require.ErrorIs(t, err, ErrFCEUXSavestate)
+}
+
+func TestMovie_Write(t *testing.T) {
+	t.Parallel()
+
+	m, err := Read(strings.NewReader(testFM2))
+	require.NoError(t, err)
+	m.Header.Savestate = []byte{1, 2, 3}
+
+	var buf bytes.Buffer
+	require.NoError(t, m.Write(&buf))
+	assert.Contains(t, buf.String(), "|0|R......A|.L......||\n")
+	assert.Contains(t, buf.String(), "gonesSavestate base64:AQID\n")
+
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+}
+
+func TestMovie_Write_FourScore(t *testing.T) {
+	t.Parallel()
+
+	m := New("Gauntlet II", "")
+	m.Header.FourScore = true
+	var frame Frame
+	frame.Buttons[0].Set(button.A, true)
+	frame.Buttons[3].Set(button.Right, true)
+	m.Frames = append(m.Frames, frame)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.Write(&buf))
+	assert.Contains(t, buf.String(), "fourscore 1\n")
+	assert.Contains(t, buf.String(), "|0|.......A|........|........|R.......||\n")
+
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+}
@@ -0,0 +1,107 @@
+package movie
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"gabe565.com/gones/internal/controller/button"
+)
+
+// Command is a bitmask of events that happen at the start of a movie frame.
+type Command uint8
+
+const (
+	CommandSoftReset Command = 1 << iota
+	CommandPowerCycle
+	CommandFDSInsert
+	CommandFDSSelect
+	CommandVSCoin
+)
+
+// Buttons is a bitmask of pressed buttons, indexed by [button.Button].
+type Buttons uint8
+
+func (b Buttons) Pressed(btn button.Button) bool {
+	return b&(1<<btn) != 0
+}
+
+func (b *Buttons) Set(btn button.Button, pressed bool) {
+	if pressed {
+		*b |= 1 << btn
+	} else {
+		*b &^= 1 << btn
+	}
+}
+
+// Frame contains the input for a single frame.
+// Players 3 and 4 are only used when the movie has FourScore set.
+type Frame struct {
+	Command Command
+	Buttons [4]Buttons
+}
+
+// Movie is an input recording.
+//
+// See [FM2 format].
+//
+// [FM2 format]: https://fceux.com/web/help/fm2.html
+type Movie struct {
+	Header Header
+	Frames []Frame
+}
+
+// Header contains the FM2 header fields understood by GoNES.
+type Header struct {
+	Version       int
+	EmuVersion    int
+	RerecordCount int
+	PAL           bool
+	NewPPU        bool
+	FDS           bool
+	FourScore     bool
+	Microphone    bool
+	Ports         [3]int
+	ROMFilename   string
+	ROMChecksum   string
+	GUID          string
+	Comments      []string
+	Subtitles     []string
+
+	// Savestate is a GoNES save state that the movie begins from.
+	// Movies without a save state begin from power-on.
+	Savestate []byte
+}
+
+// players returns the number of gamepad fields in each frame.
+func (h Header) players() int {
+	if h.FourScore {
+		return 4
+	}
+	return 2
+}
+
+const (
+	PortNone    = 0
+	PortGamepad = 1
+)
+
+// New creates an empty movie for a ROM.
+func New(romFilename, romChecksum string) *Movie {
+	return &Movie{
+		Header: Header{
+			Version:     3,
+			Ports:       [3]int{PortGamepad, PortGamepad, PortNone},
+			ROMFilename: romFilename,
+			ROMChecksum: romChecksum,
+			GUID:        newGUID(),
+		},
+	}
+}
+
+func newGUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0F | 0x40
+	b[8] = b[8]&0x3F | 0x80
+	return fmt.Sprintf("%X-%X-%X-%X-%X", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
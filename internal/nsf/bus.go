@@ -0,0 +1,255 @@
+package nsf
+
+import (
+	"gabe565.com/gones/internal/apu"
+)
+
+const (
+	// driverAddr is the address of the driver that calls INIT and PLAY.
+	// $4100-$4FFF is unused by NSF files.
+	driverAddr = 0x4100
+	// driverIdleAddr is the driver's idle loop. The CPU reaches it once INIT or PLAY returns.
+	driverIdleAddr = driverAddr + 3
+	bankSize       = 0x1000
+)
+
+// Bus is the CPU memory map for NSF playback. It replaces the cartridge with the NSF's program data.
+type Bus struct {
+	nsf *NSF
+	apu *apu.APU
+
+	RAM [0x800]byte
+	// ExtRAM is RAM from $6000-$7FFF. FDS files can also write to $8000-$FFFF.
+	ExtRAM [0xA000]byte
+	Banks  [8]int
+	prg    []byte
+
+	// MMC5RAM is the MMC5's ExRAM from $5C00-$5FF5.
+	MMC5RAM      [0x3F6]byte
+	Multiplicand byte
+	Multiplier   byte
+	// N163RAM is the Namco 163's internal RAM.
+	N163RAM [apu.N163RAMSize]byte
+
+	MMC5 apu.MMC5
+	VRC6 apu.VRC6
+	VRC7 apu.VRC7
+	N163 apu.N163
+	FDS  apu.FDS
+
+	// driver is a JSR to the routine being called, followed by an idle loop.
+	driver [6]byte
+}
+
+func newBus(n *NSF, a *apu.APU) *Bus {
+	b := &Bus{
+		nsf:    n,
+		apu:    a,
+		driver: [6]byte{0x20, 0, 0, 0x4C, driverIdleAddr & 0xFF, driverIdleAddr >> 8},
+	}
+
+	if n.Bankswitched() {
+		// Data is padded so that the load address is at its offset within the first bank
+		pad := int(n.LoadAddr) % bankSize
+		size := (pad + len(n.Data) + bankSize - 1) / bankSize * bankSize
+		b.prg = make([]byte, size)
+		copy(b.prg[pad:], n.Data)
+	} else if n.LoadAddr >= 0x8000 {
+		b.prg = make([]byte, 0x8000)
+		copy(b.prg[n.LoadAddr-0x8000:], n.Data)
+	}
+	return b
+}
+
+// reset clears RAM and restores the initial banks.
+func (b *Bus) reset() {
+	clear(b.RAM[:])
+	clear(b.ExtRAM[:])
+	clear(b.MMC5RAM[:])
+	b.MMC5 = apu.MMC5{}
+	b.VRC6 = apu.VRC6{}
+	b.VRC7 = apu.VRC7{}
+	clear(b.N163RAM[:])
+	b.N163 = apu.NewN163(b.N163RAM[:])
+	b.FDS = apu.FDS{}
+
+	fds := b.nsf.Chips.Has(ChipFDS)
+	switch {
+	case b.nsf.Bankswitched():
+		if fds {
+			// FDS files also set the banks at $6000-$7FFF from the last two banks
+			b.WriteMem(0x5FF6, b.nsf.Banks[6])
+			b.WriteMem(0x5FF7, b.nsf.Banks[7])
+		}
+		for i, bank := range b.nsf.Banks {
+			b.WriteMem(0x5FF8+uint16(i), bank)
+		}
+	case fds:
+		copy(b.ExtRAM[b.nsf.LoadAddr-0x6000:], b.nsf.Data)
+	default:
+		for i := range b.Banks {
+			b.Banks[i] = i
+		}
+	}
+}
+
+// expansions returns the expansion audio chips used by the NSF.
+func (b *Bus) expansions() []apu.Expansion {
+	var e []apu.Expansion
+	if b.nsf.Chips.Has(ChipFDS) {
+		e = append(e, &b.FDS)
+	}
+	if b.nsf.Chips.Has(ChipMMC5) {
+		e = append(e, &b.MMC5)
+	}
+	if b.nsf.Chips.Has(ChipVRC6) {
+		e = append(e, &b.VRC6)
+	}
+	if b.nsf.Chips.Has(ChipVRC7) {
+		e = append(e, &b.VRC7)
+	}
+	if b.nsf.Chips.Has(ChipN163) {
+		e = append(e, &b.N163)
+	}
+	return e
+}
+
+// call runs a routine through the driver.
+func (b *Bus) call(addr uint16) {
+	b.driver[1] = byte(addr)
+	b.driver[2] = byte(addr >> 8)
+}
+
+// bank returns a 4 KiB bank from the program data.
+func (b *Bus) bank(bank int) []byte {
+	offset := bank * bankSize
+	if offset+bankSize > len(b.prg) {
+		return nil
+	}
+	return b.prg[offset : offset+bankSize]
+}
+
+func (b *Bus) ReadMem(addr uint16) byte {
+	switch {
+	case addr < 0x2000:
+		return b.RAM[addr&0x7FF]
+	case addr == 0x4015:
+		return b.apu.ReadMem(addr)
+	case 0x4040 <= addr && addr <= 0x4097 && b.nsf.Chips.Has(ChipFDS):
+		data, _ := b.FDS.Read(addr)
+		return data
+	case 0x4800 <= addr && addr < 0x5000 && b.nsf.Chips.Has(ChipN163):
+		return b.N163.ReadData()
+	case (addr == 0x5010 || addr == 0x5015) && b.nsf.Chips.Has(ChipMMC5):
+		return b.MMC5.Read(addr)
+	case (addr == 0x5205 || addr == 0x5206) && b.nsf.Chips.Has(ChipMMC5):
+		product := uint16(b.Multiplicand) * uint16(b.Multiplier)
+		return byte(product >> (8 * (addr - 0x5205)))
+	case 0x5C00 <= addr && addr < 0x5FF6 && b.nsf.Chips.Has(ChipMMC5):
+		return b.MMC5RAM[addr-0x5C00]
+	case driverAddr <= addr && addr < driverAddr+uint16(len(b.driver)):
+		return b.driver[addr-driverAddr]
+	case 0x6000 <= addr && addr < 0x8000:
+		return b.ExtRAM[addr-0x6000]
+	case 0x8000 <= addr:
+		if b.nsf.Chips.Has(ChipFDS) {
+			return b.ExtRAM[addr-0x6000]
+		}
+		if bank := b.bank(b.Banks[(addr-0x8000)/bankSize]); bank != nil {
+			return bank[addr%bankSize]
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// ReadMemSafe reads a byte from memory, but immediately returns 0xFF for any reads with side effects.
+func (b *Bus) ReadMemSafe(addr uint16) byte {
+	if addr == 0x4015 || 0x4800 <= addr && addr < 0x5000 {
+		return 0xFF
+	}
+	return b.ReadMem(addr)
+}
+
+func (b *Bus) WriteMem(addr uint16, data byte) {
+	b.writeChips(addr, data)
+
+	switch {
+	case addr < 0x2000:
+		b.RAM[addr&0x7FF] = data
+	case addr <= 0x4013, addr == 0x4015, addr == 0x4017:
+		if addr >= 0x4000 {
+			b.apu.WriteMem(addr, data)
+		}
+	case addr == 0x5205 && b.nsf.Chips.Has(ChipMMC5):
+		b.Multiplicand = data
+	case addr == 0x5206 && b.nsf.Chips.Has(ChipMMC5):
+		b.Multiplier = data
+	case 0x5C00 <= addr && addr < 0x5FF6 && b.nsf.Chips.Has(ChipMMC5):
+		b.MMC5RAM[addr-0x5C00] = data
+	case 0x5FF6 <= addr && addr <= 0x5FFF && b.nsf.Bankswitched():
+		b.writeBank(addr, data)
+	case 0x6000 <= addr && addr < 0x8000:
+		b.ExtRAM[addr-0x6000] = data
+	case 0x8000 <= addr && b.nsf.Chips.Has(ChipFDS):
+		b.ExtRAM[addr-0x6000] = data
+	}
+}
+
+// writeChips writes to the expansion audio chips. Chips decode addresses on their own,
+// so a write can reach more than one chip, or a chip and RAM.
+func (b *Bus) writeChips(addr uint16, data byte) {
+	chips := b.nsf.Chips
+	if chips.Has(ChipFDS) && 0x4040 <= addr && addr <= 0x408A {
+		b.FDS.Write(addr, data)
+	}
+	if chips.Has(ChipN163) {
+		switch {
+		case 0x4800 <= addr && addr < 0x5000:
+			b.N163.WriteData(data)
+		case 0xF800 <= addr:
+			b.N163.WriteAddr(data)
+		}
+	}
+	if chips.Has(ChipMMC5) && 0x5000 <= addr && addr <= 0x5015 {
+		b.MMC5.Write(addr, data)
+	}
+	if chips.Has(ChipVRC6) && 0x9000 <= addr && addr <= 0xB002 {
+		b.VRC6.Write(addr, data)
+	}
+	if chips.Has(ChipVRC7) && (addr == 0x9010 || addr == 0x9030) {
+		b.VRC7.Write(addr, data)
+	}
+}
+
+func (b *Bus) writeBank(addr uint16, data byte) {
+	if b.nsf.Chips.Has(ChipFDS) {
+		// FDS RAM is filled with a copy of the bank
+		offset := int(addr-0x5FF6) * bankSize
+		bank := b.bank(int(data))
+		if bank == nil {
+			clear(b.ExtRAM[offset : offset+bankSize])
+		} else {
+			copy(b.ExtRAM[offset:], bank)
+		}
+		return
+	}
+
+	if addr >= 0x5FF8 {
+		b.Banks[addr-0x5FF8] = int(data)
+	}
+}
+
+// ReadMem16 reads two bytes from memory.
+func (b *Bus) ReadMem16(addr uint16) uint16 {
+	lo := uint16(b.ReadMem(addr))
+	hi := uint16(b.ReadMem(addr + 1))
+	return hi<<8 | lo
+}
+
+// WriteMem16 writes two bytes to memory.
+func (b *Bus) WriteMem16(addr uint16, data uint16) {
+	b.WriteMem(addr, byte(data))
+	b.WriteMem(addr+1, byte(data>>8))
+}
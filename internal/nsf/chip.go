@@ -0,0 +1,36 @@
+package nsf
+
+import "strings"
+
+// Chip is a bitmask of the expansion audio chips that a file uses.
+type Chip uint8
+
+const (
+	ChipVRC6 Chip = 1 << iota
+	ChipVRC7
+	ChipFDS
+	ChipMMC5
+	ChipN163
+	ChipS5B
+)
+
+//nolint:gochecknoglobals
+var chipNames = [...]string{"VRC6", "VRC7", "FDS", "MMC5", "Namco 163", "Sunsoft 5B"}
+
+// Has reports whether all chips in c2 are set.
+func (c Chip) Has(c2 Chip) bool {
+	return c&c2 == c2
+}
+
+func (c Chip) String() string {
+	names := make([]string, 0, len(chipNames))
+	for i, name := range chipNames {
+		if c.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, ", ")
+}
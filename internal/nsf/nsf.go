@@ -0,0 +1,314 @@
+package nsf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gabe565.com/gones/internal/archive"
+	"gabe565.com/gones/internal/util"
+)
+
+const (
+	headerSize = 0x80
+	// DefaultSpeed is the NTSC play routine period in microseconds when a file does not set one.
+	DefaultSpeed = 16639
+	// DefaultPALSpeed is the PAL play routine period in microseconds when a file does not set one.
+	DefaultPALSpeed = 19997
+)
+
+//nolint:gochecknoglobals
+var (
+	nsfMagic  = []byte{'N', 'E', 'S', 'M', 0x1A}
+	nsfeMagic = []byte("NSFE")
+)
+
+var ErrInvalidNSF = errors.New("invalid NSF file")
+
+// NSF is a parsed NSF or NSFe music file.
+//
+// See [NSF] and [NSFe].
+//
+// [NSF]: https://www.nesdev.org/wiki/NSF
+// [NSFe]: https://www.nesdev.org/wiki/NSFe
+type NSF struct {
+	hash string
+
+	Name      string
+	Artist    string
+	Copyright string
+	Ripper    string
+
+	LoadAddr uint16
+	InitAddr uint16
+	PlayAddr uint16
+	// Speed is the NTSC play routine period in microseconds.
+	Speed uint16
+	// PALSpeed is the PAL play routine period in microseconds.
+	PALSpeed uint16
+	// PAL is set when the file only plays on PAL consoles. Files for both regions are played as NTSC.
+	PAL bool
+	// Banks contains the initial bank for each 4 KiB page from $8000-$FFFF.
+	// The file is bankswitched if any bank is non-zero.
+	Banks [8]byte
+	Chips Chip
+
+	Songs     int
+	StartSong int
+	// Playlist is the order that songs are played in. It contains every song when the file does not have a playlist.
+	Playlist []int
+	Tracks   []Track
+
+	Data []byte
+}
+
+// Track contains optional NSFe metadata for a song.
+type Track struct {
+	Title string
+	// Length is how long the song plays before fading out, or 0 if it is unknown.
+	Length time.Duration
+	// Fade is how long the song takes to fade out.
+	Fade time.Duration
+}
+
+func FromFile(path string) (*NSF, error) {
+	name, b, err := archive.ReadFile(path, IsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Name == "" {
+		name = filepath.Base(name)
+		n.Name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return n, nil
+}
+
+// IsFile reports whether a path has an NSF or NSFe extension.
+func IsFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".nsf", ".nsfe":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse parses an NSF or NSFe file.
+func Parse(b []byte) (*NSF, error) {
+	var n *NSF
+	var err error
+	switch {
+	case bytes.HasPrefix(b, nsfMagic):
+		n, err = parseNSF(b)
+	case bytes.HasPrefix(b, nsfeMagic):
+		n, err = parseNSFe(b[len(nsfeMagic):])
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Songs == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "no songs")
+	}
+	if n.StartSong < 0 || n.StartSong >= n.Songs {
+		n.StartSong = 0
+	}
+	if n.Speed == 0 {
+		n.Speed = DefaultSpeed
+	}
+	if n.PALSpeed == 0 {
+		n.PALSpeed = DefaultPALSpeed
+	}
+	if len(n.Playlist) == 0 {
+		n.Playlist = make([]int, n.Songs)
+		for i := range n.Playlist {
+			n.Playlist[i] = i
+		}
+	}
+	if len(n.Tracks) < n.Songs {
+		n.Tracks = append(n.Tracks, make([]Track, n.Songs-len(n.Tracks))...)
+	}
+	if !n.Bankswitched() {
+		// FDS files load into RAM from $6000
+		minAddr := uint16(0x8000)
+		if n.Chips.Has(ChipFDS) {
+			minAddr = 0x6000
+		}
+		if n.LoadAddr < minAddr {
+			return nil, fmt.Errorf("%w: load address $%04X is below $%04X", ErrInvalidNSF, n.LoadAddr, minAddr)
+		}
+	}
+
+	sum := md5.Sum(b)
+	n.hash = hex.EncodeToString(sum[:])
+	return n, nil
+}
+
+func parseNSF(b []byte) (*NSF, error) {
+	if len(b) < headerSize {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "truncated header")
+	}
+
+	n := &NSF{
+		Songs:     int(b[0x06]),
+		StartSong: int(b[0x07]) - 1,
+		LoadAddr:  binary.LittleEndian.Uint16(b[0x08:]),
+		InitAddr:  binary.LittleEndian.Uint16(b[0x0A:]),
+		PlayAddr:  binary.LittleEndian.Uint16(b[0x0C:]),
+		Name:      util.CString(b[0x0E:0x2E]),
+		Artist:    util.CString(b[0x2E:0x4E]),
+		Copyright: util.CString(b[0x4E:0x6E]),
+		Speed:     binary.LittleEndian.Uint16(b[0x6E:]),
+		PALSpeed:  binary.LittleEndian.Uint16(b[0x78:]),
+		PAL:       b[0x7A]&0x3 == 0x1,
+		Chips:     Chip(b[0x7B]),
+		Data:      b[headerSize:],
+	}
+	copy(n.Banks[:], b[0x70:0x78])
+
+	// NSF2 files can set the program length, followed by metadata
+	if b[0x05] >= 2 {
+		if size := int(b[0x7D]) | int(b[0x7E])<<8 | int(b[0x7F])<<16; size != 0 && size < len(n.Data) {
+			n.Data = n.Data[:size]
+		}
+	}
+	return n, nil
+}
+
+func parseNSFe(b []byte) (*NSF, error) {
+	n := &NSF{Songs: 1}
+	var hasInfo, hasData bool
+
+	for len(b) != 0 {
+		if len(b) < 8 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "truncated chunk")
+		}
+		size := int(binary.LittleEndian.Uint32(b))
+		id := string(b[4:8])
+		b = b[8:]
+		if size > len(b) {
+			return nil, fmt.Errorf("%w: truncated %s chunk", ErrInvalidNSF, id)
+		}
+		chunk := b[:size]
+		b = b[size:]
+
+		switch id {
+		case "INFO":
+			if len(chunk) < 8 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "truncated INFO chunk")
+			}
+			hasInfo = true
+			n.LoadAddr = binary.LittleEndian.Uint16(chunk[0:])
+			n.InitAddr = binary.LittleEndian.Uint16(chunk[2:])
+			n.PlayAddr = binary.LittleEndian.Uint16(chunk[4:])
+			n.PAL = chunk[6]&0x3 == 0x1
+			n.Chips = Chip(chunk[7])
+			if len(chunk) > 8 {
+				n.Songs = int(chunk[8])
+			}
+			if len(chunk) > 9 {
+				n.StartSong = int(chunk[9])
+			}
+		case "DATA":
+			hasData = true
+			n.Data = chunk
+		case "BANK":
+			copy(n.Banks[:], chunk)
+		case "RATE":
+			if len(chunk) >= 2 {
+				n.Speed = binary.LittleEndian.Uint16(chunk)
+			}
+			if len(chunk) >= 4 {
+				n.PALSpeed = binary.LittleEndian.Uint16(chunk[2:])
+			}
+		case "auth":
+			fields := []*string{&n.Name, &n.Artist, &n.Copyright, &n.Ripper}
+			for i, s := range cStrings(chunk) {
+				if i < len(fields) {
+					*fields[i] = s
+				}
+			}
+		case "tlbl":
+			for i, s := range cStrings(chunk) {
+				n.track(i).Title = s
+			}
+		case "time":
+			for i := 0; i+4 <= len(chunk); i += 4 {
+				if ms := int32(binary.LittleEndian.Uint32(chunk[i:])); ms >= 0 {
+					n.track(i / 4).Length = time.Duration(ms) * time.Millisecond
+				}
+			}
+		case "fade":
+			for i := 0; i+4 <= len(chunk); i += 4 {
+				if ms := int32(binary.LittleEndian.Uint32(chunk[i:])); ms >= 0 {
+					n.track(i / 4).Fade = time.Duration(ms) * time.Millisecond
+				}
+			}
+		case "plst":
+			n.Playlist = make([]int, len(chunk))
+			for i, song := range chunk {
+				n.Playlist[i] = int(song)
+			}
+		case "NEND":
+			b = nil
+		default:
+			// Chunks starting with an uppercase letter are required to play the file
+			if id[0] >= 'A' && id[0] <= 'Z' {
+				return nil, fmt.Errorf("%w: unsupported required chunk %q", ErrInvalidNSF, id)
+			}
+		}
+	}
+
+	if !hasInfo || !hasData {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNSF, "missing INFO or DATA chunk")
+	}
+
+	n.Playlist = slices.DeleteFunc(n.Playlist, func(song int) bool { return song >= n.Songs })
+	return n, nil
+}
+
+// track returns the metadata for a song, growing Tracks if necessary.
+func (n *NSF) track(song int) *Track {
+	if song >= len(n.Tracks) {
+		n.Tracks = append(n.Tracks, make([]Track, song+1-len(n.Tracks))...)
+	}
+	return &n.Tracks[song]
+}
+
+func (n *NSF) Hash() string {
+	return n.hash
+}
+
+// Bankswitched reports whether the file uses bankswitching.
+func (n *NSF) Bankswitched() bool {
+	return n.Banks != [8]byte{}
+}
+
+// cStrings splits null-terminated strings.
+func cStrings(b []byte) []string {
+	b = bytes.TrimSuffix(b, []byte{0})
+	if len(b) == 0 {
+		return nil
+	}
+	parts := bytes.Split(b, []byte{0})
+	s := make([]string, 0, len(parts))
+	for _, part := range parts {
+		s = append(s, string(part))
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+package nsf
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProgram stores A in $00 during INIT, and increments $01 during PLAY.
+//
+//nolint:gochecknoglobals
+var testProgram = []byte{
+	0x85, 0x00, // INIT: STA $00
+	0x60,       // RTS
+	0xE6, 0x01, // PLAY: INC $01
+	0x60, // RTS
+}
+
+func testNSF(banks [8]byte, data []byte) []byte {
+	b := make([]byte, headerSize)
+	copy(b, nsfMagic)
+	b[0x05] = 1
+	b[0x06] = 3
+	b[0x07] = 2
+	binary.LittleEndian.PutUint16(b[0x08:], 0x8000)
+	binary.LittleEndian.PutUint16(b[0x0A:], 0x8000)
+	binary.LittleEndian.PutUint16(b[0x0C:], 0x8003)
+	copy(b[0x0E:], "Title")
+	copy(b[0x2E:], "Artist")
+	copy(b[0x4E:], "Copyright")
+	binary.LittleEndian.PutUint16(b[0x6E:], 16639)
+	copy(b[0x70:], banks[:])
+	return append(b, data...)
+}
+
+func nsfeChunk(id string, data []byte) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, uint32(len(data)))
+	b = append(b, id...)
+	return append(b, data...)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nsf", func(t *testing.T) {
+		t.Parallel()
+		n, err := Parse(testNSF([8]byte{}, testProgram))
+		require.NoError(t, err)
+		assert.Equal(t, "Title", n.Name)
+		assert.Equal(t, "Artist", n.Artist)
+		assert.Equal(t, "Copyright", n.Copyright)
+		assert.Equal(t, 3, n.Songs)
+		assert.Equal(t, 1, n.StartSong)
+		assert.Equal(t, []int{0, 1, 2}, n.Playlist)
+		assert.EqualValues(t, 0x8003, n.PlayAddr)
+		assert.False(t, n.PAL)
+		assert.EqualValues(t, DefaultPALSpeed, n.PALSpeed)
+		assert.False(t, n.Bankswitched())
+		assert.Equal(t, testProgram, n.Data)
+	})
+
+	t.Run("nsfe", func(t *testing.T) {
+		t.Parallel()
+		info := []byte{0x00, 0x80, 0x00, 0x80, 0x03, 0x80, 0, byte(ChipFDS), 3, 0}
+		var b []byte
+		b = append(b, nsfeMagic...)
+		b = append(b, nsfeChunk("INFO", info)...)
+		b = append(b, nsfeChunk("DATA", testProgram)...)
+		b = append(b, nsfeChunk("auth", []byte("Title\x00Artist\x00Copyright\x00Ripper\x00"))...)
+		b = append(b, nsfeChunk("tlbl", []byte("First\x00Second\x00Third\x00"))...)
+		b = append(b, nsfeChunk("time", binary.LittleEndian.AppendUint32([]byte{0xFF, 0xFF, 0xFF, 0xFF}, 90000))...)
+		b = append(b, nsfeChunk("fade", binary.LittleEndian.AppendUint32(nil, 2000))...)
+		b = append(b, nsfeChunk("plst", []byte{2, 0})...)
+		b = append(b, nsfeChunk("xtra", []byte{1, 2, 3})...)
+		b = append(b, nsfeChunk("NEND", nil)...)
+
+		n, err := Parse(b)
+		require.NoError(t, err)
+		assert.Equal(t, "Title", n.Name)
+		assert.Equal(t, "Ripper", n.Ripper)
+		assert.Equal(t, 3, n.Songs)
+		assert.Equal(t, ChipFDS, n.Chips)
+		assert.EqualValues(t, DefaultSpeed, n.Speed)
+		assert.Equal(t, []int{2, 0}, n.Playlist)
+		assert.Equal(t, []Track{
+			{Title: "First", Fade: 2 * time.Second},
+			{Title: "Second", Length: 90 * time.Second},
+			{Title: "Third"},
+		}, n.Tracks)
+	})
+
+	t.Run("nsfe required chunk", func(t *testing.T) {
+		t.Parallel()
+		b := append([]byte{}, nsfeMagic...)
+		b = append(b, nsfeChunk("ABCD", nil)...)
+		_, err := Parse(b)
+		require.ErrorIs(t, err, ErrInvalidNSF)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse([]byte("NESM"))
+		require.ErrorIs(t, err, ErrInvalidNSF)
+	})
+}
+
+func TestChip_String(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "None", Chip(0).String())
+	assert.Equal(t, "VRC6, FDS", (ChipVRC6 | ChipFDS).String())
+}
@@ -0,0 +1,305 @@
+package nsf
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"gabe565.com/gones/internal/apu"
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
+	"gabe565.com/gones/internal/cpu"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+var ErrExit = errors.New("exit")
+
+// supportedChips are the expansion audio chips that can be played.
+const supportedChips = ChipFDS | ChipMMC5 | ChipVRC6 | ChipVRC7 | ChipN163
+
+// Player plays an NSF file. It runs the file's INIT and PLAY routines on the CPU and APU
+// without a cartridge or PPU.
+type Player struct {
+	conf *config.Config
+	nsf  *NSF
+
+	CPU *cpu.CPU
+	APU *apu.APU
+	Bus *Bus
+
+	// Region is the console timing. PAL files play with PAL timing unless the config overrides it.
+	Region consts.Region
+
+	// Track is the index of the playing song in the playlist.
+	Track  int
+	Paused bool
+
+	cycles      uint
+	frameCycles float64
+	frameTimer  float64
+	playPeriod  float64
+	playTimer   float64
+	exit        bool
+
+	audioCtx *audio.Context
+	player   *audio.Player
+}
+
+func NewPlayer(conf *config.Config, n *NSF) (*Player, error) {
+	p := &Player{
+		conf:   conf,
+		nsf:    n,
+		APU:    apu.New(conf),
+		Region: selectRegion(conf, n),
+	}
+	p.APU.SetRegion(p.Region)
+	p.Bus = newBus(n, p.APU)
+
+	speed := n.Speed
+	if p.Region != consts.RegionNTSC {
+		speed = n.PALSpeed
+	}
+	p.playPeriod = float64(speed) * p.Region.CPUFrequency() / float64(time.Second/time.Microsecond)
+	p.frameCycles = p.Region.CPUFrequency() / p.Region.FrameRate()
+
+	if unsupported := n.Chips &^ supportedChips; unsupported != 0 {
+		slog.Warn("NSF uses unsupported expansion audio", "chips", unsupported)
+	}
+
+	if conf.Audio.Enabled {
+		var err error
+		p.audioCtx = audio.NewContext(consts.AudioSampleRate)
+		p.player, err = p.audioCtx.NewPlayerF32(p.APU)
+		if err != nil {
+			return p, err
+		}
+		p.player.SetBufferSize(time.Second / 20)
+		p.player.SetVolume(conf.Audio.Volume)
+		go func() {
+			p.player.Play()
+		}()
+	} else {
+		p.APU.Enabled = false
+	}
+
+	p.Play(max(slices.Index(n.Playlist, n.StartSong), 0))
+	return p, nil
+}
+
+// Play starts a song from the playlist. The index wraps around at either end of the playlist.
+func (p *Player) Play(track int) {
+	count := len(p.nsf.Playlist)
+	p.Track = (track%count + count) % count
+
+	p.APU.Power()
+	p.APU.Clear()
+	p.Bus.reset()
+	p.APU.SetExpansions(p.Bus.expansions()...)
+	for addr := uint16(0x4000); addr <= 0x4013; addr++ {
+		p.Bus.WriteMem(addr, 0)
+	}
+	p.Bus.WriteMem(0x4015, 0x0F)
+	p.Bus.WriteMem(0x4017, 0x40)
+	if p.nsf.Chips.Has(ChipFDS) {
+		p.Bus.WriteMem(0x4089, 0x80)
+		p.Bus.WriteMem(0x408A, 0xE8)
+	}
+
+	p.CPU = cpu.New(p.Bus)
+	p.APU.SetCPU(p.CPU)
+	p.CPU.StackPointer = 0xFD
+	p.CPU.Accumulator = byte(p.Song())
+	if p.Region == consts.RegionNTSC {
+		p.CPU.RegisterX = 0
+	} else {
+		p.CPU.RegisterX = 1
+	}
+	p.call(p.nsf.InitAddr)
+
+	p.cycles = 0
+	p.frameTimer = 0
+	p.playTimer = p.playPeriod
+	p.setVolume(1)
+
+	slog.Info("Playing song", "track", p.Track+1, "title", p.Title())
+}
+
+// Song returns the playing song number, starting at 0.
+func (p *Player) Song() int {
+	return p.nsf.Playlist[p.Track]
+}
+
+// Title returns the playing song's title, or a generic name if the file does not have one.
+func (p *Player) Title() string {
+	if title := p.nsf.Tracks[p.Song()].Title; title != "" {
+		return title
+	}
+	return fmt.Sprintf("Song %d", p.Song()+1)
+}
+
+// Elapsed returns how long the song has played.
+func (p *Player) Elapsed() time.Duration {
+	return time.Duration(float64(p.cycles) / p.Region.CPUFrequency() * float64(time.Second))
+}
+
+// selectRegion returns the region from the config, or the region that the file was made for.
+func selectRegion(conf *config.Config, n *NSF) consts.Region {
+	switch conf.System.Region {
+	case config.RegionNTSC:
+		return consts.RegionNTSC
+	case config.RegionPAL:
+		return consts.RegionPAL
+	case config.RegionDendy:
+		return consts.RegionDendy
+	}
+
+	if n.PAL {
+		return consts.RegionPAL
+	}
+	return consts.RegionNTSC
+}
+
+// call runs a routine through the driver.
+func (p *Player) call(addr uint16) {
+	p.Bus.call(addr)
+	p.CPU.ProgramCounter = driverAddr
+}
+
+// idle reports whether the last routine has returned.
+func (p *Player) idle() bool {
+	return p.CPU.ProgramCounter == driverIdleAddr
+}
+
+// StepFrame runs the CPU and APU for one frame, calling PLAY at the file's rate.
+func (p *Player) StepFrame() error {
+	p.frameTimer += p.frameCycles
+	for p.frameTimer > 0 {
+		cycles := p.CPU.Step()
+		if p.CPU.StepErr != nil {
+			return p.CPU.StepErr
+		}
+		for range cycles {
+			p.APU.Step()
+		}
+
+		p.cycles += cycles
+		p.frameTimer -= float64(cycles)
+		p.playTimer -= float64(cycles)
+		if p.playTimer <= 0 {
+			p.playTimer += p.playPeriod
+			// PLAY is skipped if INIT or the previous PLAY has not returned
+			if p.idle() {
+				p.call(p.nsf.PlayAddr)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Player) setVolume(v float64) {
+	if p.player != nil {
+		p.player.SetVolume(p.conf.Audio.Volume * v)
+	}
+}
+
+// Exit stops the player at the next update.
+func (p *Player) Exit() {
+	p.exit = true
+}
+
+func (p *Player) Close() error {
+	if p.player != nil {
+		return p.player.Close()
+	}
+	return nil
+}
+
+func (p *Player) Update() error {
+	if p.exit {
+		return ErrExit
+	}
+
+	keymap := p.conf.Input.Player1
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.Key(keymap.Right)):
+		p.Play(p.Track + 1)
+	case inpututil.IsKeyJustPressed(ebiten.Key(keymap.Left)):
+		p.Play(p.Track - 1)
+	case inpututil.IsKeyJustPressed(ebiten.Key(keymap.Select)):
+		p.Play(p.Track)
+	case inpututil.IsKeyJustPressed(ebiten.Key(keymap.Start)):
+		p.Paused = !p.Paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.Key(p.conf.Input.Fullscreen)) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+
+	if p.Paused {
+		return nil
+	}
+
+	if err := p.StepFrame(); err != nil {
+		slog.Error("Failed to play song", "error", err)
+		p.Paused = true
+		return nil
+	}
+
+	// Fade out, then continue to the next song
+	if track := p.nsf.Tracks[p.Song()]; track.Length != 0 {
+		switch elapsed := p.Elapsed(); {
+		case elapsed >= track.Length+track.Fade:
+			p.Play(p.Track + 1)
+		case elapsed > track.Length:
+			p.setVolume(1 - float64(elapsed-track.Length)/float64(track.Fade))
+		}
+	}
+	return nil
+}
+
+func (p *Player) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	ebitenutil.DebugPrintAt(screen, p.info(), 8, 8)
+}
+
+// info returns the text drawn by the player.
+func (p *Player) info() string {
+	var b strings.Builder
+	for _, s := range []string{p.nsf.Name, p.nsf.Artist, p.nsf.Copyright} {
+		if s != "" {
+			b.WriteString(s + "\n")
+		}
+	}
+
+	_, _ = fmt.Fprintf(&b, "\nTrack %d/%d\n%s\n", p.Track+1, len(p.nsf.Playlist), p.Title())
+	b.WriteString(formatDuration(p.Elapsed()))
+	if length := p.nsf.Tracks[p.Song()].Length; length != 0 {
+		b.WriteString(" / " + formatDuration(length))
+	}
+	if p.Paused {
+		b.WriteString(" (Paused)")
+	}
+	if p.nsf.Chips != 0 {
+		b.WriteString("\nExpansion: " + p.nsf.Chips.String())
+	}
+
+	keymap := p.conf.Input.Player1
+	_, _ = fmt.Fprintf(&b, "\n\n%s/%s: Change track\n%s: Pause\n%s: Restart",
+		ebiten.Key(keymap.Left), ebiten.Key(keymap.Right), ebiten.Key(keymap.Start), ebiten.Key(keymap.Select),
+	)
+	return b.String()
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func (p *Player) Layout(_, _ int) (int, int) {
+	return consts.Width, consts.Height
+}
@@ -0,0 +1,139 @@
+package nsf
+
+import (
+	"testing"
+
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPlayer(t *testing.T, b []byte) *Player {
+	n, err := Parse(b)
+	require.NoError(t, err)
+
+	conf := config.NewDefault()
+	conf.Audio.Enabled = false
+	p, err := NewPlayer(conf, n)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPlayer(t *testing.T) {
+	t.Parallel()
+
+	p := newTestPlayer(t, testNSF([8]byte{}, testProgram))
+	assert.Equal(t, 1, p.Song())
+
+	for range 60 {
+		require.NoError(t, p.StepFrame())
+	}
+	assert.EqualValues(t, 1, p.Bus.RAM[0], "INIT should receive the song number")
+	assert.InDelta(t, 60, p.Bus.RAM[1], 1, "PLAY should run once per frame")
+
+	p.Play(p.Track + 2)
+	assert.Equal(t, 0, p.Song())
+	assert.Zero(t, p.Bus.RAM[1], "RAM should be cleared between songs")
+	for range 2 {
+		require.NoError(t, p.StepFrame())
+	}
+	assert.EqualValues(t, 0, p.Bus.RAM[0])
+	assert.InDelta(t, 1, p.Bus.RAM[1], 1)
+}
+
+func TestPlayer_Bankswitch(t *testing.T) {
+	t.Parallel()
+
+	// Bank 1 is mapped to $8000, then INIT maps bank 2 to $9000 and copies $9000 to $00
+	data := make([]byte, 3*bankSize)
+	copy(data[bankSize:], []byte{
+		0xA9, 0x02, // INIT: LDA #$02
+		0x8D, 0xF9, 0x5F, // STA $5FF9
+		0xAD, 0x00, 0x90, // LDA $9000
+		0x85, 0x00, // STA $00
+		0x60, // RTS
+	})
+	data[2*bankSize] = 0x42
+
+	p := newTestPlayer(t, testNSF([8]byte{1}, data))
+	require.NoError(t, p.StepFrame())
+	assert.EqualValues(t, 0x42, p.Bus.RAM[0])
+	assert.Equal(t, [8]int{1, 2}, p.Bus.Banks)
+}
+
+func TestBus_FDS(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 2*bankSize)
+	data[bankSize] = 0x42
+	b := testNSF([8]byte{0, 0, 0, 0, 0, 0, 1, 0}, data)
+	b[0x7B] = byte(ChipFDS)
+
+	p := newTestPlayer(t, b)
+	assert.EqualValues(t, 0xE8, p.Bus.FDS.EnvelopeSpeed, "FDS audio should be initialized")
+	assert.EqualValues(t, 0x42, p.Bus.ReadMem(0x6000), "$5FF6 should copy bank 1 to $6000")
+
+	p.Bus.WriteMem(0x8000, 0x12)
+	assert.EqualValues(t, 0x12, p.Bus.ReadMem(0x8000), "$8000 should be writable RAM")
+}
+
+func TestBus_N163(t *testing.T) {
+	t.Parallel()
+
+	b := testNSF([8]byte{}, testProgram)
+	b[0x7B] = byte(ChipN163)
+
+	p := newTestPlayer(t, b)
+	p.Bus.WriteMem(0xF800, 0x80)
+	p.Bus.WriteMem(0x4800, 0x12)
+	p.Bus.WriteMem(0x4800, 0x34)
+	assert.EqualValues(t, []byte{0x12, 0x34}, p.Bus.N163RAM[:2])
+
+	p.Bus.WriteMem(0xF800, 0x80)
+	assert.EqualValues(t, 0xFF, p.Bus.ReadMemSafe(0x4800), "safe reads should not advance the address")
+	assert.EqualValues(t, 0x12, p.Bus.ReadMem(0x4800))
+	assert.EqualValues(t, 0x34, p.Bus.ReadMem(0x4800))
+}
+
+func TestBus_MultipleChips(t *testing.T) {
+	t.Parallel()
+
+	t.Run("vrc6 and vrc7", func(t *testing.T) {
+		t.Parallel()
+		b := testNSF([8]byte{}, testProgram)
+		b[0x7B] = byte(ChipVRC6 | ChipVRC7)
+
+		p := newTestPlayer(t, b)
+		p.Bus.WriteMem(0x9000, 0x0F)
+		p.Bus.WriteMem(0x9010, 0x30)
+		p.Bus.WriteMem(0x9030, 0x4F)
+		assert.EqualValues(t, 0xF, p.Bus.VRC6.Pulse[0].Volume)
+		assert.EqualValues(t, 4, p.Bus.VRC7.Channels[0].Instrument)
+	})
+
+	t.Run("fds and n163", func(t *testing.T) {
+		t.Parallel()
+		b := testNSF([8]byte{}, testProgram)
+		b[0x7B] = byte(ChipFDS | ChipN163)
+
+		p := newTestPlayer(t, b)
+		p.Bus.WriteMem(0xF800, 0x85)
+		assert.EqualValues(t, 0x85, p.Bus.N163.Addr)
+		assert.EqualValues(t, 0x85, p.Bus.ReadMem(0xF800), "FDS RAM should also be written")
+	})
+}
+
+func TestPlayer_PAL(t *testing.T) {
+	t.Parallel()
+
+	b := testNSF([8]byte{}, testProgram)
+	b[0x7A] = 1
+	p := newTestPlayer(t, b)
+	assert.Equal(t, consts.RegionPAL, p.Region)
+
+	for range 50 {
+		require.NoError(t, p.StepFrame())
+	}
+	assert.InDelta(t, 50, p.Bus.RAM[1], 1, "PLAY should run once per PAL frame")
+}
@@ -0,0 +1,214 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+//nolint:gochecknoglobals
+var bpsMagic = []byte("BPS1")
+
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// checksumSize is the size of the source, target, and patch CRC32 checksums at the end of BPS and UPS patches.
+const checksumSize = 12
+
+// maxTargetSize limits the target size that is read from BPS and UPS patches.
+const maxTargetSize = 64 << 20
+
+var (
+	ErrInvalidBPS = errors.New("invalid BPS patch")
+	ErrChecksum   = errors.New("checksum mismatch")
+)
+
+// ApplyBPS returns the target of a BPS patch. The source, target, and patch checksums are validated.
+//
+// See [BPS].
+//
+// [BPS]: https://www.romhacking.net/documents/746/
+func ApplyBPS(src, patch []byte) ([]byte, error) {
+	if !bytes.HasPrefix(patch, bpsMagic) || len(patch) < len(bpsMagic)+checksumSize {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidBPS)
+	}
+	if err := verifyChecksums(src, patch); err != nil {
+		return nil, err
+	}
+
+	r := &varintReader{b: patch[len(bpsMagic) : len(patch)-checksumSize]}
+	srcSize, dstSize, metaSize := r.next(), r.next(), r.next()
+	if r.err != nil || metaSize > uint64(len(r.b)) {
+		return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidBPS)
+	}
+	if srcSize != uint64(len(src)) {
+		return nil, fmt.Errorf("%w: expected %d byte source, got %d", ErrInvalidBPS, srcSize, len(src))
+	}
+	if dstSize > maxTargetSize {
+		return nil, fmt.Errorf("%w: target is larger than %d bytes", ErrInvalidBPS, maxTargetSize)
+	}
+	r.b = r.b[metaSize:]
+
+	dst := make([]byte, 0, len(src))
+	var srcOffset, dstOffset int
+	for len(r.b) != 0 {
+		data := r.next()
+		size := int(data>>2) + 1
+		if size <= 0 || uint64(len(dst))+uint64(size) > dstSize {
+			return nil, fmt.Errorf("%w: target is larger than %d bytes", ErrInvalidBPS, dstSize)
+		}
+		switch data & 3 {
+		case bpsSourceRead:
+			start := len(dst)
+			if size > len(src)-start {
+				return nil, fmt.Errorf("%w: source read out of range", ErrInvalidBPS)
+			}
+			dst = append(dst, src[start:start+size]...)
+		case bpsTargetRead:
+			if size > len(r.b) {
+				return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidBPS)
+			}
+			dst = append(dst, r.b[:size]...)
+			r.b = r.b[size:]
+		case bpsSourceCopy:
+			srcOffset += r.nextSigned()
+			if srcOffset < 0 || size > len(src)-srcOffset {
+				return nil, fmt.Errorf("%w: source copy out of range", ErrInvalidBPS)
+			}
+			dst = append(dst, src[srcOffset:srcOffset+size]...)
+			srcOffset += size
+		case bpsTargetCopy:
+			dstOffset += r.nextSigned()
+			if dstOffset < 0 || dstOffset >= len(dst) {
+				return nil, fmt.Errorf("%w: target copy out of range", ErrInvalidBPS)
+			}
+			// Copied one byte at a time, since the range can overlap the bytes being written
+			for range size {
+				dst = append(dst, dst[dstOffset])
+				dstOffset++
+			}
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidBPS)
+		}
+	}
+
+	if uint64(len(dst)) != dstSize {
+		return nil, fmt.Errorf("%w: expected %d byte target, got %d", ErrInvalidBPS, dstSize, len(dst))
+	}
+	if err := verifyTarget(dst, patch); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DiffBPS creates a BPS patch which turns original into modified.
+// Unchanged bytes are read from the source, and everything else is stored in the patch.
+func DiffBPS(original, modified []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(bytes.Clone(bpsMagic))
+	writeVarint(buf, uint64(len(original)))
+	writeVarint(buf, uint64(len(modified)))
+	writeVarint(buf, 0)
+
+	for i := 0; i < len(modified); {
+		same := i < len(original) && original[i] == modified[i]
+		start := i
+		for i < len(modified) && (i < len(original) && original[i] == modified[i]) == same {
+			i++
+		}
+
+		action := uint64(bpsTargetRead)
+		if same {
+			action = bpsSourceRead
+		}
+		writeVarint(buf, uint64(i-start-1)<<2|action)
+		if !same {
+			buf.Write(modified[start:i])
+		}
+	}
+
+	writeChecksums(buf, original, modified)
+	return buf.Bytes(), nil
+}
+
+// varintReader reads the variable-length integers used by BPS and UPS patches.
+type varintReader struct {
+	b   []byte
+	err error
+}
+
+func (r *varintReader) next() uint64 {
+	var data uint64
+	shift := uint64(1)
+	for {
+		if len(r.b) == 0 {
+			r.err = io.ErrUnexpectedEOF
+			return data
+		}
+		x := r.b[0]
+		r.b = r.b[1:]
+		data += uint64(x&0x7F) * shift
+		if x&0x80 != 0 {
+			return data
+		}
+		shift <<= 7
+		data += shift
+	}
+}
+
+func (r *varintReader) nextSigned() int {
+	data := r.next()
+	if data&1 != 0 {
+		return -int(data >> 1)
+	}
+	return int(data >> 1)
+}
+
+func writeVarint(buf *bytes.Buffer, data uint64) {
+	for {
+		x := byte(data & 0x7F)
+		data >>= 7
+		if data == 0 {
+			buf.WriteByte(0x80 | x)
+			return
+		}
+		buf.WriteByte(x)
+		data--
+	}
+}
+
+// verifyChecksums validates the source and patch checksums at the end of a BPS or UPS patch.
+func verifyChecksums(src, patch []byte) error {
+	footer := patch[len(patch)-checksumSize:]
+	if sum := crc32.ChecksumIEEE(patch[:len(patch)-4]); sum != binary.LittleEndian.Uint32(footer[8:]) {
+		return fmt.Errorf("%w: patch is corrupt", ErrChecksum)
+	}
+	if sum := crc32.ChecksumIEEE(src); sum != binary.LittleEndian.Uint32(footer) {
+		return fmt.Errorf("%w: patch was made for a different ROM", ErrChecksum)
+	}
+	return nil
+}
+
+// verifyTarget validates the target checksum at the end of a BPS or UPS patch.
+func verifyTarget(dst, patch []byte) error {
+	footer := patch[len(patch)-checksumSize:]
+	if sum := crc32.ChecksumIEEE(dst); sum != binary.LittleEndian.Uint32(footer[4:]) {
+		return fmt.Errorf("%w: patched ROM is corrupt", ErrChecksum)
+	}
+	return nil
+}
+
+// writeChecksums writes the source, target, and patch checksums.
+func writeChecksums(buf *bytes.Buffer, original, modified []byte) {
+	b := binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(original))
+	b = binary.LittleEndian.AppendUint32(b, crc32.ChecksumIEEE(modified))
+	buf.Write(b)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(buf.Bytes())))
+}
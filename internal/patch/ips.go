@@ -0,0 +1,138 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+//nolint:gochecknoglobals
+var (
+	ipsMagic = []byte("PATCH")
+	ipsEOF   = []byte("EOF")
+)
+
+const (
+	ipsMaxOffset = 0xFFFFFF
+	ipsMaxSize   = 0xFFFF
+	// ipsEOFOffset is the offset that is indistinguishable from the EOF marker.
+	ipsEOFOffset = 0x454F46
+	// ipsRecordOverhead is the size of a record's offset and size.
+	ipsRecordOverhead = 5
+)
+
+var (
+	ErrInvalidIPS  = errors.New("invalid IPS patch")
+	ErrIPSTooLarge = errors.New("data is too large for an IPS patch")
+)
+
+// ApplyIPS returns a copy of src with an IPS patch applied.
+//
+// See [IPS].
+//
+// [IPS]: https://zerosoft.zophar.net/ips.php
+func ApplyIPS(src, patch []byte) ([]byte, error) {
+	if !bytes.HasPrefix(patch, ipsMagic) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidIPS)
+	}
+	patch = patch[len(ipsMagic):]
+
+	dst := bytes.Clone(src)
+	for {
+		if len(patch) < 3 {
+			return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidIPS)
+		}
+		if bytes.Equal(patch[:3], ipsEOF) {
+			patch = patch[3:]
+			break
+		}
+		if len(patch) < ipsRecordOverhead {
+			return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidIPS)
+		}
+
+		offset := int(patch[0])<<16 | int(patch[1])<<8 | int(patch[2])
+		size := int(patch[3])<<8 | int(patch[4])
+		patch = patch[ipsRecordOverhead:]
+
+		var data []byte
+		if size == 0 {
+			// RLE record
+			if len(patch) < 3 {
+				return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidIPS)
+			}
+			size = int(patch[0])<<8 | int(patch[1])
+			data = bytes.Repeat(patch[2:3], size)
+			patch = patch[3:]
+		} else {
+			if len(patch) < size {
+				return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidIPS)
+			}
+			data = patch[:size]
+			patch = patch[size:]
+		}
+
+		if end := offset + size; end > len(dst) {
+			dst = append(dst, make([]byte, end-len(dst))...)
+		}
+		copy(dst[offset:], data)
+	}
+
+	// Truncation extension
+	if len(patch) >= 3 {
+		size := int(patch[0])<<16 | int(patch[1])<<8 | int(patch[2])
+		if size < len(dst) {
+			dst = dst[:size]
+		}
+	}
+	return dst, nil
+}
+
+// DiffIPS creates an IPS patch which turns original into modified.
+func DiffIPS(original, modified []byte) ([]byte, error) {
+	if len(modified) > ipsMaxOffset+1 {
+		return nil, ErrIPSTooLarge
+	}
+
+	var buf bytes.Buffer
+	buf.Write(ipsMagic)
+
+	for i := 0; i < len(modified); {
+		if i < len(original) && original[i] == modified[i] {
+			i++
+			continue
+		}
+
+		start := i
+		if start == ipsEOFOffset {
+			// Start one byte early so the offset is not read as EOF
+			start--
+		}
+
+		// Extend the record until the next run of unchanged bytes that is longer than a new record
+		end, same := i, 0
+		for end < len(modified) && end-start < ipsMaxSize {
+			if end < len(original) && original[end] == modified[end] {
+				same++
+			} else {
+				same = 0
+			}
+			end++
+			if same > ipsRecordOverhead {
+				break
+			}
+		}
+		end -= same
+
+		size := end - start
+		buf.Write([]byte{byte(start >> 16), byte(start >> 8), byte(start), byte(size >> 8), byte(size)})
+		buf.Write(modified[start:end])
+		i = end
+	}
+
+	buf.Write(ipsEOF)
+	if len(modified) < len(original) {
+		size := len(modified)
+		buf.Write([]byte{byte(size >> 16), byte(size >> 8), byte(size)})
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package patch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIPS(t *testing.T) {
+	t.Parallel()
+
+	original := make([]byte, 0x460000)
+	for i := range original {
+		original[i] = byte(i)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{"unchanged", func(b []byte) []byte { return b }},
+		{"single byte", func(b []byte) []byte {
+			b[0x100] ^= 0xFF
+			return b
+		}},
+		{"nearby changes", func(b []byte) []byte {
+			b[0x100] ^= 0xFF
+			b[0x103] ^= 0xFF
+			b[0x200] ^= 0xFF
+			return b
+		}},
+		{"large change", func(b []byte) []byte {
+			copy(b[0x1000:], bytes.Repeat([]byte{0xFF}, 0x20000))
+			return b
+		}},
+		{"EOF offset", func(b []byte) []byte {
+			b[ipsEOFOffset] ^= 0xFF
+			return b
+		}},
+		{"extended", func(b []byte) []byte { return append(b, 1, 2, 3) }},
+		{"truncated", func(b []byte) []byte { return b[:0x1000] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			modified := tt.modify(bytes.Clone(original))
+
+			patch, err := DiffIPS(original, modified)
+			require.NoError(t, err)
+
+			got, err := ApplyIPS(original, patch)
+			require.NoError(t, err)
+			assert.Equal(t, modified, got)
+		})
+	}
+}
+
+func TestApplyIPS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rle", func(t *testing.T) {
+		t.Parallel()
+		patch := []byte("PATCH\x00\x00\x01\x00\x00\x00\x03\xAAEOF")
+		got, err := ApplyIPS([]byte{0, 0, 0, 0, 0}, patch)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0, 0xAA, 0xAA, 0xAA, 0}, got)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		t.Parallel()
+		_, err := ApplyIPS(nil, []byte("PAT"))
+		require.ErrorIs(t, err, ErrInvalidIPS)
+	})
+
+	t.Run("truncated record", func(t *testing.T) {
+		t.Parallel()
+		_, err := ApplyIPS(nil, []byte("PATCH\x00\x00\x01\x00\x05\x01"))
+		require.ErrorIs(t, err, ErrInvalidIPS)
+	})
+}
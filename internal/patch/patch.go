@@ -0,0 +1,92 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a patch file format. The value is the file extension.
+type Format string
+
+const (
+	FormatIPS Format = "ips"
+	FormatBPS Format = "bps"
+	FormatUPS Format = "ups"
+)
+
+// Formats returns all supported patch formats.
+func Formats() []Format {
+	return []Format{FormatIPS, FormatBPS, FormatUPS}
+}
+
+var ErrUnknownFormat = errors.New("unknown patch format")
+
+// FormatFromPath returns the patch format for a file extension.
+func FormatFromPath(path string) (Format, error) {
+	ext := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	for _, f := range Formats() {
+		if ext == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+}
+
+// Apply returns a copy of src with a patch applied. The format is detected from the patch header.
+func Apply(src, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, ipsMagic):
+		return ApplyIPS(src, patch)
+	case bytes.HasPrefix(patch, bpsMagic):
+		return ApplyBPS(src, patch)
+	case bytes.HasPrefix(patch, upsMagic):
+		return ApplyUPS(src, patch)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ApplyFile returns a copy of src with a patch file applied.
+func ApplyFile(src []byte, path string) ([]byte, error) {
+	patch, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := Apply(src, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dst, nil
+}
+
+// Diff creates a patch which turns original into modified.
+func Diff(format Format, original, modified []byte) ([]byte, error) {
+	switch format {
+	case FormatIPS:
+		return DiffIPS(original, modified)
+	case FormatBPS:
+		return DiffBPS(original, modified)
+	case FormatUPS:
+		return DiffUPS(original, modified)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// Find returns a patch file next to a ROM, like game.ips or game.nes.ips for game.nes.
+func Find(rom string) (string, bool) {
+	base := strings.TrimSuffix(rom, filepath.Ext(rom))
+	for _, f := range Formats() {
+		for _, path := range []string{base + "." + string(f), rom + "." + string(f)} {
+			if _, err := os.Stat(path); err == nil {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+package patch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	original := make([]byte, 0x8000)
+	for i := range original {
+		original[i] = byte(i)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{"unchanged", func(b []byte) []byte { return b }},
+		{"single byte", func(b []byte) []byte {
+			b[0x100] ^= 0xFF
+			return b
+		}},
+		{"large change", func(b []byte) []byte {
+			copy(b[0x1000:], bytes.Repeat([]byte{0xFF}, 0x2000))
+			return b
+		}},
+		{"extended", func(b []byte) []byte { return append(b, 1, 0, 3) }},
+		{"truncated", func(b []byte) []byte { return b[:0x1000] }},
+	}
+	for _, format := range Formats() {
+		for _, tt := range tests {
+			t.Run(string(format)+" "+tt.name, func(t *testing.T) {
+				t.Parallel()
+				modified := tt.modify(bytes.Clone(original))
+
+				patch, err := Diff(format, original, modified)
+				require.NoError(t, err)
+
+				got, err := Apply(original, patch)
+				require.NoError(t, err)
+				assert.Equal(t, modified, got)
+			})
+		}
+	}
+}
+
+func TestApplyBPS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+		src := []byte("ABCDEF")
+		want := []byte("DEFxxxxAB")
+
+		buf := bytes.NewBuffer(bytes.Clone(bpsMagic))
+		writeVarint(buf, uint64(len(src)))
+		writeVarint(buf, uint64(len(want)))
+		writeVarint(buf, 0)
+		// SourceCopy 3 bytes from offset 3
+		writeVarint(buf, 2<<2|bpsSourceCopy)
+		writeVarint(buf, 3<<1)
+		// TargetRead 1 byte
+		writeVarint(buf, 0<<2|bpsTargetRead)
+		buf.WriteByte('x')
+		// TargetCopy 3 bytes from the previous byte
+		writeVarint(buf, 2<<2|bpsTargetCopy)
+		writeVarint(buf, 3<<1)
+		// SourceCopy 2 bytes from offset 0
+		writeVarint(buf, 1<<2|bpsSourceCopy)
+		writeVarint(buf, 6<<1|1)
+		writeChecksums(buf, src, want)
+
+		got, err := ApplyBPS(src, buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("wrong source", func(t *testing.T) {
+		t.Parallel()
+		patch, err := DiffBPS([]byte("ABC"), []byte("ABD"))
+		require.NoError(t, err)
+		_, err = ApplyBPS([]byte("XYZ"), patch)
+		require.ErrorIs(t, err, ErrChecksum)
+	})
+
+	t.Run("corrupt patch", func(t *testing.T) {
+		t.Parallel()
+		patch, err := DiffBPS([]byte("ABC"), []byte("ABD"))
+		require.NoError(t, err)
+		patch[len(bpsMagic)+3] ^= 0xFF
+		_, err = ApplyBPS([]byte("ABC"), patch)
+		require.ErrorIs(t, err, ErrChecksum)
+	})
+
+	t.Run("target too large", func(t *testing.T) {
+		t.Parallel()
+		src := []byte("ABC")
+		buf := bytes.NewBuffer(bytes.Clone(bpsMagic))
+		writeVarint(buf, uint64(len(src)))
+		writeVarint(buf, maxTargetSize+1)
+		writeVarint(buf, 0)
+		writeChecksums(buf, src, nil)
+		_, err := ApplyBPS(src, buf.Bytes())
+		require.ErrorIs(t, err, ErrInvalidBPS)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		t.Parallel()
+		_, err := ApplyBPS(nil, []byte("BPS"))
+		require.ErrorIs(t, err, ErrInvalidBPS)
+	})
+}
+
+func TestApplyUPS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wrong source", func(t *testing.T) {
+		t.Parallel()
+		patch, err := DiffUPS([]byte("ABC"), []byte("ABD"))
+		require.NoError(t, err)
+		_, err = ApplyUPS([]byte("XYZ"), patch)
+		require.ErrorIs(t, err, ErrChecksum)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		t.Parallel()
+		_, err := ApplyUPS(nil, []byte("UPS"))
+		require.ErrorIs(t, err, ErrInvalidUPS)
+	})
+}
+
+func TestApply_unknown(t *testing.T) {
+	t.Parallel()
+	_, err := Apply(nil, []byte("ABCD"))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rom := filepath.Join(dir, "game.nes")
+	_, ok := Find(rom)
+	assert.False(t, ok)
+
+	want := filepath.Join(dir, "game.bps")
+	require.NoError(t, os.WriteFile(want, nil, 0o644))
+	got, ok := Find(rom)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,101 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+//nolint:gochecknoglobals
+var upsMagic = []byte("UPS1")
+
+var ErrInvalidUPS = errors.New("invalid UPS patch")
+
+// ApplyUPS returns the target of a UPS patch. The source, target, and patch checksums are validated.
+//
+// See [UPS].
+//
+// [UPS]: https://www.romhacking.net/documents/392/
+func ApplyUPS(src, patch []byte) ([]byte, error) {
+	if !bytes.HasPrefix(patch, upsMagic) || len(patch) < len(upsMagic)+checksumSize {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidUPS)
+	}
+	if err := verifyChecksums(src, patch); err != nil {
+		return nil, err
+	}
+
+	r := &varintReader{b: patch[len(upsMagic) : len(patch)-checksumSize]}
+	srcSize, dstSize := r.next(), r.next()
+	if r.err != nil {
+		return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidUPS)
+	}
+	if srcSize != uint64(len(src)) {
+		return nil, fmt.Errorf("%w: expected %d byte source, got %d", ErrInvalidUPS, srcSize, len(src))
+	}
+	if dstSize > maxTargetSize {
+		return nil, fmt.Errorf("%w: target is larger than %d bytes", ErrInvalidUPS, maxTargetSize)
+	}
+
+	dst := make([]byte, dstSize)
+	copy(dst, src)
+	var offset uint64
+	for len(r.b) != 0 {
+		offset += r.next()
+		if r.err != nil {
+			return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidUPS)
+		}
+
+		// Bytes are XORed with the source until a zero byte
+		for {
+			if len(r.b) == 0 {
+				return nil, fmt.Errorf("%w: unexpected end of patch", ErrInvalidUPS)
+			}
+			x := r.b[0]
+			r.b = r.b[1:]
+			if x == 0 {
+				break
+			}
+			if offset < dstSize {
+				dst[offset] ^= x
+			}
+			offset++
+		}
+		offset++
+	}
+
+	if err := verifyTarget(dst, patch); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// DiffUPS creates a UPS patch which turns original into modified.
+func DiffUPS(original, modified []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(bytes.Clone(upsMagic))
+	writeVarint(buf, uint64(len(original)))
+	writeVarint(buf, uint64(len(modified)))
+
+	xor := func(i int) byte {
+		if i < len(original) {
+			return original[i] ^ modified[i]
+		}
+		return modified[i]
+	}
+
+	var last int
+	for i := 0; i < len(modified); i++ {
+		if xor(i) == 0 {
+			continue
+		}
+
+		writeVarint(buf, uint64(i-last))
+		for ; i < len(modified) && xor(i) != 0; i++ {
+			buf.WriteByte(xor(i))
+		}
+		buf.WriteByte(0)
+		last = i + 1
+	}
+
+	writeChecksums(buf, original, modified)
+	return buf.Bytes(), nil
+}
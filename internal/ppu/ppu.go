@@ -27,7 +27,7 @@ func New(conf *config.Config, mapper cartridge.Mapper) *PPU {
 	if conf.UI.RemoveSpriteLimit {
 		spriteLimit = consts.PPUOAMSize / 4
 	}
-	return &PPU{
+	p := &PPU{
 		offsets:       rect.Min,
 		mapper:        mapper,
 		image:         image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy())),
@@ -41,12 +41,27 @@ func New(conf *config.Config, mapper cartridge.Mapper) *PPU {
 			Indexes:    make([]byte, spriteLimit),
 		},
 	}
+	p.SetRegion(consts.RegionNTSC)
+	return p
+}
+
+// SetRegion sets the number of scanlines and the vblank timing.
+func (p *PPU) SetRegion(region consts.Region) {
+	p.region = region
+	p.preRenderLine = region.Scanlines() - 1
+	p.vblankLine = region.VBlankScanline()
+	p.UpdatePalette(p.Mask.Get())
 }
 
 type PPU struct {
-	mapper  cartridge.Mapper
-	cpu     CPU
-	offsets image.Point
+	mapper     cartridge.Mapper
+	cpu        CPU
+	chrWatcher CHRWatcher
+	offsets    image.Point
+
+	region        consts.Region
+	preRenderLine int
+	vblankLine    int
 
 	Ctrl      registers.Control
 	Mask      registers.Mask
@@ -105,6 +120,18 @@ func (p *PPU) WriteMask(data byte) {
 }
 
 func (p *PPU) UpdatePalette(data byte) {
+	if p.region != consts.RegionNTSC {
+		// PAL and Dendy swap the red and green emphasis bits
+		red, green := data&registers.MaskEmphasizeRed != 0, data&registers.MaskEmphasizeGreen != 0
+		data &^= registers.MaskEmphasizeRed | registers.MaskEmphasizeGreen
+		if red {
+			data |= registers.MaskEmphasizeGreen
+		}
+		if green {
+			data |= registers.MaskEmphasizeRed
+		}
+	}
+
 	switch data & (registers.MaskEmphasizeRed | registers.MaskEmphasizeGreen | registers.MaskEmphasizeBlue) {
 	case 0:
 		p.systemPalette = &palette.Default
@@ -165,7 +192,7 @@ func (p *PPU) ReadStatus() byte {
 	p.VblRace = false
 	p.updateNMI()
 	p.AddrLatch = false
-	if p.Scanline == 241 && p.Cycles == 0 {
+	if p.Scanline == p.vblankLine && p.Cycles == 0 {
 		p.VblRace = true
 	}
 	return status
@@ -180,6 +207,10 @@ func (p *PPU) WriteData(data byte) {
 			p.mapper.WriteMem(addr, data)
 		}
 	case 0x2000 <= addr && addr < 0x3F00:
+		if mapper, ok := p.mapper.(cartridge.MapperNametable); ok {
+			mapper.WriteNametable(&p.VRAM, addr, data)
+			break
+		}
 		addr := p.MirrorVRAMAddr(addr)
 		p.VRAM[addr] = data
 	case 0x3F00 <= addr && addr < 0x4000:
@@ -193,9 +224,36 @@ func (p *PPU) WriteData(data byte) {
 	}
 }
 
+// CHRWatcher is notified of pattern table reads.
+type CHRWatcher interface {
+	OnCHRDraw(addr uint16)
+	OnCHRRead(addr uint16)
+}
+
+// SetCHRWatcher sets the pattern table watcher. Pass nil to disable watching.
+func (p *PPU) SetCHRWatcher(w CHRWatcher) {
+	p.chrWatcher = w
+}
+
+// fetchPattern reads a pattern table byte for rendering.
+func (p *PPU) fetchPattern(kind cartridge.FetchKind, addr uint16) byte {
+	if p.chrWatcher != nil {
+		p.chrWatcher.OnCHRDraw(addr)
+	}
+	return p.renderFetch(kind, addr)
+}
+
+// renderFetch reads a byte for rendering, letting the mapper see the kind of fetch.
+func (p *PPU) renderFetch(kind cartridge.FetchKind, addr uint16) byte {
+	if mapper, ok := p.mapper.(cartridge.MapperRenderFetch); ok {
+		return mapper.RenderFetch(&p.VRAM, kind, addr)
+	}
+	return p.ReadDataAddr(addr)
+}
+
 func (p *PPU) ReadData() byte {
 	addr := p.Addr.Get() % 0x4000
-	if p.Mask.RenderingEnabled() && (p.Scanline == 261 || p.Scanline < 240) {
+	if p.Mask.RenderingEnabled() && (p.Scanline == p.preRenderLine || p.Scanline < 240) {
 		// If rendering enabled, increment Coarse X and Y
 		// https://www.nesdev.org/wiki/PPU_scrolling#$2007_reads_and_writes
 		p.Addr.IncrementX()
@@ -205,6 +263,10 @@ func (p *PPU) ReadData() byte {
 		p.Addr.Increment(p.Ctrl.VRAMAddr())
 	}
 
+	if addr < 0x2000 && p.chrWatcher != nil {
+		p.chrWatcher.OnCHRRead(addr)
+	}
+
 	val := p.ReadDataAddr(addr)
 	if addr < 0x3F00 {
 		val, p.ReadBuf = p.ReadBuf, val
@@ -227,6 +289,9 @@ func (p *PPU) ReadDataAddr(addr uint16) byte {
 	case addr < 0x2000:
 		return p.mapper.ReadMem(addr)
 	case 0x2000 <= addr && addr < 0x3F00:
+		if mapper, ok := p.mapper.(cartridge.MapperNametable); ok {
+			return mapper.ReadNametable(&p.VRAM, addr)
+		}
 		addr := p.MirrorVRAMAddr(addr)
 		return p.VRAM[addr]
 	case 0x3F00 <= addr && addr < 0x4000:
@@ -316,8 +381,8 @@ func (p *PPU) tick() {
 		}
 	}
 
-	if p.Mask.RenderingEnabled() {
-		if p.OddFrame && p.Scanline == 261 && p.Cycles == 339 {
+	if p.Mask.RenderingEnabled() && p.region.SkipsOddFrameCycle() {
+		if p.OddFrame && p.Scanline == p.preRenderLine && p.Cycles == 339 {
 			p.Cycles = 0
 			p.Scanline = 0
 			p.OddFrame = !p.OddFrame
@@ -329,7 +394,7 @@ func (p *PPU) tick() {
 		p.Cycles++
 	} else {
 		p.Cycles = 0
-		if p.Scanline < 261 {
+		if p.Scanline < p.preRenderLine {
 			p.Scanline++
 		} else {
 			p.Scanline = 0
@@ -341,7 +406,7 @@ func (p *PPU) tick() {
 func (p *PPU) Step(render bool) {
 	p.tick()
 
-	preLine := p.Scanline == 261
+	preLine := p.Scanline == p.preRenderLine
 	visibleLine := p.Scanline < 240
 	renderLine := preLine || visibleLine
 	visibleCycle := 1 <= p.Cycles && p.Cycles <= 256
@@ -399,7 +464,7 @@ func (p *PPU) Step(render bool) {
 
 	switch p.Cycles {
 	case 1:
-		if p.Scanline == 241 && !p.VblRace {
+		if p.Scanline == p.vblankLine && !p.VblRace {
 			p.Status.Vblank = true
 			p.updateNMI()
 			p.RenderDone = true
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/consts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -194,3 +196,39 @@ func TestCPU_OamDma(t *testing.T) {
 	ppu.WriteOamAddr(0x11)
 	assert.EqualValues(t, 0x66, ppu.ReadOam())
 }
+
+func TestPPU_SetRegion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		region    consts.Region
+		scanlines int
+		vblank    int
+	}{
+		{consts.RegionNTSC, 262, 241},
+		{consts.RegionPAL, 312, 241},
+		{consts.RegionDendy, 312, 291},
+	}
+	for _, tt := range tests {
+		t.Run(tt.region.String(), func(t *testing.T) {
+			t.Parallel()
+			cart := cartridge.New()
+			cart.CHR = make([]byte, 0x2000)
+			ppu := New(config.NewDefault(), cartridge.NewMapper2(cart, false))
+			ppu.SetRegion(tt.region)
+
+			// Run to the start of vblank, then count the cycles in one frame
+			for !ppu.Status.Vblank {
+				ppu.Step(false)
+			}
+			assert.Equal(t, tt.vblank, ppu.Scanline)
+
+			var cycles int
+			ppu.RenderDone = false
+			for !ppu.RenderDone {
+				ppu.Step(false)
+				cycles++
+			}
+			assert.Equal(t, 341*tt.scanlines, cycles)
+		})
+	}
+}
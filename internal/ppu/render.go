@@ -2,12 +2,27 @@ package ppu
 
 import (
 	"image"
+	"image/color"
 )
 
 func (p *PPU) Image() *image.RGBA {
 	return p.image
 }
 
+// BeamPosition returns the scanline and dot that the PPU is drawing.
+func (p *PPU) BeamPosition() (int, int) {
+	return p.Scanline, p.Cycles
+}
+
+// Pixel returns the color at a screen position, and false when the position is cropped by overscan.
+func (p *PPU) Pixel(x, y int) (color.RGBA, bool) {
+	pt := image.Pt(x, y).Sub(p.offsets)
+	if !pt.In(p.image.Rect) {
+		return color.RGBA{}, false
+	}
+	return p.image.RGBAAt(pt.X, pt.Y), true
+}
+
 func (p *PPU) renderPixel(render bool) {
 	x := p.Cycles - 1
 	y := p.Scanline
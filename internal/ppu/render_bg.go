@@ -1,5 +1,7 @@
 package ppu
 
+import "gabe565.com/gones/internal/cartridge"
+
 type BgTile struct {
 	NametableByte byte
 	AttrByte      byte
@@ -10,7 +12,7 @@ type BgTile struct {
 
 func (p *PPU) fetchNametableByte() byte {
 	addr := 0x2000 | p.Addr.Get()&0xFFF
-	return p.ReadDataAddr(addr)
+	return p.renderFetch(cartridge.FetchNametable, addr)
 }
 
 func (p *PPU) fetchAttrTableByte() byte {
@@ -22,7 +24,7 @@ func (p *PPU) fetchAttrTableByte() byte {
 		addr |= 1 << 10
 	}
 	var attrByte byte
-	attrByte = p.ReadDataAddr(addr)
+	attrByte = p.renderFetch(cartridge.FetchAttribute, addr)
 	if p.Addr.CoarseY&2 != 0 {
 		attrByte >>= 4
 	}
@@ -39,7 +41,7 @@ func (p *PPU) fetchLoTileByte() byte {
 	if p.Ctrl.BgTileSelect {
 		addr += 1 << 12
 	}
-	return p.ReadDataAddr(addr)
+	return p.fetchPattern(cartridge.FetchBackground, addr)
 }
 
 func (p *PPU) fetchHiTileByte() byte {
@@ -47,7 +49,7 @@ func (p *PPU) fetchHiTileByte() byte {
 	if p.Ctrl.BgTileSelect {
 		addr += 1 << 12
 	}
-	return p.ReadDataAddr(addr)
+	return p.fetchPattern(cartridge.FetchBackground, addr)
 }
 
 func (p *PPU) storeTileData() {
@@ -1,6 +1,7 @@
 package ppu
 
 import (
+	"gabe565.com/gones/internal/cartridge"
 	"gabe565.com/gones/internal/consts"
 	"github.com/vmihailenco/msgpack/v5"
 )
@@ -117,8 +118,8 @@ func (p *PPU) fetchSpritePattern(tile, attributes byte, row int) uint32 {
 	}
 
 	a := (attributes & 3) << 2
-	tileLo := p.ReadDataAddr(addr)
-	tileHi := p.ReadDataAddr(addr + 8)
+	tileLo := p.fetchPattern(cartridge.FetchSprite, addr)
+	tileHi := p.fetchPattern(cartridge.FetchSprite, addr+8)
 	var data uint32
 
 	for range 8 {
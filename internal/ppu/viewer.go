@@ -0,0 +1,168 @@
+package ppu
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// The viewer images below are rendered from live PPU and mapper state for debugging.
+// They read memory without any side effects, so they can be called at any time.
+
+const (
+	spriteCellWidth  = 10
+	spriteCellHeight = 18
+	paletteSwatch    = 16
+)
+
+func (p *PPU) viewerColor(idx byte) color.RGBA {
+	idx = p.readPalette(uint16(idx)) % 64
+	if p.Mask.Grayscale {
+		idx &= 0x30
+	}
+	return p.systemPalette.RGBA[idx]
+}
+
+// drawTile draws an 8x8 tile from a pattern table address.
+// Pixels with a color of 0 are skipped when transparent is true.
+func (p *PPU) drawTile(img *image.RGBA, x, y int, addr uint16, attr byte, flipX, flipY, transparent bool) {
+	for row := range 8 {
+		srcRow := row
+		if flipY {
+			srcRow = 7 - row
+		}
+		lo := p.ReadDataAddr(addr + uint16(srcRow))
+		hi := p.ReadDataAddr(addr + uint16(srcRow) + 8)
+
+		for col := range 8 {
+			bit := 7 - col
+			if flipX {
+				bit = col
+			}
+			pixel := (lo>>bit)&1 | (hi>>bit)&1<<1
+			if pixel == 0 && transparent {
+				continue
+			}
+			idx := attr | pixel
+			if pixel == 0 {
+				// Use the universal background color
+				idx = 0
+			}
+			img.SetRGBA(x+col, y+row, p.viewerColor(idx))
+		}
+	}
+}
+
+// PatternTablesImage renders both pattern tables side by side using a palette from 0-7.
+func (p *PPU) PatternTablesImage(palette byte) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 256, 128))
+	attr := (palette & 7) << 2
+	for table := range uint16(2) {
+		for tile := range uint16(256) {
+			x := int(table)*128 + int(tile%16)*8
+			y := int(tile/16) * 8
+			p.drawTile(img, x, y, table*0x1000+tile*16, attr, false, false, false)
+		}
+	}
+	return img
+}
+
+// NametablesImage renders all four nametables with the current scroll viewport outlined.
+func (p *PPU) NametablesImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 512, 480))
+
+	var patternBase uint16
+	if p.Ctrl.BgTileSelect {
+		patternBase = 0x1000
+	}
+
+	for nt := range uint16(4) {
+		base := 0x2000 + nt*0x400
+		for tileY := range uint16(30) {
+			for tileX := range uint16(32) {
+				tile := p.ReadDataAddr(base + tileY*32 + tileX)
+
+				attr := p.ReadDataAddr(base + 0x3C0 + tileY/4*8 + tileX/4)
+				if tileY&2 != 0 {
+					attr >>= 4
+				}
+				if tileX&2 != 0 {
+					attr >>= 2
+				}
+				attr = (attr & 3) << 2
+
+				x := int(nt%2)*256 + int(tileX)*8
+				y := int(nt/2)*240 + int(tileY)*8
+				p.drawTile(img, x, y, patternBase+uint16(tile)*16, attr, false, false, false)
+			}
+		}
+	}
+
+	// Outline the visible area, wrapping around the edges
+	scrollX := int(p.TmpAddr.CoarseX)*8 + int(p.FineX)
+	if p.TmpAddr.NametableX {
+		scrollX += 256
+	}
+	scrollY := int(p.TmpAddr.CoarseY)*8 + int(p.TmpAddr.FineY)
+	if p.TmpAddr.NametableY {
+		scrollY += 240
+	}
+	outline := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	for i := range 256 {
+		x := (scrollX + i) % 512
+		img.SetRGBA(x, scrollY%480, outline)
+		img.SetRGBA(x, (scrollY+239)%480, outline)
+	}
+	for i := range 240 {
+		y := (scrollY + i) % 480
+		img.SetRGBA(scrollX%512, y, outline)
+		img.SetRGBA((scrollX+255)%512, y, outline)
+	}
+
+	return img
+}
+
+// SpritesImage renders the 64 sprites from OAM in an 8x8 grid.
+// Each cell is tall enough for 8x16 sprites.
+func (p *PPU) SpritesImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 8*spriteCellWidth, 8*spriteCellHeight))
+	draw.Draw(img, img.Rect, image.NewUniform(p.viewerColor(0)), image.Point{}, draw.Src)
+
+	for i := range 64 {
+		tile := p.OAM[i*4+1]
+		attributes := p.OAM[i*4+2]
+		attr := 0x10 | (attributes&3)<<2
+		flipX := attributes&0x40 != 0
+		flipY := attributes&0x80 != 0
+
+		x := (i%8)*spriteCellWidth + 1
+		y := (i/8)*spriteCellHeight + 1
+
+		if p.Ctrl.SpriteHeight {
+			addr := 0x1000*uint16(tile&1) + uint16(tile&0xFE)*16
+			top, bottom := addr, addr+16
+			if flipY {
+				top, bottom = bottom, top
+			}
+			p.drawTile(img, x, y, top, attr, flipX, flipY, true)
+			p.drawTile(img, x, y+8, bottom, attr, flipX, flipY, true)
+		} else {
+			addr := p.Ctrl.SpriteTileAddr() + uint16(tile)*16
+			p.drawTile(img, x, y, addr, attr, flipX, flipY, true)
+		}
+	}
+	return img
+}
+
+// PaletteImage renders the 32 palette entries in two rows.
+// The top row is the background palette, and the bottom row is the sprite palette.
+func (p *PPU) PaletteImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 16*paletteSwatch, 2*paletteSwatch))
+	for i := range byte(32) {
+		x := int(i%16) * paletteSwatch
+		y := int(i/16) * paletteSwatch
+		rect := image.Rect(x, y, x+paletteSwatch, y+paletteSwatch)
+		draw.Draw(img, rect, image.NewUniform(p.viewerColor(i)), image.Point{}, draw.Src)
+	}
+	return img
+}
@@ -0,0 +1,55 @@
+package ppu
+
+import (
+	"testing"
+
+	"gabe565.com/gones/internal/ppu/palette"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPPU_PatternTablesImage(t *testing.T) {
+	t.Parallel()
+
+	ppu, cart := stubPPU()
+	cart.CHR = make([]byte, 0x2000)
+	ppu.systemPalette = &palette.Default
+	ppu.Palette = [0x20]byte{0x0F, 0x01, 0x02, 0x03}
+	// Tile 1, row 0: color 1, 2, 3, 0
+	cart.CHR[16] = 0b10100000
+	cart.CHR[24] = 0b01100000
+
+	img := ppu.PatternTablesImage(0)
+	assert.Equal(t, 256, img.Rect.Dx())
+	assert.Equal(t, 128, img.Rect.Dy())
+	assert.Equal(t, palette.Default.RGBA[0x01], img.RGBAAt(8, 0))
+	assert.Equal(t, palette.Default.RGBA[0x02], img.RGBAAt(9, 0))
+	assert.Equal(t, palette.Default.RGBA[0x03], img.RGBAAt(10, 0))
+	assert.Equal(t, palette.Default.RGBA[0x0F], img.RGBAAt(11, 0))
+}
+
+func TestPPU_PaletteImage(t *testing.T) {
+	t.Parallel()
+
+	ppu, _ := stubPPU()
+	ppu.systemPalette = &palette.Default
+	for i := range ppu.Palette {
+		ppu.Palette[i] = byte(i)
+	}
+
+	img := ppu.PaletteImage()
+	assert.Equal(t, 256, img.Rect.Dx())
+	assert.Equal(t, 32, img.Rect.Dy())
+	assert.Equal(t, palette.Default.RGBA[0x05], img.RGBAAt(5*paletteSwatch, 0))
+	assert.Equal(t, palette.Default.RGBA[0x15], img.RGBAAt(5*paletteSwatch, paletteSwatch))
+}
+
+func TestPPU_SpritesImage(t *testing.T) {
+	t.Parallel()
+
+	ppu, cart := stubPPU()
+	cart.CHR = make([]byte, 0x2000)
+	ppu.systemPalette = &palette.Default
+	img := ppu.SpritesImage()
+	assert.Equal(t, 8*spriteCellWidth, img.Rect.Dx())
+	assert.Equal(t, 8*spriteCellHeight, img.Rect.Dy())
+}
@@ -0,0 +1,98 @@
+package tracer
+
+import (
+	"fmt"
+	"strings"
+
+	"gabe565.com/gones/internal/cpu"
+	"gabe565.com/gones/internal/memory"
+)
+
+// State is a snapshot of the console before an instruction runs.
+// Custom templates can reference any field or method.
+type State struct {
+	PC       uint16
+	A        byte
+	X        byte
+	Y        byte
+	P        byte
+	SP       byte
+	Cycles   uint
+	Scanline int
+	Dot      int
+	// Bank is the 16 KiB PRG ROM bank that PC is in, or -1 when PC is not in PRG ROM.
+	Bank int
+
+	cpu *cpu.CPU
+	mem memory.ReadSafe
+}
+
+// NewState captures the current CPU registers.
+func NewState(c *cpu.CPU, mem memory.ReadSafe, scanline, dot, bank int) State {
+	return State{
+		PC:       c.ProgramCounter,
+		A:        c.Accumulator,
+		X:        c.RegisterX,
+		Y:        c.RegisterY,
+		P:        c.Status.Get(),
+		SP:       c.StackPointer,
+		Cycles:   c.GetCycles(),
+		Scanline: scanline,
+		Dot:      dot,
+		Bank:     bank,
+		cpu:      c,
+		mem:      mem,
+	}
+}
+
+// Disassembly returns the address, bytes, and instruction at PC.
+func (s State) Disassembly() string {
+	text, _ := cpu.Disassemble(s.mem, s.PC)
+	return text
+}
+
+// Instruction returns only the instruction at PC, for example "LDA #$01".
+func (s State) Instruction() string {
+	text, _ := cpu.Disassemble(s.mem, s.PC)
+	// Skip the address and bytes columns
+	return strings.TrimSpace(text[15:])
+}
+
+// Bytes returns the instruction bytes at PC as hex.
+func (s State) Bytes() string {
+	_, length := cpu.Disassemble(s.mem, s.PC)
+	var b strings.Builder
+	for i := range length {
+		if i != 0 {
+			b.WriteByte(' ')
+		}
+		_, _ = fmt.Fprintf(&b, "%02X", s.mem.ReadMemSafe(s.PC+i))
+	}
+	return b.String()
+}
+
+// Flags returns the status register as letters. Set flags are uppercase.
+func (s State) Flags() string {
+	const names = "nvubdizc"
+	flags := []byte(names)
+	for i := range flags {
+		if s.P&(0x80>>i) != 0 {
+			flags[i] -= 'a' - 'A'
+		}
+	}
+	return string(flags)
+}
+
+// Nestest returns the line in the same format as the nestest.nes log.
+func (s State) Nestest() string {
+	return fmt.Sprintf("%s PPU:%3d,%3d CYC:%d", s.cpu.Trace(), s.Scanline, s.Dot, s.Cycles)
+}
+
+// Mesen returns the line in a format similar to Mesen's default trace logger.
+func (s State) Mesen() string {
+	return fmt.Sprintf("%-40s A:%02X X:%02X Y:%02X S:%02X P:%s V:%-3d H:%-3d Cyc:%d",
+		s.Disassembly(),
+		s.A, s.X, s.Y, s.SP, s.Flags(),
+		s.Scanline, s.Dot, s.Cycles,
+	)
+}
@@ -0,0 +1,148 @@
+package tracer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/memory"
+)
+
+const (
+	FormatNestest = "nestest"
+	FormatMesen   = "mesen"
+)
+
+var ErrInvalidFormat = errors.New("invalid trace format")
+
+// Options configure which instructions are logged and how.
+type Options struct {
+	// Format is "nestest", "mesen", or a Go template which is executed with a [State].
+	Format string
+
+	// Start and End are an inclusive PC range to log.
+	Start uint16
+	End   uint16
+	// Banks limits logging to specific PRG ROM banks. All banks are logged when empty.
+	Banks []int
+
+	// StartOn delays logging until the condition is met.
+	StartOn *debugger.Condition
+	// StopOn ends logging once the condition is met.
+	StopOn *debugger.Condition
+}
+
+// Logger writes a line for each executed instruction.
+type Logger struct {
+	w      io.Writer
+	buf    *bufio.Writer
+	closer io.Closer
+
+	opts   Options
+	format func(State) string
+	active bool
+}
+
+// New creates a logger which writes to w without buffering.
+func New(w io.Writer, opts Options) (*Logger, error) {
+	l := &Logger{
+		w:      w,
+		opts:   opts,
+		active: opts.StartOn == nil,
+	}
+
+	switch opts.Format {
+	case "", FormatNestest:
+		l.format = State.Nestest
+	case FormatMesen:
+		l.format = State.Mesen
+	default:
+		if !strings.Contains(opts.Format, "{{") {
+			return nil, fmt.Errorf("%w %q: expected %s, %s, or a template", ErrInvalidFormat, opts.Format, FormatNestest, FormatMesen)
+		}
+		tmpl, err := template.New("trace").Parse(opts.Format)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+		var b strings.Builder
+		l.format = func(s State) string {
+			b.Reset()
+			if err := tmpl.Execute(&b, s); err != nil {
+				return err.Error()
+			}
+			return b.String()
+		}
+	}
+
+	return l, nil
+}
+
+// Create creates a logger which writes to a buffered file.
+func Create(path string, opts Options) (*Logger, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufio.NewWriterSize(f, 64*1024)
+	l, err := New(buf, opts)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	l.buf = buf
+	l.closer = f
+	return l, nil
+}
+
+// Enabled reports whether the instruction at pc should be logged.
+// Start and stop conditions are evaluated here, so it must be called once per instruction.
+func (l *Logger) Enabled(pc uint16, bank int, mem memory.ReadSafe) bool {
+	if !l.active {
+		if l.opts.StartOn == nil || !l.opts.StartOn.Met(pc, mem) {
+			return false
+		}
+		l.active = true
+	}
+	if l.opts.StopOn != nil && l.opts.StopOn.Met(pc, mem) {
+		l.active = false
+		return false
+	}
+
+	if pc < l.opts.Start || pc > l.opts.End {
+		return false
+	}
+	if len(l.opts.Banks) != 0 && !slices.Contains(l.opts.Banks, bank) {
+		return false
+	}
+	return true
+}
+
+// Log writes a line for the state.
+func (l *Logger) Log(s State) error {
+	_, err := io.WriteString(l.w, l.format(s)+"\n")
+	return err
+}
+
+// Flush writes any buffered lines.
+func (l *Logger) Flush() error {
+	if l.buf == nil {
+		return nil
+	}
+	return l.buf.Flush()
+}
+
+// Close flushes the logger and closes the file if it was created with [Create].
+func (l *Logger) Close() error {
+	err := l.Flush()
+	if l.closer != nil {
+		err = errors.Join(err, l.closer.Close())
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package tracer
+
+import (
+	"strings"
+	"testing"
+
+	"gabe565.com/gones/internal/apu"
+	"gabe565.com/gones/internal/bus"
+	"gabe565.com/gones/internal/cartridge"
+	"gabe565.com/gones/internal/config"
+	"gabe565.com/gones/internal/cpu"
+	"gabe565.com/gones/internal/debugger"
+	"gabe565.com/gones/internal/ppu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubCPU(program []byte) (*cpu.CPU, *bus.Bus) {
+	conf := config.NewDefault()
+	cart := cartridge.FromBytes(program)
+	mapper := cartridge.NewMapper2(cart, false)
+	b := bus.New(conf, mapper, ppu.New(conf, mapper), apu.New(conf))
+	return cpu.New(b), b
+}
+
+func runTrace(t *testing.T, opts Options, program []byte, steps int) []string {
+	c, b := stubCPU(program)
+	var out strings.Builder
+	l, err := New(&out, opts)
+	require.NoError(t, err)
+
+	for range steps {
+		if l.Enabled(c.ProgramCounter, 0, b) {
+			require.NoError(t, l.Log(NewState(c, b, 0, 21, 0)))
+		}
+		c.Step()
+		require.NoError(t, c.StepErr)
+	}
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+// LDX #$01; DEX; DEY; LDA #$10
+var program = []byte{0xA2, 0x01, 0xCA, 0x88, 0xA9, 0x10}
+
+func TestLogger_Formats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nestest", func(t *testing.T) {
+		t.Parallel()
+		lines := runTrace(t, Options{End: 0xFFFF}, program, 1)
+		assert.Equal(t,
+			"8600  A2 01     LDX #$01                        A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 21 CYC:7",
+			lines[0],
+		)
+	})
+
+	t.Run("mesen", func(t *testing.T) {
+		t.Parallel()
+		lines := runTrace(t, Options{Format: FormatMesen, End: 0xFFFF}, program, 1)
+		assert.Equal(t,
+			"8600  A2 01     LDX #$01                 A:00 X:00 Y:00 S:FD P:nvUbdIzc V:0   H:21  Cyc:7",
+			lines[0],
+		)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{Format: `{{printf "%04X" .PC}} {{.Instruction}} DOT:{{.Dot}}`, End: 0xFFFF}
+		lines := runTrace(t, opts, program, 2)
+		assert.Equal(t, []string{"8600 LDX #$01 DOT:21", "8602 DEX DOT:21"}, lines)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := New(&strings.Builder{}, Options{Format: "fceux"})
+		require.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
+
+func TestLogger_Filters(t *testing.T) {
+	t.Parallel()
+
+	format := `{{printf "%04X" .PC}}`
+
+	t.Run("range", func(t *testing.T) {
+		t.Parallel()
+		lines := runTrace(t, Options{Format: format, Start: 0x8602, End: 0x8603}, program, 4)
+		assert.Equal(t, []string{"8602", "8603"}, lines)
+	})
+
+	t.Run("bank", func(t *testing.T) {
+		t.Parallel()
+		lines := runTrace(t, Options{Format: format, End: 0xFFFF, Banks: []int{1}}, program, 4)
+		assert.Equal(t, []string{""}, lines)
+	})
+
+	t.Run("start and stop", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{
+			Format:  format,
+			End:     0xFFFF,
+			StartOn: &debugger.Condition{PC: true, Value: 0x8602},
+			StopOn:  &debugger.Condition{PC: true, Value: 0x8604},
+		}
+		lines := runTrace(t, opts, program, 4)
+		assert.Equal(t, []string{"8602", "8603"}, lines)
+	})
+}
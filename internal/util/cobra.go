@@ -3,5 +3,9 @@ package util
 import "github.com/spf13/cobra"
 
 func CompleteROM(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-	return []string{"nes"}, cobra.ShellCompDirectiveFilterFileExt
+	return []string{"nes", "unf", "unif", "fds", "qd", "nsf", "nsfe", "zip", "gz"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+func CompletePatch(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{"ips", "bps", "ups"}, cobra.ShellCompDirectiveFilterFileExt
 }
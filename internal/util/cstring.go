@@ -0,0 +1,11 @@
+package util
+
+import "bytes"
+
+// CString returns a string that ends at the first null byte.
+func CString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i != -1 {
+		b = b[:i]
+	}
+	return string(b)
+}